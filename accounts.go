@@ -0,0 +1,92 @@
+package namesilo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Account is a NameSilo account whose API keys are used for the domains it
+// holds, so that one Provider can manage domains spread across several
+// accounts. See Provider.Accounts.
+type Account struct {
+	// Zones are the registered domains of the account, as exact names
+	// ("example.com") or suffixes ("*.example.org" matches every domain
+	// ending in ".example.org"). Subzones are routed by their domain.
+	Zones []string `json:"zones"`
+
+	// APIToken and APITokens are the API keys of the account. Like the
+	// Provider's, they may contain environment placeholders, and
+	// additional keys are tried when a key is refused.
+	APIToken  string   `json:"api_token,omitempty"`
+	APITokens []string `json:"api_tokens,omitempty"`
+}
+
+// accountTokens returns the API keys of account with environment
+// placeholders expanded
+func (p *Provider) accountTokens(a Account) []string {
+	var tokens []string
+	seen := make(map[string]bool)
+	for _, token := range append([]string{a.APIToken}, a.APITokens...) {
+		token = p.expandEnv(token)
+		if token != "" && !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// match reports how specifically the account matches domain: the length
+// of the longest matching pattern, with exact names before all suffixes,
+// or 0 if no pattern matches
+func (a Account) match(domain string) int {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	best := 0
+	for _, pattern := range a.Zones {
+		pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+		switch {
+		case pattern == domain:
+			return len(domain) + 1<<16
+		case strings.HasPrefix(pattern, "*.") && strings.HasSuffix(domain, pattern[1:]):
+			if len(pattern) > best {
+				best = len(pattern)
+			}
+		}
+	}
+	return best
+}
+
+// account returns the account holding domain, or nil if domain is not
+// routed to one of the Accounts
+func (p *Provider) account(domain string) *Account {
+	if domain == "" {
+		return nil
+	}
+
+	var found *Account
+	best := 0
+	for i := range p.Accounts {
+		if m := p.Accounts[i].match(domain); m > best {
+			found, best = &p.Accounts[i], m
+		}
+	}
+	return found
+}
+
+// checkAccounts rejects accounts that could never be used
+func (p *Provider) checkAccounts() error {
+	for i, account := range p.Accounts {
+		if len(account.Zones) == 0 {
+			return fmt.Errorf("namesilo: account %d has no zones", i+1)
+		}
+		if account.APIToken == "" && len(account.APITokens) == 0 {
+			return fmt.Errorf("namesilo: account for %s has no API key", strings.Join(account.Zones, ", "))
+		}
+		for _, pattern := range account.Zones {
+			if strings.Contains(strings.TrimPrefix(pattern, "*."), "*") {
+				return fmt.Errorf("namesilo: account zone %q: only a leading \"*.\" wildcard is supported", pattern)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// accountServer answers like NameSilo for keys listed in valid, recording
+// which key was used for each domain
+func accountServer(t *testing.T, valid ...string) (*Provider, map[string][]string) {
+	var mu sync.Mutex
+	used := make(map[string][]string)
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		key, domain := r.URL.Query().Get("key"), r.URL.Query().Get("domain")
+		mu.Lock()
+		used[domain] = append(used[domain], key)
+		mu.Unlock()
+
+		code := 110
+		for _, v := range valid {
+			if key == v {
+				code = 300
+			}
+		}
+		fmt.Fprintf(w, `<namesilo><reply><code>%d</code><detail>x</detail></reply></namesilo>`, code)
+	})
+	return provider, used
+}
+
+func TestAccountRouting(t *testing.T) {
+	provider, used := accountServer(t, "default", "exact", "suffix", "longer")
+	provider.APIToken = "default"
+	provider.Accounts = []Account{
+		{Zones: []string{"*.example.org"}, APIToken: "suffix"},
+		{Zones: []string{"*.shop.example.org", "Example.NET."}, APIToken: "longer"},
+		{Zones: []string{"example.com"}, APIToken: "exact"},
+	}
+	// Domains of three labels are not taken for subzones
+	provider.Subzones = make(map[string]string)
+	zones := []string{"example.com.", "a.example.org", "b.shop.example.org", "example.net", "other.com"}
+	for _, zone := range zones {
+		provider.Subzones[zone] = zone
+	}
+	ctx := context.Background()
+
+	for _, zone := range zones {
+		if _, err := provider.GetRecords(ctx, zone); err != nil {
+			t.Fatalf("GetRecords(%s) failed: %v", zone, err)
+		}
+	}
+	if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+		t.Fatalf("ListDomains failed: %v", err)
+	}
+
+	want := map[string][]string{
+		"example.com":        {"exact"},
+		"a.example.org":      {"suffix"},
+		"b.shop.example.org": {"longer"},
+		"example.net":        {"longer"},
+		"other.com":          {"default"},
+		"":                   {"default"},
+	}
+	for domain, keys := range want {
+		if got := used[domain]; strings.Join(got, ",") != strings.Join(keys, ",") {
+			t.Errorf("Keys used for %q = %v, want %v", domain, got, keys)
+		}
+	}
+}
+
+func TestAccountKeyFailover(t *testing.T) {
+	provider, used := accountServer(t, "second")
+	provider.APIToken = ""
+	provider.Accounts = []Account{{Zones: []string{"example.com"}, APIToken: "first", APITokens: []string{"second"}}}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+	}
+	if got := strings.Join(used["example.com"], ","); got != "first,second,first,second" {
+		t.Errorf("Expected each call to try the keys in order, got %s", got)
+	}
+
+	// Without a default key, unrouted requests fail before any call
+	if _, err := provider.GetRecords(ctx, "other.com"); err == nil || !strings.Contains(err.Error(), "other.com") {
+		t.Errorf("Expected an error naming the unrouted domain, got %v", err)
+	}
+	if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err == nil {
+		t.Error("Expected ListDomains to fail without a default key")
+	}
+	if len(used["other.com"])+len(used[""]) != 0 {
+		t.Errorf("Expected no requests for unrouted operations, got %v", used)
+	}
+}
+
+func TestAccountKeyRedacted(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, "upstream failed for %s", r.URL.RawQuery)
+	})
+	provider.Accounts = []Account{{Zones: []string{"example.com"}, APIToken: "account-secret"}}
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	if err == nil || strings.Contains(err.Error(), "account-secret") {
+		t.Errorf("Expected an error without the account key, got %v", err)
+	}
+}
+
+func TestValidateAccounts(t *testing.T) {
+	provider, _ := accountServer(t, "default")
+	provider.APIToken = "default"
+	provider.Accounts = []Account{{Zones: []string{"example.org"}, APIToken: "revoked"}}
+
+	err := provider.Validate(context.Background())
+	if !errors.Is(err, ErrInvalidAPIKey) || !strings.Contains(err.Error(), "example.org") {
+		t.Errorf("Expected the account key to be reported, got %v", err)
+	}
+}
+
+func TestAccountConfig(t *testing.T) {
+	var p Provider
+	err := json.Unmarshal([]byte(`{"accounts": [{"zones": ["example.org", "*.example.net"], "api_token": "{env.OTHER}"}]}`), &p)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(p.Accounts) != 1 || p.Accounts[0].APIToken != "{env.OTHER}" || len(p.Accounts[0].Zones) != 2 {
+		t.Errorf("Unexpected accounts %+v", p.Accounts)
+	}
+
+	for _, input := range []string{
+		`{"accounts": [{"api_token": "x"}]}`,
+		`{"accounts": [{"zones": ["example.org"]}]}`,
+		`{"accounts": [{"zones": ["ex*ample.org"], "api_token": "x"}]}`,
+	} {
+		var p Provider
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+
+	var c Provider
+	if err := c.UnmarshalCaddyfile(newTestDispenser("namesilo default {\n\taccount other example.org *.example.net\n}")); err != nil {
+		t.Fatalf("UnmarshalCaddyfile failed: %v", err)
+	}
+	if len(c.Accounts) != 1 || c.Accounts[0].APIToken != "other" || strings.Join(c.Accounts[0].Zones, " ") != "example.org *.example.net" {
+		t.Errorf("Unexpected accounts %+v", c.Accounts)
+	}
+	for _, input := range []string{"namesilo {\n\taccount only-token\n}", "namesilo {\n\taccounts x\n}"} {
+		var c Provider
+		if err := c.UnmarshalCaddyfile(newTestDispenser(input)); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package namesilo
+
+import (
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// aliasType is NameSilo's record type for apex CNAME-like records
+const aliasType = "ALIAS"
+
+// AliasRecord marks a libdns.CNAME as a NameSilo ALIAS record when set as
+// its ProviderData. ALIAS records behave like a CNAME but may be placed at
+// the zone apex, where NameSilo resolves the target and serves its
+// addresses (apex flattening).
+//
+// GetRecords returns ALIAS records in this form, and passing such a record
+// to AppendRecords or SetRecords creates an ALIAS record. A libdns.RR with
+// Type "ALIAS" is accepted as well.
+type AliasRecord struct{}
+
+// NewAlias returns a record representing a NameSilo ALIAS record.
+func NewAlias(name, target string, ttl time.Duration) libdns.CNAME {
+	return libdns.CNAME{
+		Name:         name,
+		TTL:          ttl,
+		Target:       target,
+		ProviderData: AliasRecord{},
+	}
+}
+
+// IsAlias reports whether record represents a NameSilo ALIAS record.
+func IsAlias(record libdns.Record) bool {
+	return apiRecordType(record) == aliasType
+}
+
+// apiRecordType returns the NameSilo record type of a record, which differs
+// from the libdns type for ALIAS records
+func apiRecordType(record libdns.Record) string {
+	if nsRec, ok := record.(namesileoRecord); ok {
+		record = nsRec.Record
+	}
+
+	switch r := record.(type) {
+	case libdns.CNAME:
+		if _, ok := r.ProviderData.(AliasRecord); ok {
+			return aliasType
+		}
+	case *libdns.CNAME:
+		if _, ok := r.ProviderData.(AliasRecord); ok {
+			return aliasType
+		}
+	}
+
+	return strings.ToUpper(record.RR().Type)
+}
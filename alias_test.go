@@ -0,0 +1,86 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestAliasRecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		NewAlias("@", "lb.example.net", time.Hour),
+		libdns.CNAME{Name: "www", Target: "lb.example.net"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	types := map[string]string{}
+	for _, rec := range srv.Records("example.com") {
+		types[rec.Host] = rec.Type
+	}
+	if types["example.com"] != "ALIAS" || types["www.example.com"] != "CNAME" {
+		t.Fatalf("Unexpected record types: %v", types)
+	}
+
+	records, err := provider.GetRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	var aliases int
+	for _, rec := range records {
+		if !IsAlias(rec) {
+			continue
+		}
+		aliases++
+		cname, ok := rec.(namesileoRecord).Record.(libdns.CNAME)
+		if !ok || cname.Target != "lb.example.net" || cname.TTL != time.Hour {
+			t.Errorf("Unexpected ALIAS record: %#v", rec)
+		}
+	}
+	if aliases != 1 {
+		t.Errorf("Expected 1 ALIAS record, got %d", aliases)
+	}
+}
+
+func TestIsAlias(t *testing.T) {
+	if !IsAlias(NewAlias("@", "lb.example.net", 0)) {
+		t.Error("NewAlias should be an ALIAS record")
+	}
+	if !IsAlias(libdns.RR{Name: "@", Type: "alias", Data: "lb.example.net"}) {
+		t.Error("RR with type ALIAS should be an ALIAS record")
+	}
+	if IsAlias(libdns.CNAME{Name: "www", Target: "lb.example.net"}) {
+		t.Error("Plain CNAME should not be an ALIAS record")
+	}
+}
+
+func TestAliasZoneFileRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeZoneFile(&buf, "example.com", []libdns.Record{NewAlias("@", "lb.example.net", time.Hour)})
+	if err != nil {
+		t.Fatalf("writeZoneFile failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "@\t3600\tIN\tALIAS\tlb.example.net.") {
+		t.Fatalf("Unexpected zone file:\n%s", buf.String())
+	}
+
+	entries, err := parseZoneFile(&buf, "example.com")
+	if err != nil {
+		t.Fatalf("parseZoneFile failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].rr.Type != "ALIAS" || entries[0].rr.Data != "lb.example.net" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
@@ -0,0 +1,60 @@
+package namesilo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// ApexCNAMEError is returned, before any change is made, for a CNAME
+// record at the zone apex. DNS does not allow a CNAME next to the SOA and
+// NS records every zone has at its apex, and NameSilo rejects it with an
+// unhelpful message. Use an ALIAS record (see NewAlias) instead, or set
+// ApexCNAMEToAlias to have the conversion made automatically.
+type ApexCNAMEError struct {
+	Zone   string // zone of the record, without trailing dot
+	Target string // target of the CNAME record
+}
+
+func (e *ApexCNAMEError) Error() string {
+	return fmt.Sprintf("namesilo: a CNAME record is not allowed at the apex of %s (target %s); use an ALIAS record or set ApexCNAMEToAlias", e.Zone, e.Target)
+}
+
+// isApexCNAME reports whether record is a CNAME, not an ALIAS, at the apex
+// of zone
+func isApexCNAME(zone string, record libdns.Record) bool {
+	rr := record.RR()
+	return strings.EqualFold(rr.Type, "CNAME") && !IsAlias(record) && normalizeRecordName(rr.Name, zone) == "@"
+}
+
+// apexAliases returns records with CNAME records at the apex of zone
+// converted to ALIAS records when ApexCNAMEToAlias is set, and an
+// ApexCNAMEError for the first one otherwise
+func (p *Provider) apexAliases(zone string, records []libdns.Record) ([]libdns.Record, error) {
+	var converted []libdns.Record
+	for i, record := range records {
+		if !isApexCNAME(zone, record) {
+			continue
+		}
+		rr := record.RR()
+		if !p.ApexCNAMEToAlias {
+			return nil, &ApexCNAMEError{Zone: strings.TrimSuffix(zone, "."), Target: rr.Data}
+		}
+
+		if converted == nil {
+			// Leave the caller's slice unchanged
+			converted = append([]libdns.Record(nil), records...)
+		}
+		alias := libdns.Record(NewAlias(rr.Name, rr.Data, rr.TTL))
+		if id, ok := RecordID(record); ok {
+			alias = withRecordID(alias, id)
+		}
+		converted[i] = alias
+	}
+
+	if converted == nil {
+		return records, nil
+	}
+	return converted, nil
+}
@@ -0,0 +1,105 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestApexCNAMEError(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	for _, record := range []libdns.Record{
+		libdns.CNAME{Name: "@", Target: "lb.example.net."},
+		libdns.CNAME{Name: "example.com.", Target: "lb.example.net."},
+		libdns.RR{Name: "", Type: "CNAME", Data: "lb.example.net."},
+	} {
+		_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+			libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1"},
+			record,
+		})
+		var apexErr *ApexCNAMEError
+		if !errors.As(err, &apexErr) {
+			t.Fatalf("Expected an ApexCNAMEError for %+v, got %v", record, err)
+		}
+		if apexErr.Zone != "example.com" || apexErr.Target != "lb.example.net." {
+			t.Errorf("Unexpected error fields %+v", apexErr)
+		}
+	}
+	if n := len(srv.Records("example.com")); n != 0 {
+		t.Errorf("Expected no records to be created, got %d", n)
+	}
+
+	// ALIAS records and CNAMEs below the apex are fine
+	if _, err := provider.SetRecords(ctx, "example.com.", []libdns.Record{
+		NewAlias("@", "lb.example.net.", time.Hour),
+		libdns.CNAME{Name: "www", Target: "lb.example.net."},
+	}); err != nil {
+		t.Errorf("SetRecords failed: %v", err)
+	}
+}
+
+func TestApexCNAMEToAlias(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), ApexCNAMEToAlias: true}
+	ctx := context.Background()
+
+	records := []libdns.Record{libdns.CNAME{Name: "@", Target: "lb.example.net", TTL: time.Hour}}
+	added, err := provider.SetRecords(ctx, "example.com.", records)
+	if err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if len(added) != 1 || !IsAlias(added[0]) {
+		t.Errorf("Expected an ALIAS record to be returned, got %+v", added)
+	}
+	if _, ok := records[0].(libdns.CNAME); !ok || IsAlias(records[0]) {
+		t.Error("Expected the caller's records to be left unchanged")
+	}
+	if recs := srv.Records("example.com"); len(recs) != 1 || recs[0].Type != "ALIAS" || recs[0].Value != "lb.example.net" {
+		t.Errorf("Unexpected records: %+v", recs)
+	}
+
+	// The conversion is stable across syncs
+	plan, err := provider.SyncZone(ctx, "example.com.", records, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected no changes, got %+v", plan.Changes)
+	}
+}
+
+func TestApexCNAMESubzone(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{
+		APIToken:   "test",
+		HTTPClient: srv.Client(),
+		Subzones:   map[string]string{"dev.example.com": "example.com"},
+	}
+
+	// The apex of a subzone is an ordinary host of its domain
+	plan, err := provider.SyncZone(context.Background(), "dev.example.com.", []libdns.Record{
+		libdns.CNAME{Name: "@", Target: "lb.example.net"},
+	}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+	if len(plan.Changes) != 1 {
+		t.Errorf("Expected 1 change, got %+v", plan.Changes)
+	}
+	if recs := srv.Records("example.com"); len(recs) != 1 || recs[0].Type != "CNAME" {
+		t.Errorf("Unexpected records: %+v", recs)
+	}
+}
@@ -0,0 +1,118 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultNameServers are the nameservers NameSilo assigns to domains
+// that use its hosted DNS service.
+var defaultNameServers = []string{
+	"ns1.dnsowl.com",
+	"ns2.dnsowl.com",
+	"ns3.dnsowl.com",
+}
+
+// domainInfoResponse represents the response from getDomainInfo
+type domainInfoResponse struct {
+	apiResponse
+	Created     string   `xml:"reply>created"`
+	Expires     string   `xml:"reply>expires"`
+	Status      string   `xml:"reply>status"`
+	Locked      string   `xml:"reply>locked"`
+	Private     string   `xml:"reply>private"`
+	AutoRenew   string   `xml:"reply>auto_renew"`
+	TrafficType string   `xml:"reply>traffic_type"`
+	Portfolio   string   `xml:"reply>portfolio"`
+	NameServers []string `xml:"reply>nameservers>nameserver"`
+}
+
+// usesNameSiloDNS reports whether the domain is delegated to NameSilo's nameservers
+func (r domainInfoResponse) usesNameSiloDNS() bool {
+	for _, ns := range r.NameServers {
+		ns = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(ns), "."))
+		for _, def := range defaultNameServers {
+			if ns == def {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getDomainInfo retrieves the registration details of a domain in the account
+func (p *Provider) getDomainInfo(ctx context.Context, domain string) (*domainInfoResponse, error) {
+	params := map[string]string{
+		"domain": domain,
+	}
+
+	var response domainInfoResponse
+	if err := p.callAPI(ctx, "getDomainInfo", params, &response); err != nil {
+		return nil, err
+	}
+
+	return &response, nil
+}
+
+// isDetachedZoneError reports whether a reply code can mean that the domain
+// is not served by NameSilo DNS. NameSilo answers DNS operations on such a
+// domain with 200, "domain is not active, or does not belong to this user";
+// other codes, such as 280 for a rejected record, are unrelated.
+func isDetachedZoneError(code int) bool {
+	return code == 200
+}
+
+// withZoneAttached runs op and, if it fails with an API error that can mean
+// the zone is not on NameSilo DNS, checks whether it is detached. If the
+// zone gets attached, op is run once more.
+func (p *Provider) withZoneAttached(ctx context.Context, zone string, op func() error) error {
+	err := op()
+
+	var apiErr *APIError
+	if err == nil || !errors.As(err, &apiErr) || !isDetachedZoneError(apiErr.Code) {
+		return err
+	}
+
+	attached, attachErr := p.attachZone(ctx, zone)
+	if attachErr != nil {
+		return fmt.Errorf("%w (%v)", err, attachErr)
+	}
+	if !attached {
+		return err
+	}
+
+	// Retry once now that the zone is served by NameSilo DNS
+	return op()
+}
+
+// attachZone is called after a DNS operation on zone failed. It checks whether
+// the domain is registered in the account but not using NameSilo DNS, and if
+// AutoAttachZone is enabled, delegates it to NameSilo's nameservers. It returns
+// true if the zone was attached and the failed operation should be retried.
+func (p *Provider) attachZone(ctx context.Context, zone string) (bool, error) {
+	domain := strings.TrimSuffix(zone, ".")
+
+	info, err := p.getDomainInfo(ctx, domain)
+	if err != nil {
+		// The domain is not registered in this account (e.g. a free-DNS
+		// external domain that was never set up), so there is nothing to attach.
+		return false, fmt.Errorf("zone %q is not available in this NameSilo account: %w", zone, err)
+	}
+
+	if info.usesNameSiloDNS() {
+		return false, nil
+	}
+
+	if !p.AutoAttachZone {
+		return false, fmt.Errorf("zone %q is not using NameSilo DNS (nameservers: %s); enable AutoAttachZone to attach it automatically",
+			zone, strings.Join(info.NameServers, ", "))
+	}
+
+	if err := p.SetNameServers(ctx, domain, defaultNameServers); err != nil {
+		return false, fmt.Errorf("failed to attach zone %q to NameSilo DNS: %w", zone, err)
+	}
+
+	return true, nil
+}
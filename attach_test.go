@@ -0,0 +1,83 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestUsesNameSiloDNS(t *testing.T) {
+	tests := []struct {
+		nameServers []string
+		want        bool
+	}{
+		{[]string{"NS1.DNSOWL.COM", "NS2.DNSOWL.COM"}, true},
+		{[]string{"ns3.dnsowl.com."}, true},
+		{[]string{"ns1.example.net", "ns2.example.net"}, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		info := domainInfoResponse{NameServers: tt.nameServers}
+		if got := info.usesNameSiloDNS(); got != tt.want {
+			t.Errorf("usesNameSiloDNS(%v) = %v, want %v", tt.nameServers, got, tt.want)
+		}
+	}
+}
+
+func TestWithZoneAttached(t *testing.T) {
+	const (
+		notActive = `<namesilo><reply><code>200</code><detail>Domain is not active, or does not belong to this user</detail></reply></namesilo>`
+		rejected  = `<namesilo><reply><code>280</code><detail>DNS modification error</detail></reply></namesilo>`
+		success   = `<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`
+		external  = `<namesilo><reply><code>300</code><detail>success</detail><nameservers><nameserver>ns1.example.net</nameserver></nameservers></reply></namesilo>`
+		dnsOwl    = `<namesilo><reply><code>300</code><detail>success</detail><nameservers><nameserver>ns1.dnsowl.com</nameserver></nameservers></reply></namesilo>`
+	)
+	tests := []struct {
+		name       string
+		autoAttach bool
+		list       string // first dnsListRecords reply
+		info       string // getDomainInfo reply
+		operations string
+		wantErr    string
+	}{
+		{"attached and retried", true, notActive, external, "dnsListRecords getDomainInfo changeNameServers dnsListRecords", ""},
+		{"not in the account", true, notActive, notActive, "dnsListRecords getDomainInfo", "not available in this NameSilo account"},
+		{"already on NameSilo DNS", true, notActive, dnsOwl, "dnsListRecords getDomainInfo", "not active"},
+		{"attaching disabled", false, notActive, external, "dnsListRecords getDomainInfo", "enable AutoAttachZone"},
+		{"other reply codes", true, rejected, external, "dnsListRecords", "DNS modification error"},
+	}
+	for _, tt := range tests {
+		lists := 0
+		var operations []string
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			operation := strings.TrimPrefix(r.URL.Path, "/api/")
+			operations = append(operations, operation)
+			switch operation {
+			case "dnsListRecords":
+				if lists++; lists == 1 {
+					w.Write([]byte(tt.list))
+					return
+				}
+				w.Write([]byte(success))
+			case "getDomainInfo":
+				w.Write([]byte(tt.info))
+			default:
+				w.Write([]byte(success))
+			}
+		})
+		provider.AutoAttachZone = tt.autoAttach
+
+		_, err := provider.GetRecords(context.Background(), "example.com")
+		if got := strings.Join(operations, " "); got != tt.operations {
+			t.Errorf("%s: operations = %q, want %q", tt.name, got, tt.operations)
+		}
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: GetRecords failed: %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// Auditor receives an AuditEvent for every record the Provider creates,
+// updates or deletes, including changes made to roll back a failed call.
+// Audit is called synchronously after each change and should not block.
+type Auditor interface {
+	Audit(ctx context.Context, event AuditEvent)
+}
+
+// AuditorFunc adapts a function to an Auditor.
+type AuditorFunc func(ctx context.Context, event AuditEvent)
+
+// Audit calls f.
+func (f AuditorFunc) Audit(ctx context.Context, event AuditEvent) {
+	f(ctx, event)
+}
+
+// AuditEvent describes a single record change.
+type AuditEvent struct {
+	Time     time.Time
+	Actor    string        // set with WithActor, empty otherwise
+	Zone     string        // NameSilo domain the change was made in
+	Action   SyncAction    // SyncCreate, SyncUpdate or SyncDelete
+	RecordID string        // NameSilo record ID; for updates, the ID after the change
+	Before   libdns.Record // record before the change; nil for creates and unknown for DeleteRecordByID
+	After    libdns.Record // record after the change; nil for deletes
+	Err      error         // non-nil if the change failed, in which case it may not have been made
+}
+
+type actorKey struct{}
+
+// WithActor returns a context that attributes the changes made with it to
+// actor, e.g. a user or service name, in AuditEvents.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// actorFrom returns the actor set with WithActor
+func actorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// audit reports a record change to the Auditor and the webhook, if
+// configured
+func (p *Provider) audit(ctx context.Context, zone string, action SyncAction, id string, before, after libdns.Record, err error) {
+	if p.Auditor == nil && p.WebhookURL == "" {
+		return
+	}
+	event := AuditEvent{
+		Time:     time.Now(),
+		Actor:    actorFrom(ctx),
+		Zone:     strings.TrimSuffix(zone, "."),
+		Action:   action,
+		RecordID: id,
+		Before:   before,
+		After:    after,
+		Err:      err,
+	}
+
+	if p.Auditor != nil {
+		p.Auditor.Audit(ctx, event)
+	}
+	if p.WebhookURL != "" {
+		p.webhookNotifier().Audit(ctx, event)
+	}
+}
+
+// webhookNotifier returns the Provider's notifier for WebhookURL, so that
+// notifications share one queue and HTTP client
+func (p *Provider) webhookNotifier() *WebhookNotifier {
+	url := p.expandEnv(p.WebhookURL)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.webhook == nil || p.webhook.URL != url {
+		p.webhook = &WebhookNotifier{
+			URL: url,
+			OnError: func(err error) {
+				if p.Logger != nil {
+					p.Logger.Warn("namesilo webhook notification failed", "error", p.redact(err.Error()))
+				}
+			},
+		}
+	}
+	return p.webhook
+}
+
+// FlushWebhook waits until the notifications for WebhookURL queued so far
+// have been delivered or have failed, e.g. before a program exits.
+func (p *Provider) FlushWebhook() {
+	p.mu.Lock()
+	webhook := p.webhook
+	p.mu.Unlock()
+	if webhook != nil {
+		webhook.Flush()
+	}
+}
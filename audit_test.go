@@ -0,0 +1,90 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestAuditor(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.2", TTL: 3600},
+	)
+
+	var events []AuditEvent
+	provider := &Provider{
+		APIToken:   "test",
+		HTTPClient: srv.Client(),
+		Auditor: AuditorFunc(func(ctx context.Context, event AuditEvent) {
+			events = append(events, event)
+		}),
+	}
+	ctx := WithActor(context.Background(), "deploy-bot")
+
+	// One record is updated in place, the other deleted
+	if _, err := provider.SetRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.3", TTL: time.Hour},
+	}); err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if _, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "note", Text: "hello"},
+	}); err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 events, got %+v", events)
+	}
+	for _, event := range events {
+		if event.Actor != "deploy-bot" || event.Zone != "example.com" || event.Err != nil || event.Time.IsZero() {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	}
+
+	update, del, create := events[0], events[1], events[2]
+	if update.Action != SyncUpdate || update.Before.RR().Data != "192.0.2.1" || update.After.RR().Data != "192.0.2.3" {
+		t.Errorf("Unexpected update event: %+v", update)
+	}
+	if id, _ := RecordID(update.After); id != update.RecordID {
+		t.Errorf("Expected the updated record to carry the new ID %q, got %q", update.RecordID, id)
+	}
+	if del.Action != SyncDelete || del.Before.RR().Data != "192.0.2.2" || del.After != nil {
+		t.Errorf("Unexpected delete event: %+v", del)
+	}
+	if create.Action != SyncCreate || create.Before != nil || create.After.RR().Name != "note" || create.RecordID == "" {
+		t.Errorf("Unexpected create event: %+v", create)
+	}
+}
+
+func TestAuditorReportsFailures(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", namesilotest.Record{Type: "TXT", Host: "dup", Value: "x", TTL: 3600})
+
+	var events []AuditEvent
+	provider := &Provider{
+		APIToken:   "test",
+		HTTPClient: srv.Client(),
+		Auditor: AuditorFunc(func(ctx context.Context, event AuditEvent) {
+			events = append(events, event)
+		}),
+	}
+
+	_, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "dup", Text: "x", TTL: time.Hour},
+	})
+	if err == nil {
+		t.Fatal("Expected the duplicate to be rejected")
+	}
+	if len(events) != 1 || !errors.Is(events[0].Err, ErrRecordExists) || events[0].Actor != "" {
+		t.Errorf("Expected a failed create event, got %+v", events)
+	}
+}
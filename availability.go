@@ -0,0 +1,88 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// availabilityBatchSize is the number of domains checked per API request
+const availabilityBatchSize = 100
+
+// AvailableDomain is a domain that can be registered, with its pricing.
+type AvailableDomain struct {
+	Domain       string
+	Price        float64 // Registration price for Duration years, in USD
+	RenewalPrice float64 // Renewal price, in USD
+	Premium      bool    // Premium domains are priced individually by the registry
+	Duration     int     // Registration period the price applies to, in years
+}
+
+// AvailabilityResult groups checked domains by availability.
+type AvailabilityResult struct {
+	Available   []AvailableDomain
+	Unavailable []string
+	Invalid     []string // Malformed names or unsupported TLDs
+}
+
+// availabilityDomain represents a domain entry from checkRegisterAvailability
+type availabilityDomain struct {
+	Name     string  `xml:",chardata"`
+	Price    float64 `xml:"price,attr"`
+	Renew    float64 `xml:"renew,attr"`
+	Premium  int     `xml:"premium,attr"`
+	Duration int     `xml:"duration,attr"`
+}
+
+// availabilityResponse represents the response from checkRegisterAvailability
+type availabilityResponse struct {
+	apiResponse
+	Available   []availabilityDomain `xml:"reply>available>domain"`
+	Unavailable []string             `xml:"reply>unavailable>domain"`
+	Invalid     []string             `xml:"reply>invalid>domain"`
+}
+
+// CheckAvailability checks whether the given domains can be registered.
+// Large lists are split into several API requests.
+func (p *Provider) CheckAvailability(ctx context.Context, domains []string) (*AvailabilityResult, error) {
+	if len(domains) == 0 {
+		return nil, fmt.Errorf("at least one domain is required")
+	}
+
+	result := &AvailabilityResult{}
+
+	for start := 0; start < len(domains); start += availabilityBatchSize {
+		end := start + availabilityBatchSize
+		if end > len(domains) {
+			end = len(domains)
+		}
+
+		batch := make([]string, 0, end-start)
+		for _, domain := range domains[start:end] {
+			batch = append(batch, strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		}
+
+		params := map[string]string{
+			"domains": strings.Join(batch, ","),
+		}
+
+		var response availabilityResponse
+		if err := p.callAPI(ctx, "checkRegisterAvailability", params, &response); err != nil {
+			return nil, err
+		}
+
+		for _, d := range response.Available {
+			result.Available = append(result.Available, AvailableDomain{
+				Domain:       strings.TrimSpace(d.Name),
+				Price:        d.Price,
+				RenewalPrice: d.Renew,
+				Premium:      d.Premium == 1,
+				Duration:     d.Duration,
+			})
+		}
+		result.Unavailable = append(result.Unavailable, response.Unavailable...)
+		result.Invalid = append(result.Invalid, response.Invalid...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCheckAvailability(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("domains"); got != "free.com,taken.com,bad" {
+			t.Errorf("Unexpected domains parameter %q", got)
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<available><domain price="12.95" premium="1" duration="1" renew="13.95">free.com</domain></available>
+<unavailable><domain>taken.com</domain></unavailable>
+<invalid><domain>bad</domain></invalid>
+</reply></namesilo>`))
+	})
+
+	result, err := provider.CheckAvailability(context.Background(), []string{"free.com", "taken.com.", "bad"})
+	if err != nil {
+		t.Fatalf("CheckAvailability failed: %v", err)
+	}
+
+	want := AvailableDomain{Domain: "free.com", Price: 12.95, RenewalPrice: 13.95, Premium: true, Duration: 1}
+	if len(result.Available) != 1 || result.Available[0] != want {
+		t.Errorf("Unexpected available domains: %+v", result.Available)
+	}
+	if len(result.Unavailable) != 1 || result.Unavailable[0] != "taken.com" {
+		t.Errorf("Unexpected unavailable domains: %v", result.Unavailable)
+	}
+	if len(result.Invalid) != 1 || result.Invalid[0] != "bad" {
+		t.Errorf("Unexpected invalid domains: %v", result.Invalid)
+	}
+}
+
+func TestCheckAvailabilityBatches(t *testing.T) {
+	requests := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var unavailable strings.Builder
+		for _, domain := range strings.Split(r.URL.Query().Get("domains"), ",") {
+			fmt.Fprintf(&unavailable, "<domain>%s</domain>", domain)
+		}
+		fmt.Fprintf(w, `<namesilo><reply><code>300</code><detail>success</detail><unavailable>%s</unavailable></reply></namesilo>`, unavailable.String())
+	})
+
+	domains := make([]string, 250)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("domain%d.com", i)
+	}
+
+	result, err := provider.CheckAvailability(context.Background(), domains)
+	if err != nil {
+		t.Fatalf("CheckAvailability failed: %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 batched requests, got %d", requests)
+	}
+	if len(result.Unavailable) != 250 {
+		t.Errorf("Expected 250 results, got %d", len(result.Unavailable))
+	}
+}
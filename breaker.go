@@ -0,0 +1,111 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultBreakerCooldown = 30 * time.Second
+
+// ErrCircuitOpen is returned without contacting NameSilo while the circuit
+// breaker is open after repeated failures. See BreakerThreshold.
+var ErrCircuitOpen = errors.New("namesilo: circuit breaker open, API unavailable")
+
+// circuitBreaker counts consecutive NameSilo failures shared by all
+// requests of a Provider
+type circuitBreaker struct {
+	failures  int       // consecutive failures
+	openUntil time.Time // end of the current cooldown
+	probing   bool      // a trial request is in flight after the cooldown
+}
+
+// breakerCooldown returns how long the breaker stays open
+func (p *Provider) breakerCooldown() time.Duration {
+	if p.BreakerCooldown > 0 {
+		return p.BreakerCooldown
+	}
+	return defaultBreakerCooldown
+}
+
+// allowRequest returns ErrCircuitOpen if a request must fail fast. Once the
+// cooldown has passed, a single trial request is let through; allowRequest
+// reports true for it, and its outcome closes or reopens the breaker.
+func (p *Provider) allowRequest() (bool, error) {
+	if p.BreakerThreshold <= 0 {
+		return false, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b := &p.breaker
+	if b.failures < p.BreakerThreshold {
+		return false, nil
+	}
+	if b.probing || time.Now().Before(b.openUntil) {
+		return false, ErrCircuitOpen
+	}
+	b.probing = true
+	return true, nil
+}
+
+// recordOutcome updates the circuit breaker with the result of an HTTP
+// request; trial is what allowRequest reported for it. Only the trial
+// request ends the trial, so that requests started before the breaker
+// opened cannot let a second one through. Failures the caller caused, such
+// as a cancelled context, leave the failure count unchanged.
+func (p *Provider) recordOutcome(ctx context.Context, operation string, trial bool, err error) {
+	if p.BreakerThreshold <= 0 {
+		return
+	}
+
+	p.mu.Lock()
+	b := &p.breaker
+	if trial {
+		b.probing = false
+	}
+	if ctx.Err() != nil {
+		p.mu.Unlock()
+		return
+	}
+	if !isOutageError(err) {
+		b.failures = 0
+		p.mu.Unlock()
+		return
+	}
+	b.failures++
+	opened := b.failures >= p.BreakerThreshold
+	if opened {
+		b.openUntil = time.Now().Add(p.breakerCooldown())
+	}
+	failures := b.failures
+	p.mu.Unlock()
+
+	if opened && p.Logger != nil {
+		p.Logger.LogAttrs(ctx, slog.LevelWarn, "namesilo circuit breaker open",
+			slog.String("operation", operation),
+			slog.Int("failures", failures),
+			slog.Duration("cooldown", p.breakerCooldown()),
+			slog.String("error", p.redact(err.Error())))
+	}
+}
+
+// isOutageError reports whether an HTTP request error suggests NameSilo is
+// unavailable: a transport error, including timeouts, or an HTTP 5xx reply
+func isOutageError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,185 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	attempts := 0
+	healthy := false
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.BreakerThreshold = 2
+	provider.BreakerCooldown = 20 * time.Millisecond
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetRecords(ctx, "example.com"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Call %d: expected an HTTP failure, got %v", i+1, err)
+		}
+	}
+
+	_, err := provider.GetRecords(ctx, "example.com")
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected no request while open, got %d attempts", attempts)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	healthy = true
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("Expected trial request to succeed, got %v", err)
+	}
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("Expected closed breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreakerReopensAfterFailedTrial(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	provider.BreakerThreshold = 1
+	provider.BreakerCooldown = 10 * time.Millisecond
+
+	ctx := context.Background()
+	provider.GetRecords(ctx, "example.com")
+	time.Sleep(20 * time.Millisecond)
+	provider.GetRecords(ctx, "example.com")
+
+	if _, err := provider.GetRecords(ctx, "example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected breaker to reopen after failed trial, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestCircuitBreakerTrialCanceledBeforeSending(t *testing.T) {
+	attempts := 0
+	healthy := false
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.BreakerThreshold = 1
+	provider.BreakerCooldown = 10 * time.Millisecond
+	provider.RequestsPerSecond = 5
+	provider.Burst = 1
+
+	ctx := context.Background()
+	provider.GetRecords(ctx, "example.com")
+	time.Sleep(20 * time.Millisecond)
+
+	// The trial request is canceled while it waits for the rate limiter
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := provider.GetRecords(waitCtx, "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the rate-limit wait to time out, got %v", err)
+	}
+
+	healthy = true
+	time.Sleep(250 * time.Millisecond)
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("Expected a new trial request to succeed, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestCircuitBreakerSingleTrial(t *testing.T) {
+	slowStarted := make(chan struct{})
+	trialStarted := make(chan struct{})
+	release := make(chan struct{})
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("domain") {
+		case "slow.com":
+			close(slowStarted)
+			<-r.Context().Done()
+		case "trial.com":
+			close(trialStarted)
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	provider.BreakerThreshold = 1
+	provider.BreakerCooldown = 10 * time.Millisecond
+
+	// A request started before the breaker opens ends during the trial
+	slowCtx, cancel := context.WithCancel(context.Background())
+	slowDone := make(chan struct{})
+	go func() {
+		defer close(slowDone)
+		provider.GetRecords(slowCtx, "slow.com")
+	}()
+	<-slowStarted
+
+	ctx := context.Background()
+	provider.GetRecords(ctx, "example.com")
+	time.Sleep(20 * time.Millisecond)
+
+	trialDone := make(chan struct{})
+	go func() {
+		defer close(trialDone)
+		provider.GetRecords(ctx, "trial.com")
+	}()
+	<-trialStarted
+	cancel()
+	<-slowDone
+
+	if _, err := provider.GetRecords(ctx, "example.com"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Expected ErrCircuitOpen while the trial is in flight, got %v", err)
+	}
+	close(release)
+	<-trialDone
+}
+
+func TestCircuitBreakerIgnoresAPIErrors(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>200</code><detail>Domain is not active</detail></reply></namesilo>`))
+	})
+	provider.BreakerThreshold = 1
+
+	for i := 0; i < 3; i++ {
+		_, err := provider.GetRecords(context.Background(), "example.com")
+		if !errors.Is(err, ErrDomainNotInAccount) {
+			t.Fatalf("Call %d: expected ErrDomainNotInAccount, got %v", i+1, err)
+		}
+	}
+}
+
+func TestCircuitBreakerDisabledByDefault(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	for i := 0; i < 5; i++ {
+		provider.GetRecords(context.Background(), "example.com")
+	}
+	if attempts != 5 {
+		t.Errorf("Expected every call to reach the server, got %d attempts", attempts)
+	}
+}
@@ -0,0 +1,79 @@
+package namesilo
+
+import (
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// mutatingOperations are API operations that change a domain's DNS records
+// and therefore invalidate its cached records
+var mutatingOperations = map[string]bool{
+	"dnsAddRecord":                 true,
+	"dnsUpdateRecord":              true,
+	"dnsDeleteRecord":              true,
+	"changeNameServers":            true,
+	"domainForward":                true,
+	"domainForwardSubDomain":       true,
+	"domainForwardSubDomainDelete": true,
+}
+
+// zoneKey returns the key of domain in the record cache and the fetches in
+// flight, so that "Example.com." and "example.com" share their entries
+func zoneKey(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+// cacheEntry holds the records of a zone as of a point in time
+type cacheEntry struct {
+	records []libdns.Record
+	fetched time.Time
+}
+
+// cachedRecords returns a copy of the cached records for domain if the cache
+// is enabled and the entry is younger than CacheMaxAge
+func (p *Provider) cachedRecords(domain string) ([]libdns.Record, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.CacheMaxAge <= 0 {
+		return nil, false
+	}
+
+	entry, ok := p.cache[zoneKey(domain)]
+	if !ok || time.Since(entry.fetched) > p.CacheMaxAge {
+		return nil, false
+	}
+
+	return append([]libdns.Record(nil), entry.records...), true
+}
+
+// storeRecords caches the records of domain when the cache is enabled
+func (p *Provider) storeRecords(domain string, records []libdns.Record) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.CacheMaxAge <= 0 {
+		return
+	}
+
+	if p.cache == nil {
+		p.cache = make(map[string]cacheEntry)
+	}
+	p.cache[zoneKey(domain)] = cacheEntry{
+		records: append([]libdns.Record(nil), records...),
+		fetched: time.Now(),
+	}
+}
+
+// invalidateZone drops the cached records of domain, and makes later reads
+// of the zone start a new listing rather than join one in flight
+func (p *Provider) invalidateZone(domain string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := zoneKey(domain)
+	delete(p.cache, key)
+	delete(p.fetches, key)
+}
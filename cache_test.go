@@ -0,0 +1,137 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestRecordCache(t *testing.T) {
+	listCalls := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsListRecords") {
+			listCalls++
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>TXT</type><host>example.com</host><value>hello</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.CacheMaxAge = time.Minute
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := provider.GetRecords(ctx, "example.com."); err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+	}
+	if listCalls != 1 {
+		t.Errorf("Expected 1 list call with cache enabled, got %d", listCalls)
+	}
+
+	// A mutation invalidates the cached zone
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "test", Text: "value", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if _, err := provider.GetRecords(ctx, "example.com."); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("Expected cache invalidation after append, got %d list calls", listCalls)
+	}
+}
+
+func TestRecordCacheForwarding(t *testing.T) {
+	listCalls := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsListRecords") {
+			listCalls++
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.CacheMaxAge = time.Minute
+	ctx := context.Background()
+
+	// Forwarding makes NameSilo change the zone's records
+	changes := []func() error{
+		func() error {
+			return provider.ForwardDomain(ctx, "example.com", "https://example.net", ForwardOptions{})
+		},
+		func() error {
+			return provider.ForwardSubDomain(ctx, "example.com", "shop", "https://example.net", ForwardOptions{})
+		},
+		func() error { return provider.DeleteSubDomainForward(ctx, "example.com", "shop") },
+	}
+	for i, change := range changes {
+		if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+		if err := change(); err != nil {
+			t.Fatalf("Forwarding change %d failed: %v", i, err)
+		}
+		if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+		if want := i + 2; listCalls != want {
+			t.Errorf("Expected forwarding change %d to invalidate the cache, got %d list calls", i, listCalls)
+		}
+	}
+}
+
+func TestRecordCacheKeys(t *testing.T) {
+	listCalls := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsListRecords") {
+			listCalls++
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.CacheMaxAge = time.Minute
+	ctx := context.Background()
+
+	for _, zone := range []string{"Example.COM.", "example.com", "EXAMPLE.com."} {
+		if _, err := provider.GetRecords(ctx, zone); err != nil {
+			t.Fatalf("GetRecords(%s) failed: %v", zone, err)
+		}
+	}
+	if listCalls != 1 {
+		t.Errorf("Expected the spellings of a zone to share its cache entry, got %d list calls", listCalls)
+	}
+
+	provider.invalidateZone("EXAMPLE.COM.")
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if listCalls != 2 {
+		t.Errorf("Expected the invalidation to apply to every spelling, got %d list calls", listCalls)
+	}
+
+	// Fetches in flight are keyed the same way
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		provider.sharedFetch(ctx, "Example.com.", func() ([]libdns.Record, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+		close(done)
+	}()
+	<-started
+	provider.mu.Lock()
+	_, ok := provider.fetches["example.com"]
+	provider.mu.Unlock()
+	close(release)
+	<-done
+	if !ok {
+		t.Error("Expected the fetch in flight to be keyed by the normalized zone")
+	}
+}
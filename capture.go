@@ -0,0 +1,55 @@
+package namesilo
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// maxCapturedResponse is how much of a reply is attached to errors with
+// CaptureResponses
+const maxCapturedResponse = 4 << 10
+
+// rawCapturer is implemented by every response type through apiResponse,
+// keeping the body of the reply for CaptureResponses
+type rawCapturer interface {
+	setRawResponse(raw []byte)
+	rawResponse() []byte
+}
+
+// setRawResponse keeps the start of the raw reply body
+func (r *apiResponse) setRawResponse(raw []byte) {
+	if len(raw) > maxCapturedResponse {
+		cut := maxCapturedResponse
+		for cut > 0 && !utf8.RuneStart(raw[cut]) {
+			cut--
+		}
+		raw = raw[:cut]
+	}
+	r.raw = append([]byte(nil), raw...)
+}
+
+// rawResponse returns the kept reply body
+func (r *apiResponse) rawResponse() []byte {
+	return r.raw
+}
+
+// capturedResponse returns the raw reply to attach to an error for resp or
+// err, with the API key masked, or "" unless CaptureResponses is set
+func (p *Provider) capturedResponse(resp interface{}, err error) string {
+	if !p.CaptureResponses {
+		return ""
+	}
+
+	var raw string
+	if c, ok := resp.(rawCapturer); ok {
+		raw = string(c.rawResponse())
+	}
+	var statusErr *httpStatusError
+	if raw == "" && errors.As(err, &statusErr) {
+		raw = statusErr.Body
+		if len(raw) > maxCapturedResponse {
+			raw = raw[:maxCapturedResponse]
+		}
+	}
+	return p.redact(raw)
+}
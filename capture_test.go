@@ -0,0 +1,62 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCaptureResponses(t *testing.T) {
+	body := `<namesilo><request><operation>dnsListRecords</operation><ip>test-token</ip></request><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Response != "" {
+		t.Fatalf("Expected no response without CaptureResponses, got %v", err)
+	}
+
+	provider.CaptureResponses = true
+	_, err = provider.GetRecords(context.Background(), "example.com")
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if !strings.Contains(apiErr.Response, "<code>110</code>") || strings.Contains(apiErr.Response, "test-token") {
+		t.Errorf("Expected the redacted reply, got %q", apiErr.Response)
+	}
+}
+
+func TestCaptureResponsesDecodeError(t *testing.T) {
+	body := `<namesilo><reply><code>300</code><detail>` + strings.Repeat("x", 2*maxCapturedResponse)
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	provider.CaptureResponses = true
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError, got %v", err)
+	}
+	if len(reqErr.Response) != maxCapturedResponse || !strings.HasPrefix(body, reqErr.Response) {
+		t.Errorf("Expected the first %d bytes of the reply, got %d", maxCapturedResponse, len(reqErr.Response))
+	}
+}
+
+func TestCaptureResponsesHTTPStatus(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<namesilo><reply><code>115</code><detail>Registry down</detail></reply></namesilo>`))
+	})
+	provider.CaptureResponses = true
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || !strings.Contains(reqErr.Response, "Registry down") {
+		t.Errorf("Expected the error body to be captured, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+// Command namesilo manages DNS records of NameSilo zones from the command
+// line.
+//
+// Usage:
+//
+//	namesilo [flags] records list <zone>
+//	namesilo [flags] records add <zone> <name> <type> <data>
+//	namesilo [flags] records set <zone> <name> <type> <data>...
+//	namesilo [flags] records delete <zone> <name> [<type> [<data>]]
+//
+// The API key is read from the -key flag or the NAMESILO_API_KEY
+// environment variable. Record data uses zone file syntax, e.g.
+// "10 mail.example.com" for MX records.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo"
+)
+
+// errUsage is returned for invalid command lines; usage has been printed
+var errUsage = errors.New("invalid usage")
+
+const usage = `Usage:
+  namesilo [flags] records list <zone>
+  namesilo [flags] records add <zone> <name> <type> <data>
+  namesilo [flags] records set <zone> <name> <type> <data>...
+  namesilo [flags] records delete <zone> <name> [<type> [<data>]]
+
+Flags:
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := &cli{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	if err := c.run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "namesilo:", err)
+		os.Exit(1)
+	}
+}
+
+// cli holds the environment of a command invocation
+type cli struct {
+	stdout, stderr io.Writer
+	getenv         func(string) string
+	httpClient     *http.Client // nil for the default client
+}
+
+// run parses args and executes the command
+func (c *cli) run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("namesilo", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprint(c.stderr, usage)
+		fs.PrintDefaults()
+	}
+	key := fs.String("key", "", "NameSilo API key (default $NAMESILO_API_KEY)")
+	output := fs.String("o", "table", "output format: table or json")
+	ttl := fs.Duration("ttl", time.Hour, "TTL of added or set records")
+	sandbox := fs.Bool("sandbox", false, "use the NameSilo sandbox environment")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *output != "table" && *output != "json" {
+		return c.usageError(fs, "unknown output format %q", *output)
+	}
+
+	args = fs.Args()
+	if len(args) < 3 || args[0] != "records" {
+		return c.usageError(fs, "expected a records subcommand and a zone")
+	}
+	command, zone, args := args[1], args[2], args[3:]
+
+	provider := &namesilo.Provider{
+		APIToken:   *key,
+		HTTPClient: c.httpClient,
+		Sandbox:    *sandbox,
+	}
+	if provider.APIToken == "" {
+		provider.APIToken = c.getenv("NAMESILO_API_KEY")
+	}
+	if provider.APIToken == "" {
+		return errors.New("no API key: use -key or set NAMESILO_API_KEY")
+	}
+
+	var records []libdns.Record
+	var err error
+	switch command {
+	case "list":
+		if len(args) != 0 {
+			return c.usageError(fs, "list takes no arguments after the zone")
+		}
+		records, err = provider.GetRecords(ctx, zone)
+	case "add":
+		if len(args) != 3 {
+			return c.usageError(fs, "add expects <name> <type> <data>")
+		}
+		if records, err = parseRecords(args[0], args[1], args[2:], *ttl); err == nil {
+			records, err = provider.AppendRecords(ctx, zone, records)
+		}
+	case "set":
+		if len(args) < 3 {
+			return c.usageError(fs, "set expects <name> <type> <data>...")
+		}
+		if records, err = parseRecords(args[0], args[1], args[2:], *ttl); err == nil {
+			records, err = provider.SetRecords(ctx, zone, records)
+		}
+	case "delete":
+		if len(args) < 1 || len(args) > 3 {
+			return c.usageError(fs, "delete expects <name> [<type> [<data>]]")
+		}
+		rr := libdns.RR{Name: args[0]}
+		if len(args) > 1 {
+			rr.Type = strings.ToUpper(args[1])
+		}
+		if len(args) > 2 {
+			rr.Data = args[2]
+		}
+		records, err = provider.DeleteRecords(ctx, zone, []libdns.Record{rr})
+	default:
+		return c.usageError(fs, "unknown records subcommand %q", command)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output == "json" {
+		return writeJSON(c.stdout, records)
+	}
+	return writeTable(c.stdout, records)
+}
+
+// usageError prints a problem with the command line followed by the usage
+func (c *cli) usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(c.stderr, "namesilo: "+format+"\n", args...)
+	fs.Usage()
+	return errUsage
+}
+
+// parseRecords builds one record per data value
+func parseRecords(name, recordType string, data []string, ttl time.Duration) ([]libdns.Record, error) {
+	records := make([]libdns.Record, 0, len(data))
+	for _, d := range data {
+		rec, err := libdns.RR{Name: name, Type: strings.ToUpper(recordType), Data: d, TTL: ttl}.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s record data %q: %w", recordType, d, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// jsonRecord is the JSON output form of a record
+type jsonRecord struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	TTL  int    `json:"ttl"`
+	Data string `json:"data"`
+}
+
+// writeJSON prints records as a JSON array
+func writeJSON(w io.Writer, records []libdns.Record) error {
+	out := make([]jsonRecord, 0, len(records))
+	for _, rec := range records {
+		rr := rec.RR()
+		id, _ := namesilo.RecordID(rec)
+		out = append(out, jsonRecord{ID: id, Name: rr.Name, Type: rr.Type, TTL: int(rr.TTL.Seconds()), Data: rr.Data})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// writeTable prints records as aligned columns
+func writeTable(w io.Writer, records []libdns.Record) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tTTL\tTYPE\tDATA\tID")
+	for _, rec := range records {
+		rr := rec.RR()
+		id, _ := namesilo.RecordID(rec)
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", rr.Name, int(rr.TTL.Seconds()), rr.Type, rr.Data, id)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func newTestCLI(srv *namesilotest.Server) (*cli, *bytes.Buffer) {
+	var stdout bytes.Buffer
+	return &cli{
+		stdout:     &stdout,
+		stderr:     new(bytes.Buffer),
+		getenv:     func(string) string { return "test-key" },
+		httpClient: srv.Client(),
+	}, &stdout
+}
+
+func TestRecordsCommands(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600})
+	ctx := context.Background()
+
+	c, stdout := newTestCLI(srv)
+	if err := c.run(ctx, []string{"records", "add", "example.com", "@", "mx", "10 mail.example.com"}); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "mail.example.com") {
+		t.Errorf("Unexpected add output:\n%s", stdout)
+	}
+
+	c, _ = newTestCLI(srv)
+	if err := c.run(ctx, []string{"-ttl", "10m", "records", "set", "example.com", "www", "A", "192.0.2.2", "192.0.2.3"}); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	c, stdout = newTestCLI(srv)
+	if err := c.run(ctx, []string{"-o", "json", "records", "list", "example.com"}); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var listed []jsonRecord
+	if err := json.Unmarshal(stdout.Bytes(), &listed); err != nil {
+		t.Fatalf("Invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(listed) != 3 {
+		t.Fatalf("Expected 3 records, got %+v", listed)
+	}
+	for _, rec := range listed {
+		if rec.ID == "" {
+			t.Errorf("Record without ID: %+v", rec)
+		}
+		if rec.Type == "A" && rec.TTL != 600 {
+			t.Errorf("Unexpected TTL: %+v", rec)
+		}
+	}
+
+	c, _ = newTestCLI(srv)
+	if err := c.run(ctx, []string{"records", "delete", "example.com", "www", "A"}); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if records := srv.Records("example.com"); len(records) != 1 || records[0].Type != "MX" {
+		t.Errorf("Unexpected records after delete: %+v", records)
+	}
+
+	c, stdout = newTestCLI(srv)
+	if err := c.run(ctx, []string{"records", "list", "example.com"}); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout.String()), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "NAME") {
+		t.Errorf("Unexpected table output:\n%s", stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+
+	for _, args := range [][]string{
+		{},
+		{"records"},
+		{"domains", "list", "example.com"},
+		{"records", "list", "example.com", "extra"},
+		{"records", "add", "example.com", "www", "A"},
+		{"records", "bogus", "example.com"},
+		{"-o", "yaml", "records", "list", "example.com"},
+	} {
+		c, _ := newTestCLI(srv)
+		if err := c.run(context.Background(), args); !errors.Is(err, errUsage) {
+			t.Errorf("%q: expected usage error, got %v", args, err)
+		}
+	}
+
+	c, _ := newTestCLI(srv)
+	c.getenv = func(string) string { return "" }
+	if err := c.run(context.Background(), []string{"records", "list", "example.com"}); err == nil || errors.Is(err, errUsage) {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,277 @@
+package namesilo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CaddyfileDispenser is the part of Caddy's *caddyfile.Dispenser used by
+// UnmarshalCaddyfile, so that this package does not depend on Caddy.
+type CaddyfileDispenser interface {
+	Next() bool
+	NextArg() bool
+	NextBlock(initialNestingLevel int) bool
+	Nesting() int
+	Val() string
+	RemainingArgs() []string
+	ArgErr() error
+	Errf(format string, args ...interface{}) error
+}
+
+// caddyfileAliases are Caddyfile subdirectives that set a field under a
+// different name than its JSON key, since they take one entry at a time
+var caddyfileAliases = map[string]string{
+	"header":                 "headers",
+	"subzone":                "subzones",
+	"propagation_nameserver": "propagation_nameservers",
+}
+
+// UnmarshalCaddyfile sets up the Provider from Caddyfile tokens:
+//
+//	namesilo [<api_token>] {
+//	    api_token <api_token>
+//	    timeout 10s
+//	    ttl_policy clamp
+//	    header X-Gateway-Token {env.GATEWAY_TOKEN}
+//	    subzone dev.example.com example.com
+//	    account {env.OTHER_ACCOUNT_TOKEN} example.org *.example.net
+//	    sandbox
+//	    ...
+//	}
+//
+// Every option that can be set in JSON is a subdirective of the same name,
+// with durations such as "90s" or "1d". Flags take an optional true or
+// false, header and subzone take a key and a value, and account takes the
+// API key of one of the Accounts followed by its zones. A Caddy module
+// wrapping the Provider can implement caddyfile.Unmarshaler by passing its
+// *caddyfile.Dispenser to this method.
+func (p *Provider) UnmarshalCaddyfile(d CaddyfileDispenser) error {
+	for d.Next() {
+		if d.NextArg() {
+			p.APIToken = d.Val()
+		}
+		if d.NextArg() {
+			return d.ArgErr()
+		}
+
+		for nesting := d.Nesting(); d.NextBlock(nesting); {
+			name := d.Val()
+			if name == "account" {
+				// account <api_token> <zone...>
+				args := d.RemainingArgs()
+				if len(args) < 2 {
+					return d.ArgErr()
+				}
+				p.Accounts = append(p.Accounts, Account{APIToken: args[0], Zones: args[1:]})
+				continue
+			}
+			if alias, ok := caddyfileAliases[name]; ok {
+				name = alias
+			}
+			field, ok := configField(p, name)
+			if !ok {
+				return d.Errf("unrecognized namesilo option %q", d.Val())
+			}
+			if err := setConfigField(field, d.RemainingArgs()); err != nil {
+				return d.Errf("%s: %v", d.Val(), err)
+			}
+		}
+	}
+	return p.checkConfig()
+}
+
+// UnmarshalJSON decodes the Provider like encoding/json would, except that
+// durations may also be given as strings such as "90s" or "1d", and that
+// unknown TTL policies and snapping modes, key rotations and response
+// formats are rejected.
+func (p *Provider) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for name, raw := range fields {
+		field, ok := configField(p, name)
+		if !ok {
+			continue
+		}
+		var texts []string
+		switch field.Type() {
+		case durationType:
+			var text string
+			if json.Unmarshal(raw, &text) != nil {
+				continue
+			}
+			texts = []string{text}
+		case durationsType:
+			if json.Unmarshal(raw, &texts) != nil || len(texts) == 0 {
+				continue
+			}
+			field.SetLen(0)
+		default:
+			continue
+		}
+		if err := setConfigField(field, texts); err != nil {
+			return fmt.Errorf("namesilo: %s: %v", name, err)
+		}
+		delete(fields, name)
+	}
+
+	// Decode the remaining fields with the default rules, through a type
+	// without this method
+	type plain Provider
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	if err := json.NewDecoder(bytes.NewReader(rest)).Decode((*plain)(p)); err != nil {
+		return err
+	}
+	return p.checkConfig()
+}
+
+// checkConfig rejects option values that would otherwise be ignored
+func (p *Provider) checkConfig() error {
+	switch p.TTLPolicy {
+	case "", TTLUseDefault, TTLClamp, TTLError:
+	default:
+		return fmt.Errorf("namesilo: unknown TTL policy %q", p.TTLPolicy)
+	}
+	switch p.TTLSnap {
+	case TTLSnapOff, TTLSnapNearest, TTLSnapError:
+	default:
+		return fmt.Errorf("namesilo: unknown TTL snapping mode %q", p.TTLSnap)
+	}
+	switch p.KeyRotation {
+	case "", KeyFailover, KeyRoundRobin:
+	default:
+		return fmt.Errorf("namesilo: unknown key rotation %q", p.KeyRotation)
+	}
+	switch p.ResponseFormat {
+	case "", FormatXML, FormatJSON:
+	default:
+		return fmt.Errorf("namesilo: unknown response format %q", p.ResponseFormat)
+	}
+	if p.RetryJitter < 0 || p.RetryJitter > 1 {
+		return fmt.Errorf("namesilo: retry jitter %v is not between 0 and 1", p.RetryJitter)
+	}
+	return p.checkAccounts()
+}
+
+// Types of the Provider's options that need more than a conversion from
+// text
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	durationsType = reflect.TypeOf([]time.Duration(nil))
+	stringsType   = reflect.TypeOf([]string(nil))
+)
+
+// configField returns the field of p with the JSON name name
+func configField(p *Provider, name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(p).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if tag != "" && tag != "-" && tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setConfigField sets an option from its textual arguments
+func setConfigField(field reflect.Value, args []string) error {
+	switch {
+	case field.Kind() == reflect.Bool:
+		if len(args) == 0 {
+			field.SetBool(true)
+			return nil
+		}
+		if len(args) > 1 {
+			return fmt.Errorf("expected at most one argument")
+		}
+		b, err := strconv.ParseBool(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid flag %q", args[0])
+		}
+		field.SetBool(b)
+		return nil
+	case field.Type() == durationsType:
+		if len(args) == 0 {
+			return fmt.Errorf("expected at least one argument")
+		}
+		for _, arg := range args {
+			d, err := parseDuration(arg)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.Append(field, reflect.ValueOf(d)))
+		}
+		return nil
+	case field.Type() == stringsType:
+		if len(args) == 0 {
+			return fmt.Errorf("expected at least one argument")
+		}
+		field.Set(reflect.AppendSlice(field, reflect.ValueOf(args)))
+		return nil
+	case field.Kind() == reflect.Map:
+		if len(args) != 2 {
+			return fmt.Errorf("expected a name and a value")
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(args[0]), reflect.ValueOf(args[1]))
+		return nil
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("expected one argument")
+	}
+	arg := args[0]
+	switch {
+	case field.Type() == durationType:
+		d, err := parseDuration(arg)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(arg)
+	case field.CanInt():
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", arg)
+		}
+		field.SetInt(n)
+	case field.CanFloat():
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", arg)
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("cannot be set from text")
+	}
+	return nil
+}
+
+// parseDuration parses a duration like time.ParseDuration, additionally
+// accepting whole days as in Caddy, e.g. "1d"
+func parseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,235 @@
+package namesilo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDispenser mimics caddyfile.Dispenser for Caddyfiles without quoted
+// tokens
+type testDispenser struct {
+	tokens  []string
+	lines   []int
+	cursor  int
+	nesting int
+}
+
+func newTestDispenser(input string) *testDispenser {
+	d := &testDispenser{cursor: -1}
+	for i, line := range strings.Split(input, "\n") {
+		for _, token := range strings.Fields(line) {
+			d.tokens = append(d.tokens, token)
+			d.lines = append(d.lines, i)
+		}
+	}
+	return d
+}
+
+func (d *testDispenser) Next() bool {
+	if d.cursor < len(d.tokens)-1 {
+		d.cursor++
+		return true
+	}
+	return false
+}
+
+func (d *testDispenser) nextOnSameLine() bool {
+	if d.cursor < 0 {
+		d.cursor++
+		return true
+	}
+	if d.cursor >= len(d.tokens)-1 || d.lines[d.cursor+1] != d.lines[d.cursor] {
+		return false
+	}
+	d.cursor++
+	return true
+}
+
+func (d *testDispenser) NextArg() bool {
+	if !d.nextOnSameLine() {
+		return false
+	}
+	if d.Val() == "{" {
+		d.cursor--
+		return false
+	}
+	return true
+}
+
+func (d *testDispenser) NextBlock(initialNestingLevel int) bool {
+	if d.nesting > initialNestingLevel {
+		if !d.Next() {
+			return false
+		}
+		if d.Val() == "}" {
+			d.nesting--
+			return d.nesting > initialNestingLevel
+		}
+		return true
+	}
+	if !d.nextOnSameLine() {
+		return false
+	}
+	if d.Val() != "{" {
+		d.cursor--
+		return false
+	}
+	d.Next()
+	if d.Val() == "}" {
+		return false
+	}
+	d.nesting++
+	return true
+}
+
+func (d *testDispenser) Nesting() int { return d.nesting }
+func (d *testDispenser) Val() string  { return d.tokens[d.cursor] }
+
+func (d *testDispenser) RemainingArgs() []string {
+	var args []string
+	for d.NextArg() {
+		args = append(args, d.Val())
+	}
+	return args
+}
+
+func (d *testDispenser) ArgErr() error { return errors.New("wrong argument count") }
+
+func (d *testDispenser) Errf(format string, args ...interface{}) error {
+	return fmt.Errorf(format, args...)
+}
+
+func TestUnmarshalCaddyfile(t *testing.T) {
+	var p Provider
+	err := p.UnmarshalCaddyfile(newTestDispenser(`namesilo {env.NAMESILO_API_TOKEN} {
+	api_tokens second third
+	key_rotation round_robin
+	timeout 10s
+	max_retries 3
+	retry_base_delay 500ms
+	requests_per_second 2.5
+	max_response_size 1048576
+	ttl_policy clamp
+	min_ttl 10m
+	ttl_snap nearest
+	accepted_ttls 10m 1h
+	accepted_ttls 1d
+	cache_max_age 1d
+	header X-Gateway-Token secret
+	header X-Team dns
+	subzone dev.example.com example.com
+	propagation_nameserver ns1.example.net
+	sandbox
+	use_post true
+	strict_decoding false
+}`))
+	if err != nil {
+		t.Fatalf("UnmarshalCaddyfile failed: %v", err)
+	}
+
+	want := Provider{
+		APIToken:               "{env.NAMESILO_API_TOKEN}",
+		APITokens:              []string{"second", "third"},
+		KeyRotation:            KeyRoundRobin,
+		Timeout:                10 * time.Second,
+		MaxRetries:             3,
+		RetryBaseDelay:         500 * time.Millisecond,
+		RequestsPerSecond:      2.5,
+		MaxResponseSize:        1 << 20,
+		TTLPolicy:              TTLClamp,
+		MinTTL:                 10 * time.Minute,
+		TTLSnap:                TTLSnapNearest,
+		AcceptedTTLs:           []time.Duration{10 * time.Minute, time.Hour, 24 * time.Hour},
+		CacheMaxAge:            24 * time.Hour,
+		Headers:                map[string]string{"X-Gateway-Token": "secret", "X-Team": "dns"},
+		Subzones:               map[string]string{"dev.example.com": "example.com"},
+		PropagationNameservers: []string{"ns1.example.net"},
+		Sandbox:                true,
+		UsePOST:                true,
+	}
+	if !reflect.DeepEqual(&p, &want) {
+		t.Errorf("UnmarshalCaddyfile = %+v\nwant %+v", &p, &want)
+	}
+}
+
+func TestUnmarshalCaddyfileErrors(t *testing.T) {
+	for _, input := range []string{
+		"namesilo token extra",
+		"namesilo {\n\tno_such_option 1\n}",
+		"namesilo {\n\tmax_retries many\n}",
+		"namesilo {\n\ttimeout soon\n}",
+		"namesilo {\n\tsandbox maybe\n}",
+		"namesilo {\n\theader X-Only-Name\n}",
+		"namesilo {\n\tttl_policy round\n}",
+		"namesilo {\n\tapi_token\n}",
+		"namesilo {\n\ttoken_source x\n}",
+	} {
+		var p Provider
+		if err := p.UnmarshalCaddyfile(newTestDispenser(input)); err == nil {
+			t.Errorf("Expected an error for %q", input)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var p Provider
+	err := json.Unmarshal([]byte(`{
+		"api_token": "token",
+		"timeout": "15s",
+		"min_ttl": 600000000000,
+		"default_ttl": "2h",
+		"breaker_cooldown": "1d",
+		"ttl_policy": "error",
+		"ttl_snap": "error",
+		"accepted_ttls": ["5m", "1h"],
+		"sandbox": true,
+		"headers": {"X-Team": "dns"}
+	}`), &p)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if p.APIToken != "token" || p.Timeout != 15*time.Second || p.MinTTL != 10*time.Minute || p.DefaultTTL != 2*time.Hour ||
+		p.BreakerCooldown != 24*time.Hour || p.TTLPolicy != TTLError || !p.Sandbox || p.Headers["X-Team"] != "dns" ||
+		p.TTLSnap != TTLSnapError || !reflect.DeepEqual(p.AcceptedTTLs, []time.Duration{5 * time.Minute, time.Hour}) {
+		t.Errorf("Unexpected provider %+v", &p)
+	}
+
+	// Marshaled configurations decode to the same Provider
+	data, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again Provider
+	if err := json.Unmarshal(data, &again); err != nil || !reflect.DeepEqual(&again, &p) {
+		t.Errorf("Round trip through %s = %+v, %v", data, &again, err)
+	}
+
+	for _, input := range []string{
+		`{"timeout": "soon"}`,
+		`{"ttl_policy": "round"}`,
+		`{"key_rotation": "random"}`,
+		`{"ttl_snap": "round"}`,
+		`{"accepted_ttls": ["5m", "later"]}`,
+		`{"response_format": "yaml"}`,
+		`{"max_retries": "3"}`,
+	} {
+		var p Provider
+		if err := json.Unmarshal([]byte(input), &p); err == nil {
+			t.Errorf("Expected an error for %s", input)
+		}
+	}
+}
+
+func TestDefaultClientTimeout(t *testing.T) {
+	if got := (&Provider{}).httpClient().Timeout; got != defaultTimeout {
+		t.Errorf("Expected the default timeout, got %v", got)
+	}
+	if got := (&Provider{Timeout: 5 * time.Second}).httpClient().Timeout; got != 5*time.Second {
+		t.Errorf("Expected Timeout to be used, got %v", got)
+	}
+}
@@ -0,0 +1,207 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Contact is a contact profile used for domain registrant, administrative,
+// billing and technical contacts.
+type Contact struct {
+	ID        string // Assigned by NameSilo; required for updates
+	Default   bool   // Whether this is the account's default profile (read-only)
+	Nickname  string
+	Company   string
+	FirstName string
+	LastName  string
+	Address   string
+	Address2  string
+	City      string
+	State     string
+	Zip       string
+	Country   string // Two-letter country code
+	Email     string
+	Phone     string
+	Fax       string
+}
+
+// validate checks that the fields NameSilo requires are set
+func (c Contact) validate() error {
+	required := []struct{ name, value string }{
+		{"first name", c.FirstName},
+		{"last name", c.LastName},
+		{"address", c.Address},
+		{"city", c.City},
+		{"state", c.State},
+		{"zip", c.Zip},
+		{"country", c.Country},
+		{"email", c.Email},
+		{"phone", c.Phone},
+	}
+
+	var missing []string
+	for _, field := range required {
+		if strings.TrimSpace(field.value) == "" {
+			missing = append(missing, field.name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("contact is missing required fields: %s", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// params returns the API parameters describing the contact
+func (c Contact) params() map[string]string {
+	return map[string]string{
+		"nn":  c.Nickname,
+		"cp":  c.Company,
+		"fn":  c.FirstName,
+		"ln":  c.LastName,
+		"ad":  c.Address,
+		"ad2": c.Address2,
+		"cy":  c.City,
+		"st":  c.State,
+		"zp":  c.Zip,
+		"ct":  c.Country,
+		"em":  c.Email,
+		"ph":  c.Phone,
+		"fx":  c.Fax,
+	}
+}
+
+// contactRecord represents a contact from NameSilo API
+type contactRecord struct {
+	ID        string `xml:"contact_id"`
+	Default   int    `xml:"default_profile"`
+	Nickname  string `xml:"nickname"`
+	Company   string `xml:"company"`
+	FirstName string `xml:"first_name"`
+	LastName  string `xml:"last_name"`
+	Address   string `xml:"address"`
+	Address2  string `xml:"address2"`
+	City      string `xml:"city"`
+	State     string `xml:"state"`
+	Zip       string `xml:"zip"`
+	Country   string `xml:"country"`
+	Email     string `xml:"email"`
+	Phone     string `xml:"phone"`
+	Fax       string `xml:"fax"`
+}
+
+// contactListResponse represents the response from contactList
+type contactListResponse struct {
+	apiResponse
+	Contacts []contactRecord `xml:"reply>contact"`
+}
+
+// contactAddResponse represents the response from contactAdd
+type contactAddResponse struct {
+	apiResponse
+	ContactID string `xml:"reply>contact_id"`
+}
+
+// ContactList lists the contact profiles of the account.
+func (p *Provider) ContactList(ctx context.Context) ([]Contact, error) {
+	var response contactListResponse
+	if err := p.callAPI(ctx, "contactList", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	contacts := make([]Contact, 0, len(response.Contacts))
+	for _, c := range response.Contacts {
+		contacts = append(contacts, Contact{
+			ID:        c.ID,
+			Default:   c.Default == 1,
+			Nickname:  c.Nickname,
+			Company:   c.Company,
+			FirstName: c.FirstName,
+			LastName:  c.LastName,
+			Address:   c.Address,
+			Address2:  c.Address2,
+			City:      c.City,
+			State:     c.State,
+			Zip:       c.Zip,
+			Country:   c.Country,
+			Email:     c.Email,
+			Phone:     c.Phone,
+			Fax:       c.Fax,
+		})
+	}
+
+	return contacts, nil
+}
+
+// ContactAdd creates a contact profile and returns its ID.
+func (p *Provider) ContactAdd(ctx context.Context, contact Contact) (string, error) {
+	if err := contact.validate(); err != nil {
+		return "", err
+	}
+
+	var response contactAddResponse
+	if err := p.callAPI(ctx, "contactAdd", contact.params(), &response); err != nil {
+		return "", err
+	}
+
+	return response.ContactID, nil
+}
+
+// ContactUpdate replaces the details of the contact profile identified by contact.ID.
+func (p *Provider) ContactUpdate(ctx context.Context, contact Contact) error {
+	if contact.ID == "" {
+		return fmt.Errorf("contact ID is required")
+	}
+	if err := contact.validate(); err != nil {
+		return err
+	}
+
+	params := contact.params()
+	params["contact_id"] = contact.ID
+
+	var response apiResponse
+	return p.callAPI(ctx, "contactUpdate", params, &response)
+}
+
+// ContactDelete deletes a contact profile. Profiles in use by domains or
+// the account default cannot be deleted.
+func (p *Provider) ContactDelete(ctx context.Context, contactID string) error {
+	if contactID == "" {
+		return fmt.Errorf("contact ID is required")
+	}
+
+	params := map[string]string{
+		"contact_id": contactID,
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "contactDelete", params, &response)
+}
+
+// ContactAssociation assigns contact profiles to the roles of a domain.
+// Empty roles are left unchanged.
+type ContactAssociation struct {
+	Registrant     string
+	Administrative string
+	Billing        string
+	Technical      string
+}
+
+// ContactDomainAssociate assigns contact profiles to a domain.
+func (p *Provider) ContactDomainAssociate(ctx context.Context, domain string, assoc ContactAssociation) error {
+	if assoc == (ContactAssociation{}) {
+		return fmt.Errorf("at least one contact role is required")
+	}
+
+	params := map[string]string{
+		"domain":         strings.TrimSuffix(domain, "."),
+		"registrant":     assoc.Registrant,
+		"administrative": assoc.Administrative,
+		"billing":        assoc.Billing,
+		"technical":      assoc.Technical,
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "contactDomainAssociate", params, &response)
+}
@@ -0,0 +1,86 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestContactList(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<contact><contact_id>123</contact_id><default_profile>1</default_profile><nickname>Main</nickname><first_name>Jane</first_name><last_name>Doe</last_name>
+<address>1 Main St</address><city>Phoenix</city><state>AZ</state><zip>85001</zip><country>US</country><email>jane@example.com</email><phone>5555555555</phone></contact>
+<contact><contact_id>456</contact_id><default_profile>0</default_profile><first_name>John</first_name></contact>
+</reply></namesilo>`))
+	})
+
+	contacts, err := provider.ContactList(context.Background())
+	if err != nil {
+		t.Fatalf("ContactList failed: %v", err)
+	}
+	if len(contacts) != 2 {
+		t.Fatalf("Expected 2 contacts, got %d", len(contacts))
+	}
+	if c := contacts[0]; c.ID != "123" || !c.Default || c.FirstName != "Jane" || c.Country != "US" {
+		t.Errorf("Unexpected contact: %+v", c)
+	}
+	if contacts[1].Default {
+		t.Error("Expected second contact not to be the default")
+	}
+}
+
+func TestContactAddAndUpdate(t *testing.T) {
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><contact_id>789</contact_id></reply></namesilo>`))
+	})
+
+	contact := Contact{
+		FirstName: "Jane", LastName: "Doe", Address: "1 Main St", City: "Phoenix",
+		State: "AZ", Zip: "85001", Country: "US", Email: "jane@example.com", Phone: "5555555555",
+	}
+
+	id, err := provider.ContactAdd(context.Background(), contact)
+	if err != nil {
+		t.Fatalf("ContactAdd failed: %v", err)
+	}
+	if id != "789" || query["fn"][0] != "Jane" || query["em"][0] != "jane@example.com" {
+		t.Errorf("Unexpected result %q for query %v", id, query)
+	}
+
+	if err := provider.ContactUpdate(context.Background(), contact); err == nil {
+		t.Error("Expected error updating a contact without ID")
+	}
+	if _, err := provider.ContactAdd(context.Background(), Contact{FirstName: "Jane"}); err == nil {
+		t.Error("Expected error adding an incomplete contact")
+	}
+
+	contact.ID = id
+	if err := provider.ContactUpdate(context.Background(), contact); err != nil {
+		t.Fatalf("ContactUpdate failed: %v", err)
+	}
+	if query["contact_id"][0] != "789" {
+		t.Errorf("Expected contact_id=789, got %v", query)
+	}
+}
+
+func TestContactDomainAssociate(t *testing.T) {
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	err := provider.ContactDomainAssociate(context.Background(), "example.com", ContactAssociation{Registrant: "1", Technical: "2"})
+	if err != nil {
+		t.Fatalf("ContactDomainAssociate failed: %v", err)
+	}
+	if query["registrant"][0] != "1" || query["technical"][0] != "2" {
+		t.Errorf("Unexpected parameters: %v", query)
+	}
+	if _, ok := query["billing"]; ok {
+		t.Error("Expected empty roles to be omitted")
+	}
+}
@@ -0,0 +1,94 @@
+package namesilo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxSnippetLength is the length of the body excerpt kept in a
+	// ContentTypeError
+	maxSnippetLength = 200
+
+	// contentSniffLength is how much of a reply is inspected to tell
+	// whether it is an API reply
+	contentSniffLength = 512
+
+	// maxErrorPageLength is how much of an error page is read to find
+	// its title
+	maxErrorPageLength = 8 << 10
+)
+
+// ContentTypeError is returned when a reply is not an API reply in the
+// expected format, typically an HTML error page served while NameSilo is
+// down or by a proxy that intercepted the request.
+type ContentTypeError struct {
+	StatusCode  int    // HTTP status of the reply
+	ContentType string // Content-Type header of the reply
+	Snippet     string // start of the body, or the title of an HTML page
+
+	err error // the HTTP status error for non-200 replies
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("namesilo: reply is not an API reply (HTTP %d, Content-Type %q): %s", e.StatusCode, e.ContentType, e.Snippet)
+}
+
+// Unwrap returns the HTTP status error of a non-200 reply, so that retries
+// and the circuit breaker treat it like any other failed request
+func (e *ContentTypeError) Unwrap() error {
+	return e.err
+}
+
+// unexpectedContent reports whether a reply whose body starts with prefix
+// is not a reply in format: an HTML page, or a body that does not start the
+// way an XML or JSON document does. The Content-Type header is not relied
+// on, as API replies are not always labeled correctly. Empty bodies are
+// left to the decoder.
+func unexpectedContent(format ResponseFormat, prefix []byte) bool {
+	prefix = bytes.TrimLeft(prefix, " \t\r\n\ufeff")
+	if len(prefix) == 0 {
+		return false
+	}
+	if format == FormatJSON {
+		return prefix[0] != '{'
+	}
+	if bytes.HasPrefix(prefix, []byte("<?xml")) {
+		if end := bytes.Index(prefix, []byte("?>")); end >= 0 {
+			prefix = bytes.TrimLeft(prefix[end+2:], " \t\r\n")
+		}
+	}
+	return prefix[0] != '<' || looksLikeHTML(prefix)
+}
+
+// looksLikeHTML reports whether a body starts like an HTML document
+func looksLikeHTML(prefix []byte) bool {
+	lower := bytes.ToLower(prefix)
+	return bytes.HasPrefix(lower, []byte("<!doctype html")) || bytes.HasPrefix(lower, []byte("<html"))
+}
+
+// bodySnippet returns the title of an HTML page, or else the start of the
+// body with whitespace collapsed
+func bodySnippet(body []byte) string {
+	text := string(body)
+	lower := strings.ToLower(text)
+	if start := strings.Index(lower, "<title>"); start >= 0 {
+		if end := strings.Index(lower[start:], "</title>"); end >= 0 {
+			if title := strings.Join(strings.Fields(text[start+len("<title>"):start+end]), " "); title != "" {
+				return title
+			}
+		}
+	}
+
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= maxSnippetLength {
+		return text
+	}
+	cut := maxSnippetLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
@@ -0,0 +1,79 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestContentTypeError(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html><head><title>502 Bad
+  Gateway</title></head><body><h1>Bad Gateway</h1></body></html>`
+
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		wantSnippet string
+		wantStatus  bool // errors.As finds the HTTP status error
+	}{
+		{"HTML error page", http.StatusOK, "text/html", page, "502 Bad Gateway", false},
+		{"HTML with XML declaration", http.StatusOK, "application/xml", `<?xml version="1.0"?>` + "\n<html><body>Maintenance</body></html>", "<?xml", false},
+		{"plain text", http.StatusOK, "text/plain", "Service temporarily unavailable", "Service temporarily unavailable", false},
+		{"gateway error", http.StatusBadGateway, "text/html; charset=utf-8", page, "502 Bad Gateway", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+			provider.MaxRetries = -1
+
+			_, err := provider.GetRecords(context.Background(), "example.com")
+			var contentErr *ContentTypeError
+			if !errors.As(err, &contentErr) {
+				t.Fatalf("Expected a ContentTypeError, got %v", err)
+			}
+			if contentErr.StatusCode != tt.status || contentErr.ContentType != tt.contentType {
+				t.Errorf("Unexpected status or content type: %+v", contentErr)
+			}
+			if !strings.HasPrefix(contentErr.Snippet, tt.wantSnippet) {
+				t.Errorf("Expected snippet starting with %q, got %q", tt.wantSnippet, contentErr.Snippet)
+			}
+			var statusErr *httpStatusError
+			if errors.As(err, &statusErr) != tt.wantStatus {
+				t.Errorf("Expected HTTP status error to be found: %v", tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestContentTypeErrorIgnoresLabel(t *testing.T) {
+	// API replies served as text/html are still decoded
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<?xml version="1.0"?><namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Errorf("GetRecords failed: %v", err)
+	}
+}
+
+func TestBodySnippet(t *testing.T) {
+	long := strings.Repeat("é", 150)
+	got := bodySnippet([]byte(long))
+	if !strings.HasSuffix(got, "...") || !strings.HasPrefix(long, strings.TrimSuffix(got, "...")) || len(got) > maxSnippetLength+3 {
+		t.Errorf("Unexpected snippet %q", got)
+	}
+	if got := bodySnippet([]byte("<html><title></title>  error \n page</html>")); got != "<html><title></title> error page</html>" {
+		t.Errorf("Unexpected snippet %q", got)
+	}
+}
@@ -0,0 +1,91 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// defaultIPDetectors are used when Provider.IPDetectors is empty
+var defaultIPDetectors = []IPDetector{HTTPSEchoDetector{}, STUNDetector{}}
+
+// UpdateHostIP points host in zone at the public IPv4 and IPv6 addresses of
+// this machine, as found by IPDetectors. The A and AAAA records are only
+// changed when the address differs, so it is cheap to call periodically.
+// An address family that cannot be detected (commonly IPv6) is left
+// alone; it is an error only if neither is found.
+//
+// It returns the records that were changed.
+func (p *Provider) UpdateHostIP(ctx context.Context, zone, host string) ([]libdns.Record, error) {
+	detectors := p.IPDetectors
+	if len(detectors) == 0 {
+		detectors = defaultIPDetectors
+	}
+
+	var addrs []netip.Addr
+	var errs []error
+	for _, network := range []string{"ip4", "ip6"} {
+		addr, err := detectIP(ctx, detectors, network)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("failed to detect the public IP address: %w", errors.Join(errs...))
+	}
+
+	existing, err := p.GetRecordsByName(ctx, zone, host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
+	}
+
+	var changed []libdns.Record
+	for _, addr := range addrs {
+		want := libdns.Address{Name: host, IP: addr, TTL: time.Duration(p.defaultRecordTTL()) * time.Second}
+		recordType := want.RR().Type
+
+		var current []libdns.Record
+		for _, rec := range existing {
+			if rec.RR().Type == recordType {
+				current = append(current, rec)
+			}
+		}
+		if len(current) == 1 && p.sameData(zone, current[0], want) {
+			continue
+		}
+		if len(current) > 0 {
+			// Keep the TTL chosen for the record
+			want.TTL = current[0].RR().TTL
+		}
+
+		set, err := p.SetRecords(ctx, zone, []libdns.Record{want})
+		if err != nil {
+			return changed, err
+		}
+		changed = append(changed, set...)
+	}
+
+	return changed, nil
+}
+
+// detectIP returns the address found by the first detector that succeeds
+func detectIP(ctx context.Context, detectors []IPDetector, network string) (netip.Addr, error) {
+	var errs []error
+	for _, detector := range detectors {
+		addr, err := detector.DetectIP(ctx, network)
+		if err == nil {
+			return addr, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%s: %w", network, errors.Join(errs...))
+}
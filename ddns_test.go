@@ -0,0 +1,69 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestUpdateHostIP(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "home", Value: "192.0.2.1", TTL: 600},
+	)
+
+	ipv4 := netip.MustParseAddr("192.0.2.1")
+	provider := &Provider{
+		APIToken:   "test",
+		HTTPClient: srv.Client(),
+		IPDetectors: []IPDetector{
+			IPDetectorFunc(func(ctx context.Context, network string) (netip.Addr, error) {
+				return netip.Addr{}, errors.New("unavailable")
+			}),
+			IPDetectorFunc(func(ctx context.Context, network string) (netip.Addr, error) {
+				if network == "ip6" {
+					return netip.Addr{}, errors.New("no IPv6")
+				}
+				return ipv4, nil
+			}),
+		},
+	}
+	ctx := context.Background()
+
+	changed, err := provider.UpdateHostIP(ctx, "example.com.", "home")
+	if err != nil {
+		t.Fatalf("UpdateHostIP failed: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("Expected no change, got %v", changed)
+	}
+
+	ipv4 = netip.MustParseAddr("198.51.100.7")
+	changed, err = provider.UpdateHostIP(ctx, "example.com.", "home")
+	if err != nil {
+		t.Fatalf("UpdateHostIP failed: %v", err)
+	}
+	if len(changed) != 1 {
+		t.Fatalf("Expected 1 changed record, got %v", changed)
+	}
+	records := srv.Records("example.com")
+	if len(records) != 1 || records[0].Value != "198.51.100.7" || records[0].TTL != 600 {
+		t.Errorf("Unexpected records: %+v", records)
+	}
+}
+
+func TestUpdateHostIPDetectionFailure(t *testing.T) {
+	provider := &Provider{
+		APIToken: "test",
+		IPDetectors: []IPDetector{IPDetectorFunc(func(ctx context.Context, network string) (netip.Addr, error) {
+			return netip.Addr{}, errors.New("offline")
+		})},
+	}
+	if _, err := provider.UpdateHostIP(context.Background(), "example.com", "home"); err == nil {
+		t.Error("Expected error when no address can be detected")
+	}
+}
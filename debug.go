@@ -0,0 +1,69 @@
+package namesilo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maskedURL returns the request URL with the API key masked
+func (p *Provider) maskedURL(u *url.URL) string {
+	masked := *u
+	q := masked.Query()
+	if q.Has("key") {
+		q.Set("key", redactedToken)
+	}
+	masked.RawQuery = q.Encode()
+	return masked.String()
+}
+
+// maskedForm returns the form body of a POST request with the API key masked
+func (p *Provider) maskedForm(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return ""
+	}
+	form, err := url.ParseQuery(string(data))
+	if err != nil {
+		return ""
+	}
+	if form.Has("key") {
+		form.Set("key", redactedToken)
+	}
+	return form.Encode()
+}
+
+// debugDump writes a request and its raw response to DebugWriter. status is
+// 0 and body holds the error message if no response was received.
+func (p *Provider) debugDump(req *http.Request, status int, body []byte, elapsed time.Duration) {
+	if p.DebugWriter == nil {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, ">>> %s %s\n", req.Method, p.maskedURL(req.URL))
+	if form := p.maskedForm(req); form != "" {
+		fmt.Fprintf(&b, "%s\n", form)
+	}
+	if status == 0 {
+		fmt.Fprintf(&b, "<<< no response (%s): %s\n\n", elapsed.Round(time.Millisecond), p.redact(string(body)))
+	} else {
+		fmt.Fprintf(&b, "<<< %d %s (%s)\n%s\n\n", status, http.StatusText(status), elapsed.Round(time.Millisecond), strings.TrimRight(p.redact(string(body)), "\n"))
+	}
+
+	// A single write keeps dumps of concurrent requests from interleaving
+	// on writers such as os.Stderr
+	p.DebugWriter.Write([]byte(b.String()))
+}
@@ -0,0 +1,36 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDebugWriter(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domains><domain>example.com</domain></domains></reply></namesilo>`))
+	})
+	var buf bytes.Buffer
+	provider.DebugWriter = &buf
+
+	if _, err := provider.ListDomains(context.Background(), ListDomainsOptions{}); err != nil {
+		t.Fatalf("ListDomains failed: %v", err)
+	}
+
+	dump := buf.String()
+	if strings.Contains(dump, provider.APIToken) {
+		t.Fatalf("API key leaked into debug output: %s", dump)
+	}
+	for _, want := range []string{
+		">>> GET https://www.namesilo.com/api/listDomains?",
+		"key=" + redactedToken,
+		"<<< 200 OK",
+		"<domain>example.com</domain>",
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("Debug output missing %q:\n%s", want, dump)
+		}
+	}
+}
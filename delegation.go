@@ -0,0 +1,190 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// challengeLabel is the leftmost label of ACME DNS-01 challenge records
+const challengeLabel = "_acme-challenge"
+
+// maxDelegationHops bounds the number of CNAMEs followed from a challenge name
+const maxDelegationHops = 8
+
+// delegatedRecord is a challenge record redirected to the target of a CNAME
+type delegatedRecord struct {
+	original libdns.Record
+	zone     string
+	record   libdns.Record
+}
+
+// withChallengeDelegation applies op to records. With FollowChallengeCNAME
+// set, _acme-challenge TXT records whose name is a CNAME are written at the
+// CNAME target instead. Delegated records are applied with one call of op per
+// target zone, so that several values at one name form a single RRset, and
+// are reported under their original names, so that the caller can pass them
+// back unchanged (e.g. to DeleteRecords).
+func (p *Provider) withChallengeDelegation(ctx context.Context, zone string, records []libdns.Record, op func(context.Context, string, []libdns.Record) ([]libdns.Record, error)) ([]libdns.Record, error) {
+	if !p.FollowChallengeCNAME {
+		return op(ctx, zone, records)
+	}
+
+	var direct []libdns.Record
+	var delegated []delegatedRecord
+	var zones []string
+	byZone := make(map[string][]delegatedRecord)
+	var domains []string
+	for _, record := range records {
+		if !isChallengeRecord(zone, record) {
+			direct = append(direct, record)
+			continue
+		}
+		targetZone, target, ok, err := p.resolveChallenge(ctx, zone, record, &domains)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			direct = append(direct, record)
+			continue
+		}
+		d := delegatedRecord{original: record, zone: targetZone, record: target}
+		delegated = append(delegated, d)
+		if _, ok := byZone[targetZone]; !ok {
+			zones = append(zones, targetZone)
+		}
+		byZone[targetZone] = append(byZone[targetZone], d)
+	}
+
+	var results []libdns.Record
+	if len(direct) > 0 || len(delegated) == 0 {
+		var err error
+		if results, err = op(ctx, zone, direct); err != nil {
+			return results, err
+		}
+	}
+	for _, targetZone := range zones {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		group := byZone[targetZone]
+		targets := make([]libdns.Record, 0, len(group))
+		for _, d := range group {
+			targets = append(targets, d.record)
+		}
+		done, err := op(ctx, targetZone, targets)
+		results = append(results, p.delegatedResults(targetZone, group, done)...)
+		if err != nil {
+			return results, fmt.Errorf("delegated challenges in %s: %w", targetZone, err)
+		}
+	}
+
+	return results, nil
+}
+
+// delegatedResults returns the original records of group whose delegated
+// record is among done, the records op reported for zone
+func (p *Provider) delegatedResults(zone string, group []delegatedRecord, done []libdns.Record) []libdns.Record {
+	matched := make([]bool, len(done))
+	var originals []libdns.Record
+	for _, d := range group {
+		for i, rec := range done {
+			if !matched[i] && p.sameData(zone, d.record, rec) {
+				matched[i] = true
+				originals = append(originals, d.original)
+				break
+			}
+		}
+	}
+	return originals
+}
+
+// isChallengeRecord reports whether record is an ACME DNS-01 challenge
+func isChallengeRecord(zone string, record libdns.Record) bool {
+	if apiRecordType(record) != "TXT" {
+		return false
+	}
+	name := normalizeRecordName(record.RR().Name, zone)
+	return strings.EqualFold(strings.SplitN(name, ".", 2)[0], challengeLabel)
+}
+
+// resolveChallenge follows CNAMEs from the name of a challenge record through
+// the zones of the account. It returns the zone and record to write instead,
+// or false if no CNAME was followed. domains caches the account's domains
+// across calls. With Subzones configured, every CNAME target must lie inside
+// one of them.
+func (p *Provider) resolveChallenge(ctx context.Context, zone string, record libdns.Record, domains *[]string) (string, libdns.Record, bool, error) {
+	rr := record.RR()
+	name := qualifyName(normalizeRecordName(rr.Name, zone), fqdn(zone))
+	current := zone
+
+	hop := 0
+	for ; ; hop++ {
+		target, found, err := p.cnameTarget(ctx, current, name)
+		if err != nil {
+			return "", nil, false, err
+		}
+		if !found {
+			break
+		}
+		if hop >= maxDelegationHops {
+			return "", nil, false, fmt.Errorf("%s: more than %d CNAMEs", rr.Name, maxDelegationHops)
+		}
+
+		if *domains == nil {
+			if *domains, err = p.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+				return "", nil, false, fmt.Errorf("failed to list domains: %w", err)
+			}
+		}
+		targetZone := zoneOf(*domains, target)
+		if targetZone == "" {
+			return "", nil, false, fmt.Errorf("%s is a CNAME to %s, which is not in a zone of this account", name, target)
+		}
+		if len(p.Subzones) > 0 && !p.inSubzones(target) {
+			// A Provider limited to subzones must not write elsewhere
+			return "", nil, false, fmt.Errorf("%s is a CNAME to %s, which is outside the configured subzones", name, target)
+		}
+		name, current = fqdn(target), targetZone
+	}
+
+	if hop == 0 {
+		return zone, record, false, nil
+	}
+	return current, libdns.TXT{
+		Name: normalizeRecordName(name, current),
+		TTL:  rr.TTL,
+		Text: rr.Data,
+	}, true, nil
+}
+
+// cnameTarget returns the target of the CNAME at name in zone, if any. zone
+// is a registered domain, already resolved from any subzone.
+func (p *Provider) cnameTarget(ctx context.Context, zone, name string) (string, bool, error) {
+	records, err := p.getRecords(ctx, zone)
+	if err != nil {
+		return "", false, err
+	}
+	want := normalizeRecordName(strings.TrimSuffix(name, "."), zone)
+	for _, rec := range records {
+		rr := rec.RR()
+		if apiRecordType(rec) == "CNAME" && strings.EqualFold(normalizeRecordName(rr.Name, zone), want) {
+			return strings.TrimSuffix(rr.Data, "."), true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// zoneOf returns the longest of domains that name belongs to, or ""
+func zoneOf(domains []string, name string) string {
+	name = strings.TrimSuffix(name, ".")
+	var best string
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(domain, ".")
+		if (strings.EqualFold(name, domain) || hasZoneSuffix(name, domain)) && len(domain) > len(best) {
+			best = domain
+		}
+	}
+	return best
+}
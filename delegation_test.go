@@ -0,0 +1,185 @@
+package namesilo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestFollowChallengeCNAME(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "CNAME", Host: "_acme-challenge", Value: "example-com.acme.example.net", TTL: 3600},
+	)
+	srv.AddZone("example.net")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), FollowChallengeCNAME: true}
+	ctx := context.Background()
+
+	challenge := libdns.TXT{Name: "_acme-challenge", Text: "token"}
+	added, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		challenge,
+		libdns.TXT{Name: "@", Text: "v=spf1 -all"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if len(added) != 2 {
+		t.Fatalf("Expected 2 added records, got %v", added)
+	}
+
+	targets := srv.Records("example.net")
+	if len(targets) != 1 || targets[0].Type != "TXT" || targets[0].Host != "example-com.acme.example.net" || targets[0].Value != "token" {
+		t.Fatalf("Unexpected records in the target zone: %+v", targets)
+	}
+	if n := len(srv.Records("example.com")); n != 2 {
+		t.Errorf("Expected the CNAME and SPF record in the source zone, got %d records", n)
+	}
+
+	deleted, err := provider.DeleteRecords(ctx, "example.com.", []libdns.Record{challenge})
+	if err != nil {
+		t.Fatalf("DeleteRecords failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].RR().Name != "_acme-challenge" {
+		t.Errorf("Unexpected deleted records: %v", deleted)
+	}
+	if n := len(srv.Records("example.net")); n != 0 {
+		t.Errorf("Expected the delegated challenge to be deleted, %d records left", n)
+	}
+}
+
+func TestFollowChallengeCNAMESetRecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "CNAME", Host: "_acme-challenge", Value: "example-com.acme.example.net", TTL: 3600},
+	)
+	srv.AddZone("example.net")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), FollowChallengeCNAME: true}
+
+	// A wildcard and a base-name certificate share one challenge name
+	set, err := provider.SetRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "wildcard"},
+		libdns.TXT{Name: "_acme-challenge", Text: "base"},
+	})
+	if err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if len(set) != 2 {
+		t.Errorf("Expected 2 set records, got %v", set)
+	}
+
+	values := map[string]bool{}
+	for _, rec := range srv.Records("example.net") {
+		values[rec.Value] = true
+	}
+	if len(values) != 2 || !values["wildcard"] || !values["base"] {
+		t.Errorf("Expected both challenge values in the target zone, got %v", values)
+	}
+}
+
+func TestFollowChallengeCNAMEWithinZone(t *testing.T) {
+	for _, zone := range []string{"example.com", "example.com."} {
+		srv := namesilotest.NewServer()
+		srv.AddZone("example.com",
+			namesilotest.Record{Type: "CNAME", Host: "_acme-challenge", Value: "_acme-challenge.validation.example.com", TTL: 3600},
+		)
+		provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), FollowChallengeCNAME: true}
+
+		_, err := provider.AppendRecords(context.Background(), zone, []libdns.Record{
+			libdns.TXT{Name: "_acme-challenge", Text: "token"},
+		})
+		if err != nil {
+			t.Fatalf("%s: AppendRecords failed: %v", zone, err)
+		}
+		var hosts []string
+		for _, rec := range srv.Records("example.com") {
+			if rec.Type == "TXT" {
+				hosts = append(hosts, rec.Host)
+			}
+		}
+		if len(hosts) != 1 || hosts[0] != "_acme-challenge.validation.example.com" {
+			t.Errorf("%s: expected the challenge at the CNAME target, got %v", zone, hosts)
+		}
+		srv.Close()
+	}
+}
+
+func TestFollowChallengeCNAMEOutsideAccount(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "CNAME", Host: "_acme-challenge", Value: "challenges.other.org", TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), FollowChallengeCNAME: true}
+
+	_, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+	if err == nil {
+		t.Error("Expected error for a CNAME outside the account")
+	}
+}
+
+func TestFollowChallengeCNAMEWithSubzones(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "CNAME", Host: "_acme-challenge.dev", Value: "dev.acme.example.net", TTL: 3600},
+		namesilotest.Record{Type: "CNAME", Host: "_acme-challenge.www.dev", Value: "www.example.net", TTL: 3600},
+	)
+	srv.AddZone("example.net")
+	provider := &Provider{
+		APIToken:             "test",
+		HTTPClient:           srv.Client(),
+		FollowChallengeCNAME: true,
+		Subzones: map[string]string{
+			"dev.example.com":  "example.com",
+			"acme.example.net": "example.net",
+		},
+	}
+	ctx := context.Background()
+
+	added, err := provider.AppendRecords(ctx, "dev.example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if len(added) != 1 || added[0].RR().Name != "_acme-challenge" {
+		t.Fatalf("Unexpected added records: %v", added)
+	}
+	targets := srv.Records("example.net")
+	if len(targets) != 1 || targets[0].Host != "dev.acme.example.net" || targets[0].Value != "token" {
+		t.Fatalf("Unexpected records in the target zone: %+v", targets)
+	}
+
+	// Delegation must not reach names outside the configured subzones
+	_, err = provider.AppendRecords(ctx, "dev.example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge.www", Text: "token"},
+	})
+	if err == nil {
+		t.Fatal("Expected error for a CNAME outside the configured subzones")
+	}
+	if n := len(srv.Records("example.net")); n != 1 {
+		t.Errorf("Expected no record written outside the subzones, got %d records", n)
+	}
+}
+
+func TestZoneOf(t *testing.T) {
+	domains := []string{"example.com", "sub.example.com", "example.net"}
+	tests := map[string]string{
+		"a.sub.example.com.": "sub.example.com",
+		"www.example.com":    "example.com",
+		"example.net":        "example.net",
+		"example.org":        "",
+		"badexample.com":     "",
+	}
+	for name, want := range tests {
+		if got := zoneOf(domains, name); got != want {
+			t.Errorf("zoneOf(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
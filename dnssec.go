@@ -0,0 +1,106 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DSRecord is a DNSSEC delegation signer (DS) record published for a domain
+// at the registry.
+type DSRecord struct {
+	KeyTag     uint16 // Key tag of the DNSKEY the record refers to
+	Algorithm  uint8  // DNSSEC algorithm number (e.g. 13 for ECDSAP256SHA256)
+	DigestType uint8  // Digest algorithm (1 = SHA-1, 2 = SHA-256, 4 = SHA-384)
+	Digest     string // Hex-encoded digest of the DNSKEY
+}
+
+// digestLengths are the hex digest lengths of the known DS digest types
+var digestLengths = map[uint8]int{
+	1: 40, // SHA-1
+	2: 64, // SHA-256
+	4: 96, // SHA-384
+}
+
+// Validate checks that the record is well-formed before it is sent to NameSilo.
+func (ds DSRecord) Validate() error {
+	if ds.Algorithm == 0 {
+		return fmt.Errorf("DS record algorithm is required")
+	}
+
+	digest := strings.ReplaceAll(ds.Digest, " ", "")
+	if _, err := hex.DecodeString(digest); err != nil || digest == "" {
+		return fmt.Errorf("DS record digest must be hex-encoded: %q", ds.Digest)
+	}
+
+	if want, ok := digestLengths[ds.DigestType]; !ok {
+		return fmt.Errorf("unsupported DS digest type %d", ds.DigestType)
+	} else if len(digest) != want {
+		return fmt.Errorf("DS digest type %d requires %d hex characters, got %d", ds.DigestType, want, len(digest))
+	}
+
+	return nil
+}
+
+// params returns the API parameters identifying the record
+func (ds DSRecord) params(domain string) map[string]string {
+	return map[string]string{
+		"domain":     domain,
+		"digest":     strings.ToUpper(strings.ReplaceAll(ds.Digest, " ", "")),
+		"keyTag":     strconv.Itoa(int(ds.KeyTag)),
+		"alg":        strconv.Itoa(int(ds.Algorithm)),
+		"digestType": strconv.Itoa(int(ds.DigestType)),
+	}
+}
+
+// dsRecord represents a DS record from NameSilo API
+type dsRecord struct {
+	KeyTag     uint16 `xml:"key_tag"`
+	Algorithm  uint8  `xml:"algorithm"`
+	DigestType uint8  `xml:"digest_type"`
+	Digest     string `xml:"digest"`
+}
+
+// dnsSecListResponse represents the response from dnsSecListRecords
+type dnsSecListResponse struct {
+	apiResponse
+	Records []dsRecord `xml:"reply>ds_record"`
+}
+
+// ListDSRecords lists the DS records published for the domain.
+func (p *Provider) ListDSRecords(ctx context.Context, domain string) ([]DSRecord, error) {
+	params := map[string]string{
+		"domain": strings.TrimSuffix(domain, "."),
+	}
+
+	var response dnsSecListResponse
+	if err := p.callAPI(ctx, "dnsSecListRecords", params, &response); err != nil {
+		return nil, err
+	}
+
+	records := make([]DSRecord, 0, len(response.Records))
+	for _, rec := range response.Records {
+		records = append(records, DSRecord(rec))
+	}
+
+	return records, nil
+}
+
+// AddDSRecord publishes a DS record for the domain.
+func (p *Provider) AddDSRecord(ctx context.Context, domain string, ds DSRecord) error {
+	if err := ds.Validate(); err != nil {
+		return err
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "dnsSecAddRecord", ds.params(strings.TrimSuffix(domain, ".")), &response)
+}
+
+// DeleteDSRecord removes a DS record from the domain. All fields of ds must
+// match the published record.
+func (p *Provider) DeleteDSRecord(ctx context.Context, domain string, ds DSRecord) error {
+	var response apiResponse
+	return p.callAPI(ctx, "dnsSecDeleteRecord", ds.params(strings.TrimSuffix(domain, ".")), &response)
+}
@@ -0,0 +1,63 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDSRecordValidate(t *testing.T) {
+	sha256 := strings.Repeat("ab", 32)
+
+	tests := []struct {
+		ds    DSRecord
+		valid bool
+	}{
+		{DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: sha256}, true},
+		{DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 1, Digest: sha256}, false},
+		{DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "not hex"}, false},
+		{DSRecord{KeyTag: 2371, Algorithm: 0, DigestType: 2, Digest: sha256}, false},
+		{DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 3, Digest: sha256}, false},
+	}
+
+	for _, tt := range tests {
+		if err := tt.ds.Validate(); (err == nil) != tt.valid {
+			t.Errorf("Validate(%+v) = %v, want valid=%v", tt.ds, err, tt.valid)
+		}
+	}
+}
+
+func TestDSRecordOperations(t *testing.T) {
+	var added string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, "/api/") {
+		case "dnsSecListRecords":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<ds_record><digest>ABCD</digest><digest_type>2</digest_type><algorithm>13</algorithm><key_tag>2371</key_tag></ds_record>
+</reply></namesilo>`))
+		case "dnsSecAddRecord":
+			added = r.URL.RawQuery
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+
+	records, err := provider.ListDSRecords(context.Background(), "example.com.")
+	if err != nil {
+		t.Fatalf("ListDSRecords failed: %v", err)
+	}
+	want := DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: "ABCD"}
+	if len(records) != 1 || records[0] != want {
+		t.Errorf("Unexpected DS records: %+v", records)
+	}
+
+	ds := DSRecord{KeyTag: 2371, Algorithm: 13, DigestType: 2, Digest: strings.Repeat("ab", 32)}
+	if err := provider.AddDSRecord(context.Background(), "example.com.", ds); err != nil {
+		t.Fatalf("AddDSRecord failed: %v", err)
+	}
+	for _, param := range []string{"keyTag=2371", "alg=13", "digestType=2", "digest=" + strings.Repeat("AB", 32), "domain=example.com"} {
+		if !strings.Contains(added, param) {
+			t.Errorf("Expected %s in request %s", param, added)
+		}
+	}
+}
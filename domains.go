@@ -0,0 +1,396 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ListDomainsOptions filters the result of ListDomains.
+type ListDomainsOptions struct {
+	Portfolio string // Only list domains in this portfolio
+}
+
+// listDomainsResponse represents the response from listDomains
+type listDomainsResponse struct {
+	apiResponse
+	Domains []string `xml:"reply>domains>domain"`
+}
+
+// ListDomains lists the domains registered in the account.
+func (p *Provider) ListDomains(ctx context.Context, opts ListDomainsOptions) ([]string, error) {
+	params := map[string]string{
+		"portfolio": opts.Portfolio,
+	}
+
+	var response listDomainsResponse
+	if err := p.callAPI(ctx, "listDomains", params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Domains, nil
+}
+
+// DomainInfo is the registration metadata of a domain in the account.
+type DomainInfo struct {
+	Domain      string
+	Created     time.Time
+	Expires     time.Time
+	Status      string // e.g. "Active"
+	Locked      bool
+	Private     bool // WHOIS privacy enabled
+	AutoRenew   bool
+	TrafficType string // e.g. "Custom DNS", "Forwarded", "Parked"
+	Portfolio   string
+	NameServers []string
+}
+
+// GetDomainInfo returns the registration metadata of a domain in the account.
+func (p *Provider) GetDomainInfo(ctx context.Context, domain string) (*DomainInfo, error) {
+	domain = strings.TrimSuffix(domain, ".")
+	response, err := p.getDomainInfo(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.info(domain), nil
+}
+
+// info converts the response to a DomainInfo
+func (r domainInfoResponse) info(domain string) *DomainInfo {
+	info := &DomainInfo{
+		Domain:      domain,
+		Status:      r.Status,
+		Locked:      yesNo(r.Locked),
+		Private:     yesNo(r.Private),
+		AutoRenew:   yesNo(r.AutoRenew),
+		TrafficType: r.TrafficType,
+		Portfolio:   r.Portfolio,
+	}
+	info.Created, _ = parseAPIDate(r.Created)
+	info.Expires, _ = parseAPIDate(r.Expires)
+	for _, ns := range r.NameServers {
+		if ns = strings.TrimSpace(ns); ns != "" {
+			info.NameServers = append(info.NameServers, ns)
+		}
+	}
+
+	return info
+}
+
+// ExpiringDomains returns the domains of the account that expire within the
+// given duration from now, soonest first. Domains that already expired are
+// included. It fetches the metadata of every domain, so it makes one API
+// call per domain in the account.
+func (p *Provider) ExpiringDomains(ctx context.Context, within time.Duration) ([]DomainInfo, error) {
+	domains, err := p.ListDomains(ctx, ListDomainsOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(within)
+	var expiring []DomainInfo
+	for _, domain := range domains {
+		info, err := p.GetDomainInfo(ctx, domain)
+		if err != nil {
+			return nil, fmt.Errorf("getting info for %s: %w", domain, err)
+		}
+		if !info.Expires.IsZero() && info.Expires.Before(deadline) {
+			expiring = append(expiring, *info)
+		}
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].Expires.Before(expiring[j].Expires)
+	})
+
+	return expiring, nil
+}
+
+// DomainRegistration describes a domain to register with RegisterDomain.
+type DomainRegistration struct {
+	Domain      string   // Domain name to register, e.g. "example.com"
+	Years       int      // Registration period in years (1-10)
+	Private     bool     // Enable WHOIS privacy
+	AutoRenew   bool     // Renew automatically before expiration
+	NameServers []string // Optional nameservers (2-13); NameSilo's are used if empty
+	ContactID   string   // Optional contact profile ID; the account default is used if empty
+	PaymentID   string   // Optional verified credit card profile; account funds are used if empty
+	Portfolio   string   // Optional portfolio to place the domain in
+	Coupon      string   // Optional coupon code
+}
+
+// OrderResult is the outcome of a paid operation such as a registration or renewal.
+type OrderResult struct {
+	Domain      string  // Domain the order was placed for
+	OrderAmount float64 // Amount charged, in USD
+	Message     string  // Additional information from NameSilo, if any
+}
+
+// orderResponse represents the response from registerDomain and renewDomain
+type orderResponse struct {
+	apiResponse
+	Message     string  `xml:"reply>message"`
+	Domain      string  `xml:"reply>domain"`
+	OrderAmount float64 `xml:"reply>order_amount"`
+}
+
+// result converts the response to an OrderResult
+func (r orderResponse) result(domain string) *OrderResult {
+	if r.Domain != "" {
+		domain = r.Domain
+	}
+	return &OrderResult{
+		Domain:      domain,
+		OrderAmount: r.OrderAmount,
+		Message:     r.Message,
+	}
+}
+
+// RegisterDomain registers a new domain and returns the order result.
+func (p *Provider) RegisterDomain(ctx context.Context, reg DomainRegistration) (*OrderResult, error) {
+	domain := strings.TrimSuffix(reg.Domain, ".")
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required")
+	}
+	if reg.Years < 1 || reg.Years > 10 {
+		return nil, fmt.Errorf("registration years must be between 1 and 10, got %d", reg.Years)
+	}
+
+	params := map[string]string{
+		"domain":     domain,
+		"years":      strconv.Itoa(reg.Years),
+		"private":    boolParam(reg.Private),
+		"auto_renew": boolParam(reg.AutoRenew),
+		"contact_id": reg.ContactID,
+		"payment_id": reg.PaymentID,
+		"portfolio":  reg.Portfolio,
+		"coupon":     reg.Coupon,
+	}
+
+	if len(reg.NameServers) > 0 {
+		if err := addNameServerParams(params, reg.NameServers); err != nil {
+			return nil, err
+		}
+	}
+
+	var response orderResponse
+	if err := p.callAPI(ctx, "registerDomain", params, &response); err != nil {
+		return nil, err
+	}
+
+	return response.result(domain), nil
+}
+
+// RenewOptions are optional parameters of RenewDomain.
+type RenewOptions struct {
+	PaymentID string // Verified credit card profile; account funds are used if empty
+	Coupon    string // Coupon code
+}
+
+// RenewalResult is the outcome of RenewDomain.
+type RenewalResult struct {
+	OrderResult
+
+	// Expires is the new expiration date of the domain. It is zero if the
+	// renewal succeeded but the date could not be retrieved.
+	Expires time.Time
+}
+
+// RenewDomain renews a domain for the given number of years and returns the
+// order result along with the new expiration date.
+func (p *Provider) RenewDomain(ctx context.Context, domain string, years int, opts RenewOptions) (*RenewalResult, error) {
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required")
+	}
+	if years < 1 || years > 10 {
+		return nil, fmt.Errorf("renewal years must be between 1 and 10, got %d", years)
+	}
+
+	params := map[string]string{
+		"domain":     domain,
+		"years":      strconv.Itoa(years),
+		"payment_id": opts.PaymentID,
+		"coupon":     opts.Coupon,
+	}
+
+	var response orderResponse
+	if err := p.callAPI(ctx, "renewDomain", params, &response); err != nil {
+		return nil, err
+	}
+
+	result := &RenewalResult{OrderResult: *response.result(domain)}
+
+	// The renewal reply does not include the new expiration date
+	if info, err := p.getDomainInfo(ctx, domain); err == nil {
+		result.Expires, _ = parseAPIDate(info.Expires)
+	}
+
+	return result, nil
+}
+
+// parseAPIDate parses a date as formatted by NameSilo (YYYY-MM-DD)
+func parseAPIDate(s string) (time.Time, error) {
+	return time.Parse("2006-01-02", strings.TrimSpace(s))
+}
+
+// SetNameServers delegates the domain to the given nameservers. Between 2
+// and 13 nameservers are required.
+func (p *Provider) SetNameServers(ctx context.Context, domain string, nameServers []string) error {
+	params := map[string]string{
+		"domain": strings.TrimSuffix(domain, "."),
+	}
+	if err := addNameServerParams(params, nameServers); err != nil {
+		return err
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "changeNameServers", params, &response)
+}
+
+// SettingResult is the outcome of changing a domain setting such as WHOIS privacy.
+type SettingResult struct {
+	Domain  string
+	Changed bool   // False if the domain already had the requested setting
+	Detail  string // NameSilo reply detail
+}
+
+// AddPrivacy enables WHOIS privacy for the domain.
+func (p *Provider) AddPrivacy(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "addPrivacy", domain, 255)
+}
+
+// RemovePrivacy disables WHOIS privacy for the domain.
+func (p *Provider) RemovePrivacy(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "removePrivacy", domain, 256)
+}
+
+// EnableAutoRenew turns on automatic renewal for the domain.
+func (p *Provider) EnableAutoRenew(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "addAutoRenewal", domain, 250)
+}
+
+// DisableAutoRenew turns off automatic renewal for the domain.
+func (p *Provider) DisableAutoRenew(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "removeAutoRenewal", domain, 251)
+}
+
+// LockDomain locks the domain at the registry, preventing transfers.
+func (p *Provider) LockDomain(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "domainLock", domain, 252)
+}
+
+// UnlockDomain unlocks the domain so it can be transferred away.
+func (p *Provider) UnlockDomain(ctx context.Context, domain string) (*SettingResult, error) {
+	return p.changeSetting(ctx, "domainUnlock", domain, 253)
+}
+
+// AuthCodeAck acknowledges a RetrieveAuthCode request. NameSilo does not
+// return the EPP auth code through the API; it emails it to the
+// administrative contact of the domain.
+type AuthCodeAck struct {
+	Domain string
+	Detail string // NameSilo reply detail
+}
+
+// RetrieveAuthCode asks NameSilo to email the EPP auth (transfer) code of the
+// domain to its administrative contact. It returns ErrDomainLocked if the
+// domain must be unlocked first, and an error matching ErrTransferIneligible
+// if the domain cannot be transferred yet.
+func (p *Provider) RetrieveAuthCode(ctx context.Context, domain string) (*AuthCodeAck, error) {
+	domain = strings.TrimSuffix(domain, ".")
+
+	info, err := p.getDomainInfo(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	if yesNo(info.Locked) {
+		return nil, fmt.Errorf("cannot retrieve auth code for %q: %w", domain, ErrDomainLocked)
+	}
+
+	params := map[string]string{
+		"domain": domain,
+	}
+
+	var response apiResponse
+	if err := p.callAPI(ctx, "retrieveAuthCode", params, &response); err != nil {
+		return nil, err
+	}
+
+	return &AuthCodeAck{Domain: domain, Detail: response.Detail}, nil
+}
+
+// PushDomain moves the domain to another NameSilo account, identified by the
+// login email of that account. The push is immediate and cannot be undone
+// from this account.
+func (p *Provider) PushDomain(ctx context.Context, domain, targetAccount string) error {
+	if !strings.Contains(targetAccount, "@") {
+		return fmt.Errorf("target account must be the login email of a NameSilo account, got %q", targetAccount)
+	}
+
+	params := map[string]string{
+		"domain":          strings.TrimSuffix(domain, "."),
+		"recipient_login": targetAccount,
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "domainPush", params, &response)
+}
+
+// changeSetting performs an operation that toggles a domain setting.
+// unchangedCode is the reply code NameSilo uses when the domain already has
+// the requested setting, which is reported as an unchanged result.
+func (p *Provider) changeSetting(ctx context.Context, operation, domain string, unchangedCode int) (*SettingResult, error) {
+	domain = strings.TrimSuffix(domain, ".")
+	params := map[string]string{
+		"domain": domain,
+	}
+
+	var response apiResponse
+	err := p.callAPI(ctx, operation, params, &response)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Code == unchangedCode {
+		return &SettingResult{Domain: domain, Changed: false, Detail: apiErr.Detail}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &SettingResult{Domain: domain, Changed: true, Detail: response.Detail}, nil
+}
+
+// addNameServerParams validates nameservers and adds them as ns1..ns13
+func addNameServerParams(params map[string]string, nameServers []string) error {
+	if len(nameServers) < 2 || len(nameServers) > 13 {
+		return fmt.Errorf("between 2 and 13 nameservers are required, got %d", len(nameServers))
+	}
+
+	for i, ns := range nameServers {
+		ns = strings.TrimSuffix(strings.TrimSpace(ns), ".")
+		if ns == "" {
+			return fmt.Errorf("nameserver %d is empty", i+1)
+		}
+		params[fmt.Sprintf("ns%d", i+1)] = ns
+	}
+
+	return nil
+}
+
+// yesNo parses the Yes/No flags used in NameSilo replies
+func yesNo(s string) bool {
+	return strings.EqualFold(strings.TrimSpace(s), "Yes")
+}
+
+// boolParam formats a boolean as the 1/0 flag NameSilo expects
+func boolParam(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
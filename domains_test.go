@@ -0,0 +1,286 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRegisterDomain(t *testing.T) {
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<message>Your domain registration was successfully processed.</message><domain>example.com</domain><order_amount>10.95</order_amount>
+</reply></namesilo>`))
+	})
+
+	result, err := provider.RegisterDomain(context.Background(), DomainRegistration{
+		Domain:      "example.com",
+		Years:       2,
+		Private:     true,
+		NameServers: []string{"ns1.example.net.", "ns2.example.net"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterDomain failed: %v", err)
+	}
+
+	if result.Domain != "example.com" || result.OrderAmount != 10.95 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	for key, want := range map[string]string{"years": "2", "private": "1", "auto_renew": "0", "ns1": "ns1.example.net", "ns2": "ns2.example.net"} {
+		if got := query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("Parameter %s = %v, want %s", key, got, want)
+		}
+	}
+}
+
+func TestRegisterDomainValidation(t *testing.T) {
+	provider := Provider{APIToken: "test-token"}
+
+	tests := []DomainRegistration{
+		{Domain: "", Years: 1},
+		{Domain: "example.com", Years: 0},
+		{Domain: "example.com", Years: 11},
+		{Domain: "example.com", Years: 1, NameServers: []string{"ns1.example.net"}},
+	}
+	for _, reg := range tests {
+		if _, err := provider.RegisterDomain(context.Background(), reg); err == nil {
+			t.Errorf("Expected validation error for %+v", reg)
+		}
+	}
+}
+
+func TestRenewDomain(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/renewDomain":
+			if r.URL.Query().Get("years") != "3" {
+				t.Errorf("Expected years=3, got %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domain>example.com</domain><order_amount>32.85</order_amount></reply></namesilo>`))
+		case "/api/getDomainInfo":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><expires>2030-06-22</expires></reply></namesilo>`))
+		}
+	})
+
+	result, err := provider.RenewDomain(context.Background(), "example.com.", 3, RenewOptions{})
+	if err != nil {
+		t.Fatalf("RenewDomain failed: %v", err)
+	}
+
+	if result.OrderAmount != 32.85 {
+		t.Errorf("Expected order amount 32.85, got %v", result.OrderAmount)
+	}
+	if want := time.Date(2030, 6, 22, 0, 0, 0, 0, time.UTC); !result.Expires.Equal(want) {
+		t.Errorf("Expected expiration %v, got %v", want, result.Expires)
+	}
+}
+
+func TestSetNameServers(t *testing.T) {
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	err := provider.SetNameServers(context.Background(), "example.com.", []string{"ns1.example.net", "ns2.example.net", "ns3.example.net."})
+	if err != nil {
+		t.Fatalf("SetNameServers failed: %v", err)
+	}
+	if query["ns3"][0] != "ns3.example.net" || query["domain"][0] != "example.com" {
+		t.Errorf("Unexpected parameters: %v", query)
+	}
+
+	tooMany := make([]string, 14)
+	for i := range tooMany {
+		tooMany[i] = "ns.example.net"
+	}
+	for _, ns := range [][]string{nil, {"ns1.example.net"}, tooMany, {"ns1.example.net", " "}} {
+		if err := provider.SetNameServers(context.Background(), "example.com", ns); err == nil {
+			t.Errorf("Expected validation error for %d nameservers", len(ns))
+		}
+	}
+}
+
+func TestPrivacy(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/addPrivacy":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		case "/api/removePrivacy":
+			w.Write([]byte(`<namesilo><reply><code>256</code><detail>Domain is already Not Private - No update made</detail></reply></namesilo>`))
+		}
+	})
+
+	result, err := provider.AddPrivacy(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("AddPrivacy failed: %v", err)
+	}
+	if !result.Changed || result.Domain != "example.com" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	result, err = provider.RemovePrivacy(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("RemovePrivacy failed: %v", err)
+	}
+	if result.Changed {
+		t.Errorf("Expected unchanged result, got %+v", result)
+	}
+}
+
+func TestAutoRenew(t *testing.T) {
+	var operations []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		operations = append(operations, r.URL.Path)
+		if r.URL.Path == "/api/addAutoRenewal" {
+			w.Write([]byte(`<namesilo><reply><code>250</code><detail>Domain is already set to AutoRenew - No update made.</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	result, err := provider.EnableAutoRenew(context.Background(), "example.com")
+	if err != nil || result.Changed {
+		t.Errorf("EnableAutoRenew = %+v, %v; want unchanged result", result, err)
+	}
+
+	result, err = provider.DisableAutoRenew(context.Background(), "example.com")
+	if err != nil || !result.Changed {
+		t.Errorf("DisableAutoRenew = %+v, %v; want changed result", result, err)
+	}
+
+	if len(operations) != 2 || operations[1] != "/api/removeAutoRenewal" {
+		t.Errorf("Unexpected operations: %v", operations)
+	}
+}
+
+func TestDomainLock(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/domainLock":
+			w.Write([]byte(`<namesilo><reply><code>252</code><detail>Domain is already Locked - No update made.</detail></reply></namesilo>`))
+		case "/api/domainUnlock":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+
+	result, err := provider.LockDomain(context.Background(), "example.com")
+	if err != nil || result.Changed {
+		t.Errorf("LockDomain = %+v, %v; want unchanged result", result, err)
+	}
+
+	result, err = provider.UnlockDomain(context.Background(), "example.com")
+	if err != nil || !result.Changed {
+		t.Errorf("UnlockDomain = %+v, %v; want changed result", result, err)
+	}
+}
+
+func TestRetrieveAuthCode(t *testing.T) {
+	locked := "Yes"
+	authCodeReply := `<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/getDomainInfo":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><locked>` + locked + `</locked></reply></namesilo>`))
+		case "/api/retrieveAuthCode":
+			w.Write([]byte(authCodeReply))
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := provider.RetrieveAuthCode(ctx, "example.com"); !errors.Is(err, ErrDomainLocked) {
+		t.Errorf("Expected ErrDomainLocked, got %v", err)
+	}
+
+	locked = "No"
+	ack, err := provider.RetrieveAuthCode(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("RetrieveAuthCode failed: %v", err)
+	}
+	if ack.Domain != "example.com" {
+		t.Errorf("Unexpected acknowledgment: %+v", ack)
+	}
+
+	authCodeReply = `<namesilo><reply><code>265</code><detail>Domain cannot be transferred at this time</detail></reply></namesilo>`
+	if _, err := provider.RetrieveAuthCode(ctx, "example.com"); !errors.Is(err, ErrTransferIneligible) {
+		t.Errorf("Expected ErrTransferIneligible, got %v", err)
+	}
+}
+
+func TestPushDomain(t *testing.T) {
+	var query url.Values
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	ctx := context.Background()
+
+	if err := provider.PushDomain(ctx, "example.com.", "client@example.net"); err != nil {
+		t.Fatalf("PushDomain failed: %v", err)
+	}
+	if query.Get("domain") != "example.com" || query.Get("recipient_login") != "client@example.net" {
+		t.Errorf("Unexpected query %v", query)
+	}
+
+	if err := provider.PushDomain(ctx, "example.com", "client"); err == nil {
+		t.Error("Expected error for invalid target account")
+	}
+}
+
+func TestGetDomainInfo(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<created>2020-05-01</created><expires>2025-05-01</expires><status>Active</status><locked>Yes</locked><private>No</private><auto_renew>Yes</auto_renew><traffic_type>Custom DNS</traffic_type><portfolio>clients</portfolio>
+<nameservers><nameserver position="1">ns1.dnsowl.com</nameserver><nameserver position="2">ns2.dnsowl.com</nameserver></nameservers>
+</reply></namesilo>`))
+	})
+
+	info, err := provider.GetDomainInfo(context.Background(), "example.com.")
+	if err != nil {
+		t.Fatalf("GetDomainInfo failed: %v", err)
+	}
+	want := DomainInfo{
+		Domain:      "example.com",
+		Created:     time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+		Expires:     time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+		Status:      "Active",
+		Locked:      true,
+		AutoRenew:   true,
+		TrafficType: "Custom DNS",
+		Portfolio:   "clients",
+		NameServers: []string{"ns1.dnsowl.com", "ns2.dnsowl.com"},
+	}
+	if !reflect.DeepEqual(*info, want) {
+		t.Errorf("Unexpected info:\n got %+v\nwant %+v", *info, want)
+	}
+}
+
+func TestExpiringDomains(t *testing.T) {
+	expires := map[string]string{
+		"soon.com":  time.Now().AddDate(0, 0, 10).Format("2006-01-02"),
+		"later.com": time.Now().AddDate(1, 0, 0).Format("2006-01-02"),
+		"past.com":  time.Now().AddDate(0, 0, -3).Format("2006-01-02"),
+	}
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/listDomains":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domains><domain>soon.com</domain><domain>later.com</domain><domain>past.com</domain></domains></reply></namesilo>`))
+		case "/api/getDomainInfo":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><expires>` + expires[r.URL.Query().Get("domain")] + `</expires></reply></namesilo>`))
+		}
+	})
+
+	expiring, err := provider.ExpiringDomains(context.Background(), 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("ExpiringDomains failed: %v", err)
+	}
+	if len(expiring) != 2 || expiring[0].Domain != "past.com" || expiring[1].Domain != "soon.com" {
+		t.Errorf("Unexpected expiring domains: %+v", expiring)
+	}
+}
@@ -0,0 +1,36 @@
+package namesilo
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// envPlaceholder matches {env.NAME}
+var envPlaceholder = regexp.MustCompile(`\{env\.([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces {env.NAME} placeholders in a configuration value with
+// the environment variable, so that secrets such as API keys need not be
+// stored in config files. Unset variables expand to the empty string; any
+// other text, including $NAME, is kept as is. Each value is resolved when
+// the Provider first uses it, and the result is reused afterwards.
+func (p *Provider) expandEnv(value string) string {
+	if !strings.Contains(value, "{env.") {
+		return value
+	}
+
+	p.envMu.Lock()
+	defer p.envMu.Unlock()
+	if expanded, ok := p.env[value]; ok {
+		return expanded
+	}
+
+	expanded := envPlaceholder.ReplaceAllStringFunc(value, func(placeholder string) string {
+		return os.Getenv(envPlaceholder.FindStringSubmatch(placeholder)[1])
+	})
+	if p.env == nil {
+		p.env = make(map[string]string)
+	}
+	p.env[value] = expanded
+	return expanded
+}
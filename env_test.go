@@ -0,0 +1,73 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("NAMESILO_TEST_KEY", "secret")
+	t.Setenv("NAMESILO_TEST_EMPTY", "")
+
+	tests := map[string]string{
+		"plain":                       "plain",
+		"{env.NAMESILO_TEST_KEY}":     "secret",
+		"{env.NAMESILO_TEST_KEY}-2":   "secret-2",
+		"app/{env.NAMESILO_TEST_KEY}": "app/secret",
+		"{env.NAMESILO_TEST_UNSET}":   "",
+		"{env.NAMESILO_TEST_EMPTY}":   "",
+		"$NAMESILO_TEST_KEY":          "$NAMESILO_TEST_KEY",
+		"${NAMESILO_TEST_KEY}":        "${NAMESILO_TEST_KEY}",
+		"costs $5":                    "costs $5",
+		"{env.}":                      "{env.}",
+	}
+	provider := &Provider{}
+	for value, want := range tests {
+		if got := provider.expandEnv(value); got != want {
+			t.Errorf("expandEnv(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestEnvPlaceholdersInConfig(t *testing.T) {
+	t.Setenv("NAMESILO_TEST_KEY", "env-token")
+	t.Setenv("NAMESILO_TEST_TENANT", "dns")
+
+	var gotKey, gotTenant string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.URL.Query().Get("key")
+		gotTenant = r.Header.Get("X-Tenant")
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	config := `{"api_token": "{env.NAMESILO_TEST_KEY}", "headers": {"X-Tenant": "{env.NAMESILO_TEST_TENANT}"}}`
+	provider.APIToken = ""
+	if err := json.Unmarshal([]byte(config), provider); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if provider.APIToken != "{env.NAMESILO_TEST_KEY}" {
+		t.Errorf("Expected the placeholder to be kept in the config, got %q", provider.APIToken)
+	}
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if gotKey != "env-token" || gotTenant != "dns" {
+		t.Errorf("Expected expanded key and header, got key %q and header %q", gotKey, gotTenant)
+	}
+}
+
+func TestExpandEnvResolvedOnce(t *testing.T) {
+	t.Setenv("NAMESILO_TEST_KEY", "first")
+	provider := &Provider{}
+
+	if got := provider.expandEnv("{env.NAMESILO_TEST_KEY}"); got != "first" {
+		t.Fatalf("expandEnv = %q, want %q", got, "first")
+	}
+	t.Setenv("NAMESILO_TEST_KEY", "second")
+	if got := provider.expandEnv("{env.NAMESILO_TEST_KEY}"); got != "first" {
+		t.Errorf("Expected the value resolved at first use, got %q", got)
+	}
+}
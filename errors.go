@@ -0,0 +1,211 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors that an *APIError matches with errors.Is, based on its
+// NameSilo reply code.
+var (
+	// ErrInvalidAPIKey is matched when no API key or an invalid one was sent.
+	ErrInvalidAPIKey = errors.New("namesilo: invalid API key")
+
+	// ErrIPNotAllowed is matched when the API key cannot be used from the
+	// caller's IP address.
+	ErrIPNotAllowed = errors.New("namesilo: API access not allowed from this IP")
+
+	// ErrDomainNotInAccount is matched when the domain is not active or does
+	// not belong to the account.
+	ErrDomainNotInAccount = errors.New("namesilo: domain is not active or does not belong to this account")
+
+	// ErrRecordExists is matched when a DNS modification was rejected
+	// because an identical record already exists.
+	ErrRecordExists = errors.New("namesilo: record already exists")
+
+	// ErrDuplicate is returned by AppendRecords with RejectDuplicates when
+	// an identical record already exists. It is also matched when NameSilo
+	// rejects a record as already existing.
+	ErrDuplicate = errors.New("namesilo: duplicate record")
+
+	// ErrDomainLocked is returned when an operation requires the domain to
+	// be unlocked first.
+	ErrDomainLocked = errors.New("namesilo: domain is locked")
+
+	// ErrTransferIneligible is matched when the domain cannot be
+	// transferred at this time (e.g. within 60 days of registration).
+	ErrTransferIneligible = errors.New("namesilo: domain is not eligible for transfer")
+
+	// ErrInsufficientFunds is matched when the account balance cannot cover
+	// the requested transaction.
+	ErrInsufficientFunds = errors.New("namesilo: insufficient account funds")
+
+	// ErrUnreachable is matched by a *RequestError when the API could not
+	// be reached or did not reply: a network error, a timeout, an HTTP
+	// error status, an error page instead of an API reply, or an open
+	// circuit breaker.
+	ErrUnreachable = errors.New("namesilo: API unreachable")
+)
+
+// APIError is returned when NameSilo replies to an operation with an
+// unsuccessful reply code.
+type APIError struct {
+	Operation string // API operation, e.g. "dnsAddRecord"
+	Domain    string // Domain the operation targeted, if any
+	Code      int    // NameSilo reply code
+	Detail    string // NameSilo reply detail
+
+	// Params are the parameters of the request, such as rrhost and
+	// rrvalue for record operations, without the API key and with values
+	// that may hold personal data masked
+	Params map[string]string
+
+	// Response is the start of the raw reply, with CaptureResponses set
+	Response string
+}
+
+func (e *APIError) Error() string {
+	if e.Domain == "" {
+		return fmt.Sprintf("namesilo: %s failed%s: code %d - %s", e.Operation, formatParams(e.Params), e.Code, e.Detail)
+	}
+	return fmt.Sprintf("namesilo: %s failed for %q%s: code %d - %s", e.Operation, e.Domain, formatParams(e.Params), e.Code, e.Detail)
+}
+
+// RequestError is returned when an API request fails before NameSilo
+// replies, e.g. because of a network error or an HTTP error status. The
+// underlying error is available through errors.As and errors.Is.
+type RequestError struct {
+	Operation string            // API operation, e.g. "dnsAddRecord"
+	Domain    string            // Domain the operation targeted, if any
+	Params    map[string]string // request parameters, without the API key and personal data
+	Response  string            // start of the raw reply, with CaptureResponses set
+	Err       error
+}
+
+func (e *RequestError) Error() string {
+	if e.Domain == "" {
+		return fmt.Sprintf("%s request failed%s: %v", e.Operation, formatParams(e.Params), e.Err)
+	}
+	return fmt.Sprintf("%s request failed for %q%s: %v", e.Operation, e.Domain, formatParams(e.Params), e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the request failed for lack of a reply from the API.
+// Canceled requests and replies rejected by StrictDecoding do not match
+// ErrUnreachable.
+func (e *RequestError) Is(target error) bool {
+	if target != ErrUnreachable {
+		return false
+	}
+	return !errors.Is(e.Err, context.Canceled) && !errors.Is(e.Err, ErrUnexpectedResponse)
+}
+
+// maxParamLength is the length at which parameter values, such as long TXT
+// values, are cut in error messages
+const maxParamLength = 64
+
+// formatParams formats request parameters for an error message, sorted by
+// name and without the domain, which APIError and RequestError messages
+// name from their Domain field
+func formatParams(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "domain" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		value := params[name]
+		if len(value) > maxParamLength {
+			value = value[:maxParamLength] + "..."
+		}
+		pairs = append(pairs, name+"="+strconv.Quote(value))
+	}
+	return " (" + strings.Join(pairs, " ") + ")"
+}
+
+// reportedParams are the request parameters that errors report as sent; the
+// values of others, such as contact details, email addresses and transfer
+// authorization codes, are masked
+var reportedParams = map[string]bool{
+	"domain":     true,
+	"rrid":       true,
+	"rrtype":     true,
+	"rrhost":     true,
+	"rrvalue":    true,
+	"rrdistance": true,
+	"rrttl":      true,
+	"digest":     true,
+	"keyTag":     true,
+	"alg":        true,
+	"digestType": true,
+	"protocol":   true,
+	"address":    true,
+	"method":     true,
+	"sub_domain": true,
+	"portfolio":  true,
+	"contact_id": true,
+	"years":      true,
+	"private":    true,
+	"auto_renew": true,
+}
+
+// errorParams returns a copy of the request parameters for an error, with
+// any API key removed and the values of parameters that may hold personal
+// data masked
+func (p *Provider) errorParams(params map[string]string) map[string]string {
+	if len(params) == 0 {
+		return nil
+	}
+	sanitized := make(map[string]string, len(params))
+	for name, value := range params {
+		switch {
+		case name == "key":
+			continue
+		case reportedParams[name] || isNameServerParam(name):
+			sanitized[name] = p.redact(value)
+		default:
+			sanitized[name] = redactedToken
+		}
+	}
+	return sanitized
+}
+
+// isNameServerParam reports whether name is one of the ns1 ... ns13
+// parameters of changeNameServers
+func isNameServerParam(name string) bool {
+	n, err := strconv.Atoi(strings.TrimPrefix(name, "ns"))
+	return strings.HasPrefix(name, "ns") && err == nil && n >= 1 && n <= 13
+}
+
+// Is reports whether the reply code corresponds to one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrInvalidAPIKey:
+		return e.Code == 109 || e.Code == 110
+	case ErrIPNotAllowed:
+		return e.Code == 113
+	case ErrDomainNotInAccount:
+		return e.Code == 200
+	case ErrRecordExists, ErrDuplicate:
+		return e.Code == 280 && strings.Contains(strings.ToLower(e.Detail), "already exists")
+	case ErrTransferIneligible:
+		return e.Code == 265
+	case ErrInsufficientFunds:
+		return e.Code == 119
+	}
+	return false
+}
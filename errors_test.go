@@ -0,0 +1,133 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{&APIError{Code: 110, Detail: "Invalid API Key"}, ErrInvalidAPIKey, true},
+		{&APIError{Code: 109}, ErrInvalidAPIKey, true},
+		{&APIError{Code: 113}, ErrIPNotAllowed, true},
+		{&APIError{Code: 200}, ErrDomainNotInAccount, true},
+		{&APIError{Code: 280, Detail: "The record already exists"}, ErrRecordExists, true},
+		{&APIError{Code: 280, Detail: "Invalid value"}, ErrRecordExists, false},
+		{&APIError{Code: 110}, ErrDomainNotInAccount, false},
+	}
+
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("wrapped: %w", tt.err)
+		if got := errors.Is(wrapped, tt.target); got != tt.want {
+			t.Errorf("errors.Is(code %d, %v) = %v, want %v", tt.err.Code, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestAPIErrorFromReply(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+	})
+
+	_, err := provider.GetRecords(context.Background(), "example.com.")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if apiErr.Operation != "dnsListRecords" || apiErr.Domain != "example.com" || apiErr.Code != 110 {
+		t.Errorf("Unexpected APIError fields: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Error("Expected error to match ErrInvalidAPIKey")
+	}
+}
+
+func TestAPIErrorParams(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>280</code><detail>Invalid value</detail></reply></namesilo>`))
+	})
+
+	_, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.TXT{Name: "verify", Text: "test-token " + strings.Repeat("x", 100)},
+	})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if apiErr.Params["rrhost"] != "verify" || apiErr.Params["rrtype"] != "TXT" {
+		t.Errorf("Expected the record in Params, got %v", apiErr.Params)
+	}
+	if _, ok := apiErr.Params["key"]; ok || strings.Contains(apiErr.Params["rrvalue"], "test-token") {
+		t.Errorf("Expected the API key to be masked, got %v", apiErr.Params)
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, `rrhost="verify"`) || !strings.Contains(msg, "...") || strings.Contains(msg, strings.Repeat("x", 100)) {
+		t.Errorf("Expected the record and a shortened value in the message, got %q", msg)
+	}
+}
+
+func TestErrorParamsMaskPersonalData(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := provider.ContactAdd(context.Background(), Contact{
+		FirstName: "Jane", LastName: "Doe", Address: "1 Main St", City: "Springfield", State: "IL",
+		Zip: "62701", Country: "US", Email: "jane@example.com", Phone: "+1.5555550100",
+	})
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError, got %v", err)
+	}
+	if reqErr.Params["em"] != redactedToken || reqErr.Params["ph"] != redactedToken {
+		t.Errorf("Expected contact details to be masked, got %v", reqErr.Params)
+	}
+	for _, value := range []string{"jane@example.com", "5555550100", "Main St", "Doe"} {
+		if strings.Contains(err.Error(), value) {
+			t.Errorf("Expected %q to be masked in %q", value, err.Error())
+		}
+	}
+
+	params := provider.errorParams(map[string]string{"domain": "example.com", "ns1": "ns1.example.net", "auth": "secret", "ns14": "x"})
+	want := map[string]string{"domain": "example.com", "ns1": "ns1.example.net", "auth": redactedToken, "ns14": redactedToken}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("errorParams = %v, want %v", params, want)
+	}
+}
+
+func TestRequestError(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	err := provider.DeleteRecordByID(context.Background(), "example.com.", "abc123")
+
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError, got %v", err)
+	}
+	if reqErr.Operation != "dnsDeleteRecord" || reqErr.Domain != "example.com" || reqErr.Params["rrid"] != "abc123" {
+		t.Errorf("Unexpected RequestError fields: %+v", reqErr)
+	}
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected the HTTP status error to be wrapped, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `dnsDeleteRecord request failed for "example.com" (rrid="abc123")`) {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
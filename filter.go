@@ -0,0 +1,42 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// GetRecordsByType lists the records in the zone with the given type
+// (e.g. "TXT"). The comparison is case-insensitive.
+func (p *Provider) GetRecordsByType(ctx context.Context, zone, recordType string) ([]libdns.Record, error) {
+	return p.getFilteredRecords(ctx, zone, func(rr libdns.RR) bool {
+		return strings.EqualFold(rr.Type, recordType)
+	})
+}
+
+// GetRecordsByName lists the records in the zone with the given name. The
+// name may be relative to the zone ("www", "@") or fully qualified.
+func (p *Provider) GetRecordsByName(ctx context.Context, zone, name string) ([]libdns.Record, error) {
+	want := normalizeRecordName(strings.TrimSuffix(name, "."), zone)
+	return p.getFilteredRecords(ctx, zone, func(rr libdns.RR) bool {
+		return strings.EqualFold(normalizeRecordName(rr.Name, zone), want)
+	})
+}
+
+// getFilteredRecords lists the records in the zone for which match returns true
+func (p *Provider) getFilteredRecords(ctx context.Context, zone string, match func(libdns.RR) bool) ([]libdns.Record, error) {
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []libdns.Record
+	for _, rec := range records {
+		if match(rec.RR()) {
+			filtered = append(filtered, rec)
+		}
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,52 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+const filterTestZone = `<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>2</record_id><type>TXT</type><host>_acme-challenge.example.com</host><value>token</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>3</record_id><type>TXT</type><host>example.com</host><value>v=spf1 -all</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>4</record_id><type>A</type><host>www.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`
+
+func TestGetRecordsByType(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(filterTestZone))
+	})
+
+	records, err := provider.GetRecordsByType(context.Background(), "example.com.", "txt")
+	if err != nil {
+		t.Fatalf("GetRecordsByType failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Errorf("Expected 2 TXT records, got %d", len(records))
+	}
+}
+
+func TestGetRecordsByName(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(filterTestZone))
+	})
+
+	tests := map[string]int{
+		"@":                            2,
+		"www":                          1,
+		"www.example.com.":             1,
+		"_acme-challenge":              1,
+		"_ACME-CHALLENGE.example.com.": 1,
+		"missing":                      0,
+	}
+	for name, want := range tests {
+		records, err := provider.GetRecordsByName(context.Background(), "example.com.", name)
+		if err != nil {
+			t.Fatalf("GetRecordsByName(%q) failed: %v", name, err)
+		}
+		if len(records) != want {
+			t.Errorf("GetRecordsByName(%q): expected %d records, got %d", name, want, len(records))
+		}
+	}
+}
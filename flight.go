@@ -0,0 +1,73 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/libdns/libdns"
+)
+
+// zoneFetch is a listing of a zone's records in progress, shared by every
+// caller that asks for the zone before it completes
+type zoneFetch struct {
+	done    chan struct{}
+	records []libdns.Record
+	err     error
+}
+
+// sharedFetch returns the records fetched by fetch for domain. Concurrent
+// calls for the same domain wait for the first one's request instead of
+// making their own, each receiving a copy of its result. A caller whose
+// ctx ends while waiting returns ctx.Err() without affecting the others,
+// and callers still waiting when the first one's ctx ends make their own
+// request.
+func (p *Provider) sharedFetch(ctx context.Context, domain string, fetch func() ([]libdns.Record, error)) ([]libdns.Record, error) {
+	key := zoneKey(domain)
+	p.mu.Lock()
+	for {
+		call, ok := p.fetches[key]
+		if !ok {
+			break
+		}
+		p.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err == nil {
+			return append([]libdns.Record(nil), call.records...), nil
+		}
+		if !errors.Is(call.err, context.Canceled) && !errors.Is(call.err, context.DeadlineExceeded) {
+			return nil, call.err
+		}
+		p.mu.Lock()
+	}
+	call := &zoneFetch{done: make(chan struct{})}
+	if p.fetches == nil {
+		p.fetches = make(map[string]*zoneFetch)
+	}
+	p.fetches[key] = call
+	p.mu.Unlock()
+
+	// A change to the zone made while the request was in flight removes
+	// the fetch (see invalidateZone); its result may then be outdated and
+	// is not cached
+	defer func() {
+		p.mu.Lock()
+		current := p.fetches[key] == call
+		if current {
+			delete(p.fetches, key)
+		}
+		p.mu.Unlock()
+		if current && call.err == nil {
+			p.storeRecords(domain, call.records)
+		}
+		close(call.done)
+	}()
+	call.records, call.err = fetch()
+	if call.err != nil {
+		return nil, call.err
+	}
+	return append([]libdns.Record(nil), call.records...), nil
+}
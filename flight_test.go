@@ -0,0 +1,167 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestConcurrentGetRecordsShareRequest(t *testing.T) {
+	var listCalls atomic.Int32
+	release := make(chan struct{})
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		listCalls.Add(1)
+		<-release
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+	})
+
+	const callers = 10
+	results := make([][]libdns.Record, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = provider.GetRecords(context.Background(), "example.com.")
+		}(i)
+	}
+	// Let every caller join the request before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := listCalls.Load(); n != 1 {
+		t.Errorf("Expected concurrent reads to share 1 request, got %d", n)
+	}
+	for i := range results {
+		if errs[i] != nil || len(results[i]) != 1 || results[i][0].RR().Name != "www" {
+			t.Fatalf("Caller %d got %v, %v", i, results[i], errs[i])
+		}
+	}
+
+	// Each caller owns its slice
+	results[0][0] = libdns.RR{Name: "changed"}
+	if results[1][0].RR().Name != "www" {
+		t.Error("Expected callers to receive separate slices")
+	}
+
+	// Later reads make a new request
+	if _, err := provider.GetRecords(context.Background(), "example.com."); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if n := listCalls.Load(); n != 2 {
+		t.Errorf("Expected a new request after the shared one completed, got %d", n)
+	}
+}
+
+func TestSharedFetchErrors(t *testing.T) {
+	p := &Provider{}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	failure := errors.New("upstream failed")
+
+	done := make(chan error)
+	go func() {
+		_, err := p.sharedFetch(context.Background(), "example.com", func() ([]libdns.Record, error) {
+			close(started)
+			<-release
+			return nil, failure
+		})
+		done <- err
+	}()
+	<-started
+
+	// A waiting caller gives up when its own context ends
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.sharedFetch(ctx, "example.com", func() ([]libdns.Record, error) {
+		t.Error("Expected the request in flight to be joined")
+		return nil, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline, got %v", err)
+	}
+
+	// Errors are shared like results
+	go func() {
+		_, err := p.sharedFetch(context.Background(), "example.com", func() ([]libdns.Record, error) {
+			t.Error("Expected the request in flight to be joined")
+			return nil, nil
+		})
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-done; err != failure {
+			t.Errorf("Expected the shared error, got %v", err)
+		}
+	}
+}
+
+func TestSharedFetchLeaderCanceled(t *testing.T) {
+	p := &Provider{}
+	started := make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go p.sharedFetch(ctx, "example.com", func() ([]libdns.Record, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	records, err := p.sharedFetch(context.Background(), "example.com", func() ([]libdns.Record, error) {
+		return []libdns.Record{libdns.RR{Name: "www"}}, nil
+	})
+	if err != nil || len(records) != 1 {
+		t.Errorf("Expected a waiting caller to fetch itself after the first was canceled, got %v, %v", records, err)
+	}
+}
+
+func TestSharedFetchInvalidated(t *testing.T) {
+	p := &Provider{CacheMaxAge: time.Minute}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		p.sharedFetch(context.Background(), "example.com", func() ([]libdns.Record, error) {
+			close(started)
+			<-release
+			return []libdns.Record{libdns.RR{Name: "old"}}, nil
+		})
+	}()
+	<-started
+
+	// A change to the zone makes later reads start their own request
+	p.invalidateZone("example.com")
+	records, err := p.sharedFetch(context.Background(), "example.com", func() ([]libdns.Record, error) {
+		return []libdns.Record{libdns.RR{Name: "new"}}, nil
+	})
+	if err != nil || len(records) != 1 || records[0].RR().Name != "new" {
+		t.Errorf("Expected a new request after invalidation, got %v, %v", records, err)
+	}
+	close(release)
+	<-done
+
+	// The outdated result is not cached
+	if cached, ok := p.cachedRecords("example.com"); ok && strings.Contains(cached[0].RR().Name, "old") {
+		t.Errorf("Expected the outdated listing not to be cached, got %v", cached)
+	}
+}
@@ -0,0 +1,149 @@
+package namesilo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// ResponseFormat is the format NameSilo replies in.
+type ResponseFormat string
+
+// Response formats.
+const (
+	// FormatXML requests XML replies. This is the default.
+	FormatXML ResponseFormat = "xml"
+
+	// FormatJSON requests JSON replies, which are easier to read in debug
+	// output and avoid XML charset quirks.
+	FormatJSON ResponseFormat = "json"
+)
+
+// responseFormat returns the configured format, defaulting to XML
+func (p *Provider) responseFormat() ResponseFormat {
+	if p.ResponseFormat == FormatJSON {
+		return FormatJSON
+	}
+	return FormatXML
+}
+
+// decodeJSONResponse decodes a JSON reply into resp. The JSON is converted
+// to the equivalent XML document while it is read, so that the same
+// response types and stream decoders serve both formats.
+func decodeJSONResponse(r io.Reader, resp interface{}) error {
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(jsonToXML(r, pw))
+	}()
+
+	err := decodeResponse(pr, resp)
+
+	// Stop the conversion if decoding ended early, and wait for it so that
+	// r is not read after returning
+	pr.Close()
+	<-done
+	return err
+}
+
+// jsonToXML writes the JSON document read from r as XML with a namesilo
+// root element. Object keys become elements and array items become
+// repeated elements, mirroring NameSilo's XML replies.
+func jsonToXML(r io.Reader, w io.Writer) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	enc := xml.NewEncoder(w)
+
+	if err := convertJSONValue(dec, enc, "namesilo"); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// convertJSONValue converts the next JSON value to elements named name
+func convertJSONValue(dec *json.Decoder, enc *xml.Encoder, name string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			if err := enc.EncodeToken(start); err != nil {
+				return err
+			}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key, _ := keyTok.(string)
+				if !isXMLName(key) {
+					// e.g. "@attributes"; not used by any response type
+					var skip json.RawMessage
+					if err := dec.Decode(&skip); err != nil {
+						return err
+					}
+					continue
+				}
+				if err := convertJSONValue(dec, enc, key); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil {
+				return err
+			}
+			return enc.EncodeToken(start.End())
+		case '[':
+			for dec.More() {
+				if err := convertJSONValue(dec, enc, name); err != nil {
+					return err
+				}
+			}
+			_, err := dec.Token()
+			return err
+		}
+		return fmt.Errorf("unexpected JSON delimiter %v", t)
+	case string:
+		return encodeTextElement(enc, start, t)
+	case json.Number:
+		return encodeTextElement(enc, start, t.String())
+	case bool:
+		return encodeTextElement(enc, start, fmt.Sprint(t))
+	case nil:
+		return encodeTextElement(enc, start, "")
+	}
+	return fmt.Errorf("unexpected JSON token %v", tok)
+}
+
+// encodeTextElement writes an element containing only text
+func encodeTextElement(enc *xml.Encoder, start xml.StartElement, text string) error {
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// isXMLName reports whether s can be used as an element name
+func isXMLName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		case i > 0 && (c == '-' || c == '.' || c >= '0' && c <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
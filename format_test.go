@@ -0,0 +1,67 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestJSONResponseFormat(t *testing.T) {
+	var gotType string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		gotType = r.URL.Query().Get("type")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"request":{"operation":"dnsListRecords","ip":"192.0.2.9"},
+			"reply":{"code":300,"detail":"success","resource_record":[
+				{"record_id":"1","type":"A","host":"www.example.com","value":"192.0.2.1","ttl":"3600","distance":0},
+				{"record_id":"2","type":"MX","host":"example.com","value":"mail.example.com","ttl":7207,"distance":10}
+			]}}`)
+	})
+	provider.ResponseFormat = FormatJSON
+
+	records, err := provider.GetRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if gotType != "json" {
+		t.Errorf("Expected type=json, got %q", gotType)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	if rr := records[1].RR(); rr.Type != "MX" || rr.Data != "10 mail.example.com" {
+		t.Errorf("Unexpected MX record: %+v", rr)
+	}
+}
+
+func TestJSONErrorReply(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		// A single item is sent as an object rather than a one-element array
+		fmt.Fprint(w, `{"request":{"operation":"listDomains"},"reply":{"code":"110","detail":"Invalid API Key","domains":{"domain":"example.com"}}}`)
+	})
+	provider.ResponseFormat = FormatJSON
+
+	_, err := provider.ListDomains(context.Background(), ListDomainsOptions{})
+	if err == nil || !strings.Contains(err.Error(), "code 110") {
+		t.Errorf("Expected code 110 error, got %v", err)
+	}
+}
+
+func TestJSONToXML(t *testing.T) {
+	var b strings.Builder
+	err := jsonToXML(strings.NewReader(`{"reply":{"code":300,"ok":true,"none":null,"@attributes":{"x":"y"},"list":["a","b<"]}}`), &b)
+	if err != nil {
+		t.Fatalf("jsonToXML failed: %v", err)
+	}
+	want := "<namesilo><reply><code>300</code><ok>true</ok><none></none><list>a</list><list>b&lt;</list></reply></namesilo>"
+	if b.String() != want {
+		t.Errorf("jsonToXML = %s, want %s", b.String(), want)
+	}
+
+	var resp apiResponse
+	if err := decodeJSONResponse(strings.NewReader(`{"reply":{"code":`), &resp); err == nil {
+		t.Error("Expected error for truncated JSON")
+	}
+}
@@ -0,0 +1,108 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ForwardMethod is the way a forwarded domain redirects visitors.
+type ForwardMethod string
+
+// Forwarding methods supported by NameSilo.
+const (
+	ForwardPermanent ForwardMethod = "301"     // HTTP 301 redirect
+	ForwardTemporary ForwardMethod = "302"     // HTTP 302 redirect
+	ForwardCloaked   ForwardMethod = "cloaked" // Target shown in a frame under the original URL
+)
+
+// ForwardOptions configures domain and subdomain forwarding.
+type ForwardOptions struct {
+	// Method defaults to ForwardPermanent.
+	Method ForwardMethod
+
+	// Meta tags served by the cloaking frame; only used with ForwardCloaked.
+	MetaTitle       string
+	MetaDescription string
+	MetaKeywords    string
+}
+
+// params validates the options and target URL and returns the forwarding parameters
+func (o ForwardOptions) params(target string) (map[string]string, error) {
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("forwarding target must be an absolute http or https URL: %q", target)
+	}
+
+	method := o.Method
+	if method == "" {
+		method = ForwardPermanent
+	}
+	switch method {
+	case ForwardPermanent, ForwardTemporary, ForwardCloaked:
+	default:
+		return nil, fmt.Errorf("unsupported forwarding method %q", method)
+	}
+
+	params := map[string]string{
+		"protocol": u.Scheme,
+		"address":  strings.TrimPrefix(target, u.Scheme+"://"),
+		"method":   string(method),
+	}
+	if method == ForwardCloaked {
+		params["meta_title"] = o.MetaTitle
+		params["meta_description"] = o.MetaDescription
+		params["meta_keywords"] = o.MetaKeywords
+	}
+
+	return params, nil
+}
+
+// ForwardDomain forwards all web traffic for the domain to target, an
+// absolute http or https URL. NameSilo implements forwarding with DNS
+// records pointing at its forwarding servers, so forwarding is removed by
+// replacing those records (e.g. with SetRecords).
+func (p *Provider) ForwardDomain(ctx context.Context, domain, target string, opts ForwardOptions) error {
+	params, err := opts.params(target)
+	if err != nil {
+		return err
+	}
+	params["domain"] = strings.TrimSuffix(domain, ".")
+
+	var response apiResponse
+	return p.callAPI(ctx, "domainForward", params, &response)
+}
+
+// ForwardSubDomain forwards web traffic for a subdomain (e.g. "www") of the
+// domain to target.
+func (p *Provider) ForwardSubDomain(ctx context.Context, domain, subDomain, target string, opts ForwardOptions) error {
+	if subDomain == "" || subDomain == "@" {
+		return fmt.Errorf("subdomain is required; use ForwardDomain for the domain itself")
+	}
+
+	params, err := opts.params(target)
+	if err != nil {
+		return err
+	}
+	params["domain"] = strings.TrimSuffix(domain, ".")
+	params["sub_domain"] = normalizeRecordName(subDomain, domain)
+
+	var response apiResponse
+	return p.callAPI(ctx, "domainForwardSubDomain", params, &response)
+}
+
+// DeleteSubDomainForward removes the forwarding of a subdomain.
+func (p *Provider) DeleteSubDomainForward(ctx context.Context, domain, subDomain string) error {
+	if subDomain == "" || subDomain == "@" {
+		return fmt.Errorf("subdomain is required")
+	}
+
+	params := map[string]string{
+		"domain":     strings.TrimSuffix(domain, "."),
+		"sub_domain": normalizeRecordName(subDomain, domain),
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "domainForwardSubDomainDelete", params, &response)
+}
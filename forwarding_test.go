@@ -0,0 +1,68 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestForwardDomain(t *testing.T) {
+	var path string
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		query = r.URL.Query()
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	ctx := context.Background()
+
+	err := provider.ForwardDomain(ctx, "example.com.", "https://landing.example.net/offer?id=1", ForwardOptions{
+		Method:    ForwardCloaked,
+		MetaTitle: "Example",
+	})
+	if err != nil {
+		t.Fatalf("ForwardDomain failed: %v", err)
+	}
+	want := map[string]string{
+		"domain":     "example.com",
+		"protocol":   "https",
+		"address":    "landing.example.net/offer?id=1",
+		"method":     "cloaked",
+		"meta_title": "Example",
+	}
+	for key, value := range want {
+		if got := query[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("Parameter %s = %v, want %s", key, got, value)
+		}
+	}
+
+	if err := provider.ForwardSubDomain(ctx, "example.com", "www.example.com", "http://example.net", ForwardOptions{}); err != nil {
+		t.Fatalf("ForwardSubDomain failed: %v", err)
+	}
+	if path != "/api/domainForwardSubDomain" || query["sub_domain"][0] != "www" || query["method"][0] != "301" {
+		t.Errorf("Unexpected request %s %v", path, query)
+	}
+
+	if err := provider.DeleteSubDomainForward(ctx, "example.com", "www"); err != nil {
+		t.Fatalf("DeleteSubDomainForward failed: %v", err)
+	}
+	if path != "/api/domainForwardSubDomainDelete" {
+		t.Errorf("Unexpected request %s", path)
+	}
+}
+
+func TestForwardOptionsValidation(t *testing.T) {
+	tests := []struct {
+		target string
+		opts   ForwardOptions
+	}{
+		{"landing.example.net", ForwardOptions{}},
+		{"ftp://example.net", ForwardOptions{}},
+		{"https://example.net", ForwardOptions{Method: "307"}},
+	}
+	for _, tt := range tests {
+		if _, err := tt.opts.params(tt.target); err == nil {
+			t.Errorf("Expected error for target %q with %+v", tt.target, tt.opts)
+		}
+	}
+}
@@ -1,5 +1,5 @@
 module github.com/r6c/namesilo
 
-go 1.19
+go 1.23
 
 require github.com/libdns/libdns v1.1.0
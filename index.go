@@ -0,0 +1,92 @@
+package namesilo
+
+import (
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// recordIndex resolves records to existing NameSilo records from a single
+// zone listing, so that many lookups need no further API calls
+type recordIndex struct {
+	p      *Provider
+	zone   string
+	byName map[string][]namesileoRecord // by lowercase relative name
+	byID   map[string]namesileoRecord
+	used   map[string]bool // IDs already resolved, so each is matched once
+}
+
+// newRecordIndex indexes the records of a zone listing
+func (p *Provider) newRecordIndex(zone string, records []libdns.Record) *recordIndex {
+	idx := &recordIndex{
+		p:      p,
+		zone:   zone,
+		byName: make(map[string][]namesileoRecord),
+		byID:   make(map[string]namesileoRecord),
+		used:   make(map[string]bool),
+	}
+	for _, rec := range records {
+		nsRec, ok := rec.(namesileoRecord)
+		if !ok {
+			continue
+		}
+		name := idx.nameKey(rec.RR().Name)
+		idx.byName[name] = append(idx.byName[name], nsRec)
+		idx.byID[nsRec.ID] = nsRec
+	}
+	return idx
+}
+
+// nameKey returns the index key of a record name
+func (idx *recordIndex) nameKey(name string) string {
+	return strings.ToLower(normalizeRecordName(name, idx.zone))
+}
+
+// match returns the existing records that record refers to and marks them
+// as used. A record carrying a NameSilo ID matches that record only. As in
+// libdns, an empty type or value matches any; the TTL is not compared.
+func (idx *recordIndex) match(record libdns.Record) []namesileoRecord {
+	if id, ok := RecordID(record); ok {
+		existing, found := idx.byID[id]
+		if !found || idx.used[id] {
+			return nil
+		}
+		idx.used[id] = true
+		return []namesileoRecord{existing}
+	}
+
+	rr := record.RR()
+	want := idx.p.NormalizeRecord(idx.zone, record)
+
+	var matches []namesileoRecord
+	for _, existing := range idx.byName[idx.nameKey(rr.Name)] {
+		if idx.used[existing.ID] {
+			continue
+		}
+		got := idx.p.NormalizeRecord(idx.zone, existing)
+		if rr.Type != "" && got.Type != want.Type {
+			continue
+		}
+		if rr.Data != "" && got.Data != want.Data {
+			continue
+		}
+		idx.used[existing.ID] = true
+		matches = append(matches, existing)
+		if rr.Type != "" && rr.Data != "" {
+			// A fully specified record deletes a single match
+			break
+		}
+	}
+	return matches
+}
+
+// contains reports whether the zone has a record with the same name, type,
+// value and TTL as record
+func (idx *recordIndex) contains(record libdns.Record) bool {
+	for _, existing := range idx.byName[idx.nameKey(record.RR().Name)] {
+		if idx.p.RecordsEqual(idx.zone, existing, record) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+// countingTransport counts the API operations sent through it
+type countingTransport struct {
+	base http.RoundTripper
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.calls == nil {
+		t.calls = make(map[string]int)
+	}
+	t.calls[operationName(req)]++
+	t.mu.Unlock()
+	return t.base.RoundTrip(req)
+}
+
+func TestDeleteRecordsSingleFetch(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.2", TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "_acme-challenge", Value: "token", TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "@", Value: "mail.example.com", TTL: 3600, Distance: 10},
+		namesilotest.Record{Type: "CNAME", Host: "old", Value: "www.example.com", TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "old", Value: "stale", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "keep", Value: "192.0.2.9", TTL: 3600},
+	)
+	counter := &countingTransport{base: srv.Client().Transport}
+	provider := &Provider{APIToken: "test", HTTPClient: &http.Client{Transport: counter}}
+
+	deleted, err := provider.DeleteRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2"},
+		libdns.TXT{Name: "_acme-challenge.example.com.", Text: "token"},
+		libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com"},
+		libdns.RR{Name: "old"},
+		libdns.RR{Name: "missing", Type: "A", Data: "192.0.2.3"},
+		libdns.RR{Name: "keep", Type: "A", Data: "192.0.2.10"},
+	})
+	if err != nil {
+		t.Fatalf("DeleteRecords failed: %v", err)
+	}
+	if len(deleted) != 5 {
+		t.Errorf("Expected 5 deleted records, got %d: %v", len(deleted), deleted)
+	}
+	if counter.calls["dnsListRecords"] != 1 || counter.calls["dnsDeleteRecord"] != 5 {
+		t.Errorf("Unexpected API calls: %v", counter.calls)
+	}
+
+	remaining := srv.Records("example.com")
+	if len(remaining) != 2 {
+		t.Fatalf("Expected 2 remaining records, got %+v", remaining)
+	}
+	for _, rec := range remaining {
+		if rec.Value != "192.0.2.1" && rec.Value != "192.0.2.9" {
+			t.Errorf("Unexpected remaining record: %+v", rec)
+		}
+	}
+}
+
+func TestDeleteRecordsByID(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	added, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "one"},
+		libdns.TXT{Name: "_acme-challenge", Text: "two"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	deleted, err := provider.DeleteRecords(ctx, "example.com.", added[1:])
+	if err != nil {
+		t.Fatalf("DeleteRecords failed: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].RR().Data != "two" {
+		t.Errorf("Unexpected deleted records: %v", deleted)
+	}
+	if remaining := srv.Records("example.com"); len(remaining) != 1 || remaining[0].Value != "one" {
+		t.Errorf("Unexpected remaining records: %+v", remaining)
+	}
+}
+
+func TestDeleteRecordByID(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600})
+	counter := &countingTransport{base: srv.Client().Transport}
+	provider := &Provider{APIToken: "test", HTTPClient: &http.Client{Transport: counter}}
+	ctx := context.Background()
+
+	id := srv.Records("example.com")[0].ID
+	if err := provider.DeleteRecordByID(ctx, "example.com.", id); err != nil {
+		t.Fatalf("DeleteRecordByID failed: %v", err)
+	}
+	if n := len(srv.Records("example.com")); n != 0 {
+		t.Errorf("Expected the record to be deleted, %d left", n)
+	}
+	if counter.calls["dnsListRecords"] != 0 {
+		t.Errorf("Expected no zone listing, got %v", counter.calls)
+	}
+
+	var apiErr *APIError
+	if err := provider.DeleteRecordByID(ctx, "example.com.", id); !errors.As(err, &apiErr) {
+		t.Errorf("Expected APIError for unknown record ID, got %v", err)
+	}
+	if err := provider.DeleteRecordByID(ctx, "example.com.", ""); err == nil {
+		t.Error("Expected error for empty record ID")
+	}
+}
+
+func TestSkipExisting(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "TXT", Host: "@", Value: "v=spf1 -all", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), SkipExisting: true}
+
+	records := []libdns.Record{
+		libdns.TXT{Name: "@", Text: "v=spf1 -all", TTL: time.Hour},
+		libdns.RR{Name: "www.example.com.", Type: "A", Data: "192.0.2.1", TTL: 10 * time.Minute},
+		libdns.RR{Name: "api", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+	}
+	for run := 0; run < 2; run++ {
+		added, err := provider.AppendRecords(context.Background(), "example.com.", records)
+		if err != nil {
+			t.Fatalf("run %d: AppendRecords failed: %v", run, err)
+		}
+		want := 1
+		if run > 0 {
+			want = 0
+		}
+		if len(added) != want {
+			t.Errorf("run %d: expected %d added records, got %v", run, want, added)
+		}
+	}
+	if n := len(srv.Records("example.com")); n != 3 {
+		t.Errorf("Expected 3 records, got %d", n)
+	}
+}
+
+func TestRejectDuplicates(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), RejectDuplicates: true}
+
+	_, err := provider.AppendRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.RR{Name: "api", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+		libdns.RR{Name: "WWW", Type: "A", Data: "192.0.2.1", TTL: 10 * time.Minute},
+	})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("Expected ErrDuplicate, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "www A") {
+		t.Errorf("Expected the duplicate to be named, got %v", err)
+	}
+	if n := len(srv.Records("example.com")); n != 1 {
+		t.Errorf("Expected no record to be added, got %d records", n)
+	}
+
+}
+
+func TestAPIErrorMatchesErrDuplicate(t *testing.T) {
+	err := &APIError{Operation: "dnsAddRecord", Code: 280, Detail: "Record already exists"}
+	if !errors.Is(err, ErrDuplicate) {
+		t.Error("Expected an already-exists reply to match ErrDuplicate")
+	}
+}
@@ -0,0 +1,86 @@
+package namesilo
+
+import (
+	"context"
+	"time"
+)
+
+// CallInfo describes a completed NameSilo API call.
+type CallInfo struct {
+	Operation string        // API operation, e.g. "dnsAddRecord"
+	Zone      string        // Domain the call applied to, if any
+	ReplyCode int           // NameSilo reply code, or 0 if no reply was decoded
+	Retries   int           // Number of retries after transient failures
+	Duration  time.Duration // Total duration, including retries and rate limiting
+	Err       error         // Error returned to the caller, if any
+}
+
+// Tracer creates a span around each NameSilo API call. It mirrors the shape
+// of OpenTelemetry's trace.Tracer so an adapter is only a few lines, without
+// this package depending on OpenTelemetry:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, operation string) (context.Context, namesilo.Span) {
+//		ctx, span := t.Tracer.Start(ctx, "namesilo."+operation, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ trace.Span }
+//
+//	func (s otelSpan) End(info namesilo.CallInfo) {
+//		s.SetAttributes(
+//			attribute.String("namesilo.zone", info.Zone),
+//			attribute.Int("namesilo.reply_code", info.ReplyCode),
+//			attribute.Int("namesilo.retries", info.Retries),
+//		)
+//		if info.Err != nil {
+//			s.RecordError(info.Err)
+//			s.SetStatus(codes.Error, info.Err.Error())
+//		}
+//		s.Span.End()
+//	}
+//
+// The context returned by Start is used for the HTTP request, so trace
+// propagation through an instrumented HTTPClient transport works as usual.
+type Tracer interface {
+	Start(ctx context.Context, operation string) (context.Context, Span)
+}
+
+// Span is an in-progress span for a single API call.
+type Span interface {
+	End(info CallInfo)
+}
+
+// Metrics receives measurements of API usage, e.g. to export them as
+// Prometheus metrics. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveCall is called once for every completed API call. Request
+	// counts, error counts by reply code and latencies can all be derived
+	// from it.
+	ObserveCall(info CallInfo)
+
+	// ObserveThrottle is called when a request was delayed by the rate
+	// limiter (see RequestsPerSecond), with the time it waited.
+	ObserveThrottle(wait time.Duration)
+}
+
+// startSpan starts a span for an API call if a Tracer is configured
+func (p *Provider) startSpan(ctx context.Context, operation string) (context.Context, Span) {
+	if p.Tracer == nil {
+		return ctx, nil
+	}
+	return p.Tracer.Start(ctx, operation)
+}
+
+// observeCall reports a completed API call to the configured span, metrics
+// and logger
+func (p *Provider) observeCall(ctx context.Context, span Span, info CallInfo) {
+	if span != nil {
+		span.End(info)
+	}
+	if p.Metrics != nil {
+		p.Metrics.ObserveCall(info)
+	}
+	p.logCall(ctx, info)
+}
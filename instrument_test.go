@@ -0,0 +1,118 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testTracer struct {
+	started []string
+	ended   []CallInfo
+}
+
+func (t *testTracer) Start(ctx context.Context, operation string) (context.Context, Span) {
+	t.started = append(t.started, operation)
+	return ctx, testSpan{t}
+}
+
+type testSpan struct{ t *testTracer }
+
+func (s testSpan) End(info CallInfo) {
+	s.t.ended = append(s.t.ended, info)
+}
+
+func TestTracer(t *testing.T) {
+	calls := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Path == "/api/dnsListRecords" && calls == 1:
+			w.Write([]byte(`<namesilo><reply><code>115</code><detail>try again later</detail></reply></namesilo>`))
+		case r.URL.Path == "/api/dnsListRecords":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>200</code><detail>Domain is not active, or does not belong to this user</detail></reply></namesilo>`))
+		}
+	})
+	provider.MaxRetries = 1
+	provider.RetryBaseDelay = time.Millisecond
+	tracer := &testTracer{}
+	provider.Tracer = tracer
+	ctx := context.Background()
+
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	_, err := provider.ListDomains(ctx, ListDomainsOptions{})
+
+	if len(tracer.started) != 2 || len(tracer.ended) != 2 {
+		t.Fatalf("Expected 2 spans, got started %v ended %v", tracer.started, tracer.ended)
+	}
+
+	list := tracer.ended[0]
+	if list.Operation != "dnsListRecords" || list.Zone != "example.com" || list.ReplyCode != 300 || list.Retries != 1 || list.Err != nil {
+		t.Errorf("Unexpected span info: %+v", list)
+	}
+	if list.Duration <= 0 {
+		t.Error("Expected positive duration")
+	}
+
+	failed := tracer.ended[1]
+	if failed.ReplyCode != 200 || !errors.Is(failed.Err, ErrDomainNotInAccount) || failed.Err != err {
+		t.Errorf("Unexpected failed span info: %+v", failed)
+	}
+}
+
+type testMetrics struct {
+	mu        sync.Mutex
+	calls     []CallInfo
+	throttles []time.Duration
+}
+
+func (m *testMetrics) ObserveCall(info CallInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, info)
+}
+
+func (m *testMetrics) ObserveThrottle(wait time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.throttles = append(m.throttles, wait)
+}
+
+func TestMetrics(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.RequestsPerSecond = 50
+	metrics := &testMetrics{}
+	provider.Metrics = metrics
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+			t.Fatalf("ListDomains failed: %v", err)
+		}
+	}
+
+	if len(metrics.calls) != 3 {
+		t.Fatalf("Expected 3 observed calls, got %d", len(metrics.calls))
+	}
+	if metrics.calls[0].Operation != "listDomains" || metrics.calls[0].ReplyCode != 300 {
+		t.Errorf("Unexpected call info: %+v", metrics.calls[0])
+	}
+	// The first request uses the burst token; the others wait ~20ms each
+	if len(metrics.throttles) != 2 {
+		t.Fatalf("Expected 2 throttles, got %v", metrics.throttles)
+	}
+	for _, wait := range metrics.throttles {
+		if wait <= 0 {
+			t.Errorf("Expected positive throttle wait, got %v", wait)
+		}
+	}
+}
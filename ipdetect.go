@@ -0,0 +1,252 @@
+package namesilo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// IPDetector finds the public IP address of this machine. network is
+// "ip4" or "ip6". Implementations must be safe for concurrent use.
+type IPDetector interface {
+	DetectIP(ctx context.Context, network string) (netip.Addr, error)
+}
+
+// IPDetectorFunc adapts a function to an IPDetector.
+type IPDetectorFunc func(ctx context.Context, network string) (netip.Addr, error)
+
+// DetectIP calls f.
+func (f IPDetectorFunc) DetectIP(ctx context.Context, network string) (netip.Addr, error) {
+	return f(ctx, network)
+}
+
+// HTTPSEchoDetector asks a web service that replies with the caller's IP
+// address in plain text, such as ipify.
+type HTTPSEchoDetector struct {
+	// IPv4URL and IPv6URL are the services queried for each address
+	// family. They default to api.ipify.org and api6.ipify.org.
+	IPv4URL string
+	IPv6URL string
+
+	// HTTPClient is used for the requests. Defaults to a client with a
+	// 10 second timeout.
+	HTTPClient *http.Client
+}
+
+// DetectIP queries the echo service for the address family.
+func (d HTTPSEchoDetector) DetectIP(ctx context.Context, network string) (netip.Addr, error) {
+	target := d.IPv4URL
+	if target == "" {
+		target = "https://api.ipify.org"
+	}
+	if network == "ip6" {
+		target = d.IPv6URL
+		if target == "" {
+			target = "https://api6.ipify.org"
+		}
+	}
+	client := d.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return netip.Addr{}, fmt.Errorf("%s: unexpected HTTP status %d", target, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%s: %w", target, err)
+	}
+	return checkFamily(addr.Unmap(), network)
+}
+
+// STUNDetector asks a STUN server (RFC 5389) for the address it sees
+// requests coming from.
+type STUNDetector struct {
+	// Server is the STUN server as "host:port". Defaults to
+	// stun.l.google.com:19302.
+	Server string
+}
+
+// stunMagicCookie is the fixed value in every STUN message header
+const stunMagicCookie = 0x2112A442
+
+// DetectIP sends a STUN binding request over UDP.
+func (d STUNDetector) DetectIP(ctx context.Context, network string) (netip.Addr, error) {
+	server := d.Server
+	if server == "" {
+		server = "stun.l.google.com:19302"
+	}
+	udp := "udp4"
+	if network == "ip6" {
+		udp = "udp6"
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, udp, server)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	conn.SetDeadline(deadline)
+
+	req := make([]byte, 20)
+	binary.BigEndian.PutUint16(req[0:], 0x0001) // Binding request
+	binary.BigEndian.PutUint32(req[4:], stunMagicCookie)
+	if _, err := rand.Read(req[8:20]); err != nil {
+		return netip.Addr{}, err
+	}
+	if _, err := conn.Write(req); err != nil {
+		return netip.Addr{}, err
+	}
+
+	resp := make([]byte, 1500)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	addr, err := parseSTUNResponse(resp[:n], req[8:20])
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%s: %w", server, err)
+	}
+	return checkFamily(addr, network)
+}
+
+// parseSTUNResponse extracts the mapped address from a binding response
+func parseSTUNResponse(msg, transactionID []byte) (netip.Addr, error) {
+	if len(msg) < 20 || binary.BigEndian.Uint16(msg[0:]) != 0x0101 ||
+		binary.BigEndian.Uint32(msg[4:]) != stunMagicCookie || string(msg[8:20]) != string(transactionID) {
+		return netip.Addr{}, errors.New("invalid STUN binding response")
+	}
+
+	var mapped netip.Addr
+	attrs := msg[20:]
+	for len(attrs) >= 4 {
+		typ := binary.BigEndian.Uint16(attrs[0:])
+		length := int(binary.BigEndian.Uint16(attrs[2:]))
+		if 4+length > len(attrs) {
+			break
+		}
+		value := attrs[4 : 4+length]
+
+		switch typ {
+		case 0x0020: // XOR-MAPPED-ADDRESS
+			if addr, ok := stunAddress(value, msg[4:20]); ok {
+				return addr, nil
+			}
+		case 0x0001: // MAPPED-ADDRESS
+			if addr, ok := stunAddress(value, nil); ok {
+				mapped = addr
+			}
+		}
+
+		// Attributes are padded to a multiple of four bytes
+		attrs = attrs[4+(length+3)&^3:]
+	}
+
+	if mapped.IsValid() {
+		return mapped, nil
+	}
+	return netip.Addr{}, errors.New("STUN response has no mapped address")
+}
+
+// stunAddress decodes a (XOR-)MAPPED-ADDRESS value. xor is the magic cookie
+// followed by the transaction ID, or nil for a plain MAPPED-ADDRESS.
+func stunAddress(value, xor []byte) (netip.Addr, bool) {
+	if len(value) < 4 {
+		return netip.Addr{}, false
+	}
+	ip := append([]byte(nil), value[4:]...)
+	for i := range ip {
+		if xor != nil && i < len(xor) {
+			ip[i] ^= xor[i]
+		}
+	}
+
+	switch {
+	case value[1] == 0x01 && len(ip) == 4:
+		return netip.AddrFrom4([4]byte(ip)), true
+	case value[1] == 0x02 && len(ip) == 16:
+		return netip.AddrFrom16([16]byte(ip)), true
+	}
+	return netip.Addr{}, false
+}
+
+// InterfaceDetector uses a global unicast address of a local network
+// interface, for hosts with a public address assigned directly.
+type InterfaceDetector struct {
+	// Interface restricts detection to the named interface, e.g. "eth0".
+	// All interfaces are considered if empty.
+	Interface string
+}
+
+// DetectIP returns the first public address of the interfaces.
+func (d InterfaceDetector) DetectIP(ctx context.Context, network string) (netip.Addr, error) {
+	var ifaces []net.Interface
+	if d.Interface != "" {
+		iface, err := net.InterfaceByName(d.Interface)
+		if err != nil {
+			return netip.Addr{}, err
+		}
+		ifaces = []net.Interface{*iface}
+	} else {
+		var err error
+		if ifaces, err = net.Interfaces(); err != nil {
+			return netip.Addr{}, err
+		}
+	}
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			prefix, err := netip.ParsePrefix(a.String())
+			if err != nil {
+				continue
+			}
+			addr := prefix.Addr().Unmap()
+			if addr.IsGlobalUnicast() && !addr.IsPrivate() {
+				if addr, err := checkFamily(addr, network); err == nil {
+					return addr, nil
+				}
+			}
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("no public %s address on local interfaces", network)
+}
+
+// checkFamily returns addr if it belongs to network ("ip4" or "ip6")
+func checkFamily(addr netip.Addr, network string) (netip.Addr, error) {
+	if (network == "ip6") != addr.Is6() {
+		return netip.Addr{}, fmt.Errorf("got %s, which is not an %s address", addr, network)
+	}
+	return addr, nil
+}
@@ -0,0 +1,94 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestHTTPSEchoDetector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v6" {
+			fmt.Fprintln(w, "2001:db8::1")
+			return
+		}
+		fmt.Fprintln(w, "203.0.113.5")
+	}))
+	defer srv.Close()
+
+	d := HTTPSEchoDetector{IPv4URL: srv.URL + "/v4", IPv6URL: srv.URL + "/v6", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	if addr, err := d.DetectIP(ctx, "ip4"); err != nil || addr != netip.MustParseAddr("203.0.113.5") {
+		t.Errorf("DetectIP(ip4) = %v, %v", addr, err)
+	}
+	if addr, err := d.DetectIP(ctx, "ip6"); err != nil || addr != netip.MustParseAddr("2001:db8::1") {
+		t.Errorf("DetectIP(ip6) = %v, %v", addr, err)
+	}
+
+	wrong := HTTPSEchoDetector{IPv4URL: srv.URL + "/v6", HTTPClient: srv.Client()}
+	if _, err := wrong.DetectIP(ctx, "ip4"); err == nil {
+		t.Error("Expected error for an address of the wrong family")
+	}
+}
+
+func TestSTUNDetector(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	defer conn.Close()
+
+	mapped := netip.MustParseAddr("198.51.100.20")
+	go func() {
+		buf := make([]byte, 1500)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil || n < 20 {
+			return
+		}
+		resp := make([]byte, 20, 32)
+		binary.BigEndian.PutUint16(resp[0:], 0x0101)
+		binary.BigEndian.PutUint16(resp[2:], 12)
+		copy(resp[4:20], buf[4:20])
+
+		// XOR-MAPPED-ADDRESS
+		ip := mapped.As4()
+		for i := range ip {
+			ip[i] ^= buf[4+i]
+		}
+		resp = append(resp, 0x00, 0x20, 0x00, 0x08, 0x00, 0x01, 0x00, 0x00)
+		resp = append(resp, ip[:]...)
+		conn.WriteTo(resp, addr)
+	}()
+
+	d := STUNDetector{Server: conn.LocalAddr().String()}
+	addr, err := d.DetectIP(context.Background(), "ip4")
+	if err != nil {
+		t.Fatalf("DetectIP failed: %v", err)
+	}
+	if addr != mapped {
+		t.Errorf("DetectIP = %v, want %v", addr, mapped)
+	}
+}
+
+func TestParseSTUNResponseErrors(t *testing.T) {
+	id := make([]byte, 12)
+	header := make([]byte, 20)
+	binary.BigEndian.PutUint16(header[0:], 0x0101)
+	binary.BigEndian.PutUint32(header[4:], stunMagicCookie)
+
+	if _, err := parseSTUNResponse(header[:10], id); err == nil {
+		t.Error("Expected error for a short message")
+	}
+	if _, err := parseSTUNResponse(header, id); err == nil {
+		t.Error("Expected error for a response without an address")
+	}
+	if _, err := parseSTUNResponse(header, []byte("other-txn-id")); err == nil {
+		t.Error("Expected error for a mismatched transaction ID")
+	}
+}
@@ -0,0 +1,125 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// recordSink receives the records of a dnsListRecords reply as they are
+// decoded, instead of collecting them in dnsListResponse.Records
+type recordSink struct {
+	// yield is called with each record and the reason it is malformed, if
+	// it is; decoding stops when it returns false
+	yield func(libdns.Record, error) bool
+
+	yielded bool // records were passed on, so the request cannot be retried
+	stopped bool // yield asked to stop before the end of the reply
+}
+
+type recordSinkKey struct{}
+
+// withRecordSink returns a context whose dnsListRecords replies are decoded
+// into sink
+func withRecordSink(ctx context.Context, sink *recordSink) context.Context {
+	return context.WithValue(ctx, recordSinkKey{}, sink)
+}
+
+// recordSinkFrom returns the recordSink of ctx, or nil
+func recordSinkFrom(ctx context.Context) *recordSink {
+	sink, _ := ctx.Value(recordSinkKey{}).(*recordSink)
+	return sink
+}
+
+// attachRecordSink makes resp pass its records to the recordSink of ctx, if
+// resp is a dnsListRecords reply and ctx has one
+func attachRecordSink(ctx context.Context, resp interface{}) {
+	if list, ok := resp.(*dnsListResponse); ok {
+		list.sink = recordSinkFrom(ctx)
+	}
+}
+
+// RecordsIter lists the records in the zone like GetRecords, yielding each
+// record as soon as it has been decoded from NameSilo's reply, so that huge
+// zones can be processed without holding all their records in memory:
+//
+//	for record, err := range provider.RecordsIter(ctx, "example.com") {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+//
+// If the listing fails, the error is yielded once with a nil record and the
+// iteration ends; records yielded before are valid. Breaking out of the
+// loop stops reading the reply. The records are not cached, but a zone
+// cached by CacheMaxAge is served from the cache.
+func (p *Provider) RecordsIter(ctx context.Context, zone string) iter.Seq2[libdns.Record, error] {
+	return func(yield func(libdns.Record, error) bool) {
+		if err := p.recordsIter(ctx, zone, yield); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// recordsIter implements RecordsIter, returning the error that ended the
+// listing
+func (p *Provider) recordsIter(ctx context.Context, zone string, yield func(libdns.Record, error) bool) error {
+	if !p.hasCredentials() {
+		return fmt.Errorf("API token is required")
+	}
+
+	sub, err := p.resolveZone(ctx, zone)
+	if err != nil {
+		return err
+	}
+	domain := strings.TrimSuffix(zone, ".")
+	if sub != nil {
+		domain = sub.domain
+	}
+
+	// emit maps a record of the domain into the zone and yields it,
+	// skipping records outside a subzone
+	emit := func(record libdns.Record) bool {
+		record = relativeRecord(domain, record)
+		if sub != nil {
+			var inside bool
+			if record, inside = sub.fromDomain(record); !inside {
+				return true
+			}
+		}
+		return yield(record, nil)
+	}
+
+	if records, ok := p.cachedRecords(domain); ok {
+		for _, record := range records {
+			if !emit(record) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	sink := &recordSink{
+		yield: func(record libdns.Record, malformed error) bool {
+			if malformed != nil && p.Logger != nil {
+				p.Logger.WarnContext(ctx, "namesilo returned a malformed record", "zone", domain, "error", malformed)
+			}
+			return emit(record)
+		},
+	}
+	params := map[string]string{
+		"domain": domain,
+	}
+	var response dnsListResponse
+	err = p.withZoneAttached(ctx, domain, func() error {
+		return p.callAPI(withRecordSink(ctx, sink), "dnsListRecords", params, &response)
+	})
+	if sink.stopped {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// listReply returns a dnsListRecords reply with n A records named www<i>
+func listReply(n int, format ResponseFormat) string {
+	var b strings.Builder
+	if format == FormatJSON {
+		b.WriteString(`{"request":{"operation":"dnsListRecords"},"reply":{"code":300,"detail":"success","resource_record":[`)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, `{"record_id":"%d","type":"A","host":"www%d.example.com","value":"192.0.2.1","ttl":"3600"}`, i, i)
+		}
+		b.WriteString(`]}}`)
+		return b.String()
+	}
+	b.WriteString(`<namesilo><reply><code>300</code><detail>success</detail>`)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `<resource_record><record_id>%d</record_id><type>A</type><host>www%d.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>`, i, i)
+	}
+	b.WriteString(`</reply></namesilo>`)
+	return b.String()
+}
+
+func TestRecordsIter(t *testing.T) {
+	for _, format := range []ResponseFormat{FormatXML, FormatJSON} {
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(listReply(3, format)))
+		})
+		provider.ResponseFormat = format
+
+		var names []string
+		for record, err := range provider.RecordsIter(context.Background(), "example.com.") {
+			if err != nil {
+				t.Fatalf("%s: RecordsIter failed: %v", format, err)
+			}
+			if id, _ := RecordID(record); id == "" {
+				t.Errorf("%s: Expected record IDs, got %+v", format, record)
+			}
+			names = append(names, record.RR().Name)
+		}
+		if got := strings.Join(names, " "); got != "www0 www1 www2" {
+			t.Errorf("%s: Unexpected records %s", format, got)
+		}
+	}
+}
+
+func TestRecordsIterBreak(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(listReply(1000, FormatXML)))
+	})
+	provider.StrictDecoding = true
+
+	count := 0
+	for _, err := range provider.RecordsIter(context.Background(), "example.com") {
+		if err != nil {
+			t.Fatalf("RecordsIter failed: %v", err)
+		}
+		if count++; count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected the loop to stop after 2 records, got %d", count)
+	}
+}
+
+func TestRecordsIterErrors(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		// A reply cut off after its first record
+		body := listReply(2, FormatXML)
+		w.Write([]byte(body[:strings.Index(body, "</resource_record>")+len("</resource_record>")+10]))
+	})
+	provider.MaxRetries = 3
+	provider.RetryBaseDelay = time.Millisecond
+	provider.RetryPolicy = func(a RetryAttempt) RetryClass { return RetryTransient }
+
+	var records []libdns.Record
+	var errs []error
+	for record, err := range provider.RecordsIter(context.Background(), "example.com") {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	// The failed first attempt is retried, but not the cut-off reply,
+	// whose first record has already been yielded
+	if attempts != 2 || len(records) != 1 || len(errs) != 1 {
+		t.Errorf("Expected 2 attempts, 1 record and 1 error, got %d, %v and %v", attempts, records, errs)
+	}
+
+	var none Provider
+	for record, err := range none.RecordsIter(context.Background(), "example.com") {
+		if err == nil || record != nil {
+			t.Errorf("Expected an error without credentials, got %v, %v", record, err)
+		}
+	}
+}
+
+func TestRecordsIterSubzoneAndCache(t *testing.T) {
+	lists := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		lists++
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>www.dev.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>2</record_id><type>A</type><host>www.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+	})
+	provider.Subzones = map[string]string{"dev.example.com": "example.com", "example.com": "example.com"}
+	provider.CacheMaxAge = time.Minute
+	ctx := context.Background()
+
+	collect := func() []string {
+		var names []string
+		for record, err := range provider.RecordsIter(ctx, "dev.example.com") {
+			if err != nil {
+				t.Fatalf("RecordsIter failed: %v", err)
+			}
+			names = append(names, record.RR().Name)
+		}
+		return names
+	}
+	if got := collect(); strings.Join(got, " ") != "www" {
+		t.Errorf("Expected only the subzone's records, got %v", got)
+	}
+	if collect(); lists != 2 {
+		t.Errorf("Expected each iteration to list the zone, got %d listings", lists)
+	}
+
+	// Iterating does not fill the cache, but a cached zone is used
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if got := collect(); strings.Join(got, " ") != "www" || lists != 3 {
+		t.Errorf("Expected the cached zone to be iterated, got %v after %d listings", got, lists)
+	}
+}
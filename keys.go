@@ -0,0 +1,145 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// KeyRotation is the strategy for using several API keys.
+type KeyRotation string
+
+// Key rotation strategies.
+const (
+	// KeyFailover uses the first key until NameSilo refuses it, then
+	// switches to the next key for this and all later requests.
+	KeyFailover KeyRotation = "failover"
+
+	// KeyRoundRobin spreads requests evenly across all keys, still moving
+	// on to the next key when one is refused.
+	KeyRoundRobin KeyRotation = "round_robin"
+)
+
+// keyFailureCodes are reply codes meaning the API key itself was refused
+var keyFailureCodes = map[int]bool{
+	109: true, // No API key
+	110: true, // Invalid API key
+	113: true, // API access not allowed from this IP address
+}
+
+// tokens returns the API keys to use for a request, fetching the current
+// key from TokenSource if one is configured
+func (p *Provider) tokens(ctx context.Context) ([]string, error) {
+	if p.TokenSource == nil {
+		return p.staticTokens(), nil
+	}
+
+	token, err := p.TokenSource.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting API token: %w", err)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("token source returned an empty API token")
+	}
+	p.rememberToken(token)
+
+	return []string{token}, nil
+}
+
+// staticTokens returns the configured API keys in order, with environment
+// placeholders expanded
+func (p *Provider) staticTokens() []string {
+	return p.accountTokens(Account{APIToken: p.APIToken, APITokens: p.APITokens})
+}
+
+// hasCredentials reports whether an API key or token source is configured
+func (p *Provider) hasCredentials() bool {
+	return p.TokenSource != nil || len(p.staticTokens()) > 0 || len(p.Accounts) > 0
+}
+
+// domainTokens returns the API keys to use for a request about domain: the
+// keys of the account holding it, or else those of tokens. It reports
+// whether the keys belong to one of the Accounts.
+func (p *Provider) domainTokens(ctx context.Context, domain string) ([]string, bool, error) {
+	if account := p.account(domain); account != nil {
+		if tokens := p.accountTokens(*account); len(tokens) > 0 {
+			return tokens, true, nil
+		}
+		return nil, true, fmt.Errorf("API token is required for the account of %s", domain)
+	}
+
+	tokens, err := p.tokens(ctx)
+	if err == nil && len(tokens) == 0 {
+		if domain == "" {
+			return nil, false, fmt.Errorf("API token is required for operations outside the configured accounts")
+		}
+		return nil, false, fmt.Errorf("API token is required: %s is not in any of the configured accounts", domain)
+	}
+	return tokens, false, err
+}
+
+// rememberToken records a key returned by TokenSource so it can be redacted.
+// The previous key is kept as well, since requests made with it may still
+// be in flight after a rotation.
+func (p *Provider) rememberToken(token string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.sourceTokens) > 0 && p.sourceTokens[len(p.sourceTokens)-1] == token {
+		return
+	}
+	p.sourceTokens = append(p.sourceTokens, token)
+	if len(p.sourceTokens) > 2 {
+		p.sourceTokens = p.sourceTokens[len(p.sourceTokens)-2:]
+	}
+}
+
+// knownTokens returns every API key that may appear in requests
+func (p *Provider) knownTokens() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	tokens := append(p.staticTokens(), p.sourceTokens...)
+	for _, account := range p.Accounts {
+		tokens = append(tokens, p.accountTokens(account)...)
+	}
+	return tokens
+}
+
+// selectKey returns the index of the key to use first for a request
+func (p *Provider) selectKey(n int) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := p.keyNext % n
+	if p.KeyRotation == KeyRoundRobin {
+		p.keyNext = (key + 1) % n
+	}
+	return key
+}
+
+// keyFailed moves on from a refused key. With failover the next key
+// becomes the preferred one; round robin moves on anyway.
+func (p *Provider) keyFailed(key, n int) {
+	if p.KeyRotation == KeyRoundRobin {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.keyNext%n == key {
+		p.keyNext = (key + 1) % n
+	}
+}
+
+// isKeyFailure reports whether err means the API key was refused or rate
+// limited, so that another key should be tried
+func isKeyFailure(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return keyFailureCodes[apiErr.Code]
+	}
+
+	var statusErr *httpStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests
+}
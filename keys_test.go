@@ -0,0 +1,79 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// keyServer replies with success for valid keys and code 110 otherwise,
+// recording the key of every request
+func keyServer(t *testing.T, valid map[string]bool, used *[]string) *Provider {
+	return newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		*used = append(*used, key)
+		if !valid[key] {
+			w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+}
+
+func TestKeyFailover(t *testing.T) {
+	var used []string
+	provider := keyServer(t, map[string]bool{"new": true}, &used)
+	provider.APIToken = "old"
+	provider.APITokens = []string{"new"}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+			t.Fatalf("ListDomains failed: %v", err)
+		}
+	}
+
+	// The refused key is skipped for later requests
+	if got := strings.Join(used, ","); got != "old,new,new" {
+		t.Errorf("Unexpected key sequence %s", got)
+	}
+}
+
+func TestKeyRoundRobin(t *testing.T) {
+	var used []string
+	provider := keyServer(t, map[string]bool{"a": true, "b": true, "c": true}, &used)
+	provider.APIToken = "a"
+	provider.APITokens = []string{"b", "c", "a"}
+	provider.KeyRotation = KeyRoundRobin
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+			t.Fatalf("ListDomains failed: %v", err)
+		}
+	}
+
+	if got := strings.Join(used, ","); got != "a,b,c,a" {
+		t.Errorf("Unexpected key sequence %s", got)
+	}
+}
+
+func TestAllKeysRefused(t *testing.T) {
+	var used []string
+	provider := keyServer(t, map[string]bool{}, &used)
+	provider.APIToken = "a"
+	provider.APITokens = []string{"b"}
+
+	_, err := provider.ListDomains(context.Background(), ListDomainsOptions{})
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if len(used) != 2 {
+		t.Errorf("Expected each key to be tried once, got %v", used)
+	}
+	if !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
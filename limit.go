@@ -0,0 +1,59 @@
+package namesilo
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaultMaxResponseSize bounds replies when MaxResponseSize is not set. It
+// leaves room for the record lists of very large zones.
+const defaultMaxResponseSize = 16 << 20
+
+// ResponseTooLargeError is returned when a reply is larger than
+// MaxResponseSize, which points to a misbehaving proxy or endpoint.
+type ResponseTooLargeError struct {
+	Limit int64 // the size limit in bytes
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("namesilo: response exceeds %d bytes", e.Limit)
+}
+
+// maxResponseSize returns the largest reply body that is read
+func (p *Provider) maxResponseSize() int64 {
+	if p.MaxResponseSize > 0 {
+		return p.MaxResponseSize
+	}
+	return defaultMaxResponseSize
+}
+
+// limitedBody reads at most limit bytes and then fails with a
+// ResponseTooLargeError, unlike io.LimitReader, which ends silently and
+// would let a truncated reply be decoded
+type limitedBody struct {
+	r     io.Reader // io.LimitReader allowing one byte past limit
+	limit int64
+	read  int64
+	err   error // set once the limit is exceeded
+}
+
+// newLimitedBody returns a reader failing once r yields more than limit
+// bytes
+func newLimitedBody(r io.Reader, limit int64) *limitedBody {
+	return &limitedBody{r: io.LimitReader(r, limit+1), limit: limit}
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := l.r.Read(p)
+	if l.read+int64(n) > l.limit {
+		n = int(l.limit - l.read)
+		l.read = l.limit
+		l.err = &ResponseTooLargeError{Limit: l.limit}
+		return n, l.err
+	}
+	l.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,68 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	reply := `<namesilo><reply><code>300</code><detail>success</detail>` +
+		strings.Repeat(`<resource_record><record_id>1</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>`, 50) +
+		`</reply></namesilo>`
+
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Write([]byte(reply))
+	})
+	provider.MaxRetries = 3
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("Expected the default limit to allow the reply, got %v", err)
+	}
+
+	provider.MaxResponseSize = int64(len(reply)) - 1
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != provider.MaxResponseSize {
+		t.Fatalf("Expected a ResponseTooLargeError, got %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected no retries, got %d attempts", attempts)
+	}
+
+	provider.MaxResponseSize = int64(len(reply))
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Errorf("Expected a reply of exactly the limit to be read, got %v", err)
+	}
+}
+
+func TestMaxResponseSizeJSON(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"reply":{"code":300,"detail":"` + strings.Repeat("x", 4096) + `"}}`))
+	})
+	provider.ResponseFormat = FormatJSON
+	provider.MaxResponseSize = 1024
+
+	var tooLarge *ResponseTooLargeError
+	if _, err := provider.GetRecords(context.Background(), "example.com"); !errors.As(err, &tooLarge) {
+		t.Fatalf("Expected a ResponseTooLargeError, got %v", err)
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	data, err := io.ReadAll(newLimitedBody(strings.NewReader("hello"), 5))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("Expected the whole body, got %q, %v", data, err)
+	}
+
+	data, err = io.ReadAll(newLimitedBody(strings.NewReader("hello world"), 5))
+	var tooLarge *ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || string(data) != "hello" {
+		t.Errorf("Expected a ResponseTooLargeError after 5 bytes, got %q, %v", data, err)
+	}
+}
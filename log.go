@@ -0,0 +1,57 @@
+package namesilo
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// logCall logs the outcome of an API operation. Successful calls are logged
+// at debug level and failures at info level, since callers usually handle
+// (or retry) the returned error themselves.
+func (p *Provider) logCall(ctx context.Context, info CallInfo) {
+	if p.Logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", info.Operation),
+		slog.Duration("duration", info.Duration),
+	}
+	if info.Zone != "" {
+		attrs = append(attrs, slog.String("zone", info.Zone))
+	}
+	if info.ReplyCode != 0 {
+		attrs = append(attrs, slog.Int("reply_code", info.ReplyCode))
+	}
+	if info.Retries > 0 {
+		attrs = append(attrs, slog.Int("retries", info.Retries))
+	}
+
+	if info.Err != nil {
+		attrs = append(attrs, slog.String("error", p.redact(info.Err.Error())))
+		p.Logger.LogAttrs(ctx, slog.LevelInfo, "namesilo API call failed", attrs...)
+		return
+	}
+	p.Logger.LogAttrs(ctx, slog.LevelDebug, "namesilo API call", attrs...)
+}
+
+// logRetry logs a retry of a transient failure
+func (p *Provider) logRetry(ctx context.Context, operation string, attempt int, delay time.Duration, err error, code int) {
+	if p.Logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.Int("attempt", attempt+1),
+		slog.Duration("delay", delay),
+	}
+	if code != 0 {
+		attrs = append(attrs, slog.Int("reply_code", code))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", p.redact(err.Error())))
+	}
+	p.Logger.LogAttrs(ctx, slog.LevelDebug, "retrying namesilo API call", attrs...)
+}
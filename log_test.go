@@ -0,0 +1,68 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/dnsListRecords":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+		}
+	})
+	provider.Logger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.Background()
+
+	if _, err := provider.GetRecords(ctx, "example.com."); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err == nil {
+		t.Fatal("Expected ListDomains to fail")
+	}
+
+	if strings.Contains(buf.String(), provider.APIToken) {
+		t.Fatalf("API key leaked into logs: %s", buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "DEBUG" || entry["operation"] != "dnsListRecords" || entry["zone"] != "example.com" || entry["reply_code"] != float64(300) {
+		t.Errorf("Unexpected success entry: %v", entry)
+	}
+	if _, ok := entry["duration"]; !ok {
+		t.Error("Expected duration attribute")
+	}
+
+	entry = nil
+	if err := json.Unmarshal([]byte(lines[1]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "INFO" || entry["operation"] != "listDomains" || entry["reply_code"] != float64(110) || entry["error"] == nil {
+		t.Errorf("Unexpected failure entry: %v", entry)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	provider := &Provider{APIToken: "secret123"}
+	got := provider.redact(`Get "https://www.namesilo.com/api/listDomains?key=secret123": timeout`)
+	if strings.Contains(got, "secret123") || !strings.Contains(got, "key="+redactedToken) {
+		t.Errorf("Unexpected redacted string %q", got)
+	}
+}
@@ -0,0 +1,490 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// maxSPFLookups is the number of DNS lookups an SPF evaluation may cause
+// before it fails with a permanent error (RFC 7208, section 4.6.4)
+const maxSPFLookups = 10
+
+// SPFQualifier is the result an SPF mechanism yields when it matches.
+type SPFQualifier string
+
+const (
+	SPFPass     SPFQualifier = "+"
+	SPFFail     SPFQualifier = "-"
+	SPFSoftFail SPFQualifier = "~"
+	SPFNeutral  SPFQualifier = "?"
+)
+
+// SPFBuilder composes an SPF policy, for example:
+//
+//	spf := namesilo.NewSPF().MX().Include("_spf.google.com").All(namesilo.SPFFail)
+//	record, err := spf.Record("@", time.Hour)
+//
+// Mechanisms are emitted in the order they are added. Errors, such as an
+// invalid address, are reported by Value and Record.
+type SPFBuilder struct {
+	terms    []string
+	all      SPFQualifier
+	redirect string
+	lookups  int
+	err      error
+}
+
+// NewSPF returns an empty SPF policy. Unless All or Redirect is called, the
+// policy ends in "~all".
+func NewSPF() *SPFBuilder {
+	return &SPFBuilder{}
+}
+
+// add appends a term unless it is already present
+func (b *SPFBuilder) add(term string, lookup bool) *SPFBuilder {
+	for _, t := range b.terms {
+		if strings.EqualFold(t, term) {
+			return b
+		}
+	}
+	b.terms = append(b.terms, term)
+	if lookup {
+		b.lookups++
+	}
+	return b
+}
+
+// fail records the first error of the builder
+func (b *SPFBuilder) fail(format string, args ...any) *SPFBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf("SPF: "+format, args...)
+	}
+	return b
+}
+
+// Include authorizes the senders of other domains' SPF policies, such as
+// "_spf.google.com".
+func (b *SPFBuilder) Include(domains ...string) *SPFBuilder {
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+		if domain == "" || strings.ContainsAny(domain, " ;\"") {
+			b.fail("invalid include domain %q", domain)
+			continue
+		}
+		b.add("include:"+domain, true)
+	}
+	return b
+}
+
+// IP4 authorizes IPv4 addresses or CIDR ranges.
+func (b *SPFBuilder) IP4(prefixes ...string) *SPFBuilder {
+	return b.addIPs("ip4", prefixes, true)
+}
+
+// IP6 authorizes IPv6 addresses or CIDR ranges.
+func (b *SPFBuilder) IP6(prefixes ...string) *SPFBuilder {
+	return b.addIPs("ip6", prefixes, false)
+}
+
+// addIPs validates and appends ip4 or ip6 mechanisms
+func (b *SPFBuilder) addIPs(mechanism string, prefixes []string, v4 bool) *SPFBuilder {
+	for _, value := range prefixes {
+		value = strings.TrimSpace(value)
+		var addr netip.Addr
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			addr = prefix.Addr()
+			value = prefix.Masked().String()
+		} else if a, err := netip.ParseAddr(value); err == nil {
+			addr = a
+		} else {
+			b.fail("invalid %s value %q", mechanism, value)
+			continue
+		}
+		if addr.Is4() != v4 || addr.Zone() != "" {
+			b.fail("invalid %s value %q", mechanism, value)
+			continue
+		}
+		b.add(mechanism+":"+value, false)
+	}
+	return b
+}
+
+// A authorizes the addresses of the given hosts, or of the domain the policy
+// is published for if none are given.
+func (b *SPFBuilder) A(hosts ...string) *SPFBuilder {
+	return b.addHosts("a", hosts)
+}
+
+// MX authorizes the mail exchangers of the given domains, or of the domain
+// the policy is published for if none are given.
+func (b *SPFBuilder) MX(domains ...string) *SPFBuilder {
+	return b.addHosts("mx", domains)
+}
+
+// addHosts appends a or mx mechanisms
+func (b *SPFBuilder) addHosts(mechanism string, hosts []string) *SPFBuilder {
+	if len(hosts) == 0 {
+		return b.add(mechanism, true)
+	}
+	for _, host := range hosts {
+		host = strings.TrimSuffix(strings.TrimSpace(host), ".")
+		if host == "" || strings.ContainsAny(host, " ;\"") {
+			b.fail("invalid %s host %q", mechanism, host)
+			continue
+		}
+		b.add(mechanism+":"+host, true)
+	}
+	return b
+}
+
+// Redirect hands evaluation over to the SPF policy of another domain. It
+// replaces the final "all" mechanism.
+func (b *SPFBuilder) Redirect(domain string) *SPFBuilder {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	if domain == "" || strings.ContainsAny(domain, " ;\"") {
+		return b.fail("invalid redirect domain %q", domain)
+	}
+	b.redirect = domain
+	return b
+}
+
+// All sets the result for senders matched by no other mechanism.
+func (b *SPFBuilder) All(qualifier SPFQualifier) *SPFBuilder {
+	switch qualifier {
+	case SPFPass, SPFFail, SPFSoftFail, SPFNeutral:
+		b.all = qualifier
+		return b
+	}
+	return b.fail("invalid qualifier %q", qualifier)
+}
+
+// Value returns the TXT text of the policy, such as
+// "v=spf1 mx include:_spf.google.com -all".
+func (b *SPFBuilder) Value() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if b.redirect != "" && b.all != "" {
+		return "", fmt.Errorf("SPF: redirect has no effect together with all")
+	}
+
+	lookups := b.lookups
+	if b.redirect != "" {
+		lookups++
+	}
+	if lookups > maxSPFLookups {
+		return "", fmt.Errorf("SPF: policy needs %d DNS lookups, at most %d are allowed", lookups, maxSPFLookups)
+	}
+
+	terms := append([]string{"v=spf1"}, b.terms...)
+	switch {
+	case b.redirect != "":
+		terms = append(terms, "redirect="+b.redirect)
+	case b.all != "":
+		terms = append(terms, string(b.all)+"all")
+	default:
+		terms = append(terms, "~all")
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// Record returns the policy as a TXT record for name ("@" for the apex).
+func (b *SPFBuilder) Record(name string, ttl time.Duration) (libdns.TXT, error) {
+	value, err := b.Value()
+	if err != nil {
+		return libdns.TXT{}, err
+	}
+	return libdns.TXT{Name: name, TTL: ttl, Text: value}, nil
+}
+
+// DMARCAction is what receivers should do with mail failing DMARC.
+type DMARCAction string
+
+const (
+	DMARCNone       DMARCAction = "none"
+	DMARCQuarantine DMARCAction = "quarantine"
+	DMARCReject     DMARCAction = "reject"
+)
+
+// DMARCPolicy describes a DMARC policy (RFC 7489), published as a TXT record
+// at _dmarc.
+type DMARCPolicy struct {
+	// Policy applies to the domain itself and is required
+	Policy DMARCAction
+
+	// SubdomainPolicy applies to subdomains; empty means the same as Policy
+	SubdomainPolicy DMARCAction
+
+	// Percent of failing mail the policy applies to; 0 leaves the
+	// default of 100
+	Percent int
+
+	// AggregateReports and FailureReports are the addresses reports are
+	// sent to. Plain email addresses get a "mailto:" scheme.
+	AggregateReports []string
+	FailureReports   []string
+
+	// StrictDKIM and StrictSPF require an exact domain match instead of
+	// an organizational one
+	StrictDKIM bool
+	StrictSPF  bool
+
+	// FailureOptions selects when failure reports are sent, e.g. "1" or
+	// "d:s"; empty leaves the default
+	FailureOptions string
+
+	// ReportInterval between aggregate reports; 0 leaves the default of
+	// one day
+	ReportInterval time.Duration
+}
+
+// Value returns the TXT text of the policy, such as
+// "v=DMARC1; p=reject; rua=mailto:dmarc@example.com".
+func (d DMARCPolicy) Value() (string, error) {
+	if !validDMARCAction(d.Policy) {
+		return "", fmt.Errorf("DMARC: invalid policy %q", d.Policy)
+	}
+	if d.SubdomainPolicy != "" && !validDMARCAction(d.SubdomainPolicy) {
+		return "", fmt.Errorf("DMARC: invalid subdomain policy %q", d.SubdomainPolicy)
+	}
+	if d.Percent < 0 || d.Percent > 100 {
+		return "", fmt.Errorf("DMARC: percent %d out of range 0-100", d.Percent)
+	}
+	if d.ReportInterval < 0 {
+		return "", fmt.Errorf("DMARC: negative report interval")
+	}
+
+	tags := []string{"v=DMARC1", "p=" + string(d.Policy)}
+	if d.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+string(d.SubdomainPolicy))
+	}
+	if d.Percent > 0 && d.Percent < 100 {
+		tags = append(tags, "pct="+strconv.Itoa(d.Percent))
+	}
+	for _, reports := range []struct {
+		tag       string
+		addresses []string
+	}{{"rua", d.AggregateReports}, {"ruf", d.FailureReports}} {
+		if len(reports.addresses) == 0 {
+			continue
+		}
+		uris, err := dmarcURIs(reports.addresses)
+		if err != nil {
+			return "", err
+		}
+		tags = append(tags, reports.tag+"="+uris)
+	}
+	if d.StrictDKIM {
+		tags = append(tags, "adkim=s")
+	}
+	if d.StrictSPF {
+		tags = append(tags, "aspf=s")
+	}
+	if d.FailureOptions != "" {
+		if strings.ContainsAny(d.FailureOptions, " ;=") {
+			return "", fmt.Errorf("DMARC: invalid failure options %q", d.FailureOptions)
+		}
+		tags = append(tags, "fo="+d.FailureOptions)
+	}
+	if d.ReportInterval > 0 {
+		tags = append(tags, "ri="+strconv.Itoa(int(d.ReportInterval/time.Second)))
+	}
+	return strings.Join(tags, "; "), nil
+}
+
+// Record returns the policy as a TXT record at _dmarc below name ("@" for
+// the apex).
+func (d DMARCPolicy) Record(name string, ttl time.Duration) (libdns.TXT, error) {
+	value, err := d.Value()
+	if err != nil {
+		return libdns.TXT{}, err
+	}
+	return libdns.TXT{Name: prefixLabel("_dmarc", name), TTL: ttl, Text: value}, nil
+}
+
+// validDMARCAction reports whether a is a known DMARC policy
+func validDMARCAction(a DMARCAction) bool {
+	return a == DMARCNone || a == DMARCQuarantine || a == DMARCReject
+}
+
+// dmarcURIs formats report addresses as a comma-separated URI list
+func dmarcURIs(addresses []string) (string, error) {
+	uris := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if !strings.Contains(address, ":") {
+			address = "mailto:" + address
+		}
+		if strings.HasPrefix(address, "mailto:") && !strings.Contains(address, "@") || strings.ContainsAny(address, " ;,") {
+			return "", fmt.Errorf("DMARC: invalid report address %q", address)
+		}
+		uris = append(uris, address)
+	}
+	return strings.Join(uris, ","), nil
+}
+
+// DKIMRecord describes a DKIM public key (RFC 6376), published as a TXT
+// record at <selector>._domainkey.
+type DKIMRecord struct {
+	// Selector chosen by the signing mail server, e.g. "mail"
+	Selector string
+
+	// KeyType is "rsa" or "ed25519"; empty means "rsa"
+	KeyType string
+
+	// PublicKey is the base64-encoded key, with or without PEM armor.
+	// Whitespace and line breaks are removed.
+	PublicKey string
+
+	// Testing marks the domain as testing DKIM (t=y)
+	Testing bool
+}
+
+// Value returns the TXT text of the key, such as "v=DKIM1; k=rsa; p=MIIB...".
+// Keys longer than 255 characters are split into several character-strings
+// when the record is created.
+func (d DKIMRecord) Value() (string, error) {
+	keyType := strings.ToLower(d.KeyType)
+	if keyType == "" {
+		keyType = "rsa"
+	}
+	if keyType != "rsa" && keyType != "ed25519" {
+		return "", fmt.Errorf("DKIM: unsupported key type %q", d.KeyType)
+	}
+
+	key := d.PublicKey
+	if block, _ := pem.Decode([]byte(key)); block != nil {
+		key = base64.StdEncoding.EncodeToString(block.Bytes)
+	}
+	key = strings.Join(strings.Fields(key), "")
+	if key == "" {
+		return "", fmt.Errorf("DKIM: public key is required")
+	}
+	if _, err := base64.StdEncoding.DecodeString(key); err != nil {
+		return "", fmt.Errorf("DKIM: public key is not valid base64: %w", err)
+	}
+
+	tags := []string{"v=DKIM1", "k=" + keyType}
+	if d.Testing {
+		tags = append(tags, "t=y")
+	}
+	tags = append(tags, "p="+key)
+	return strings.Join(tags, "; "), nil
+}
+
+// Record returns the key as a TXT record at <selector>._domainkey below name
+// ("@" for the apex).
+func (d DKIMRecord) Record(name string, ttl time.Duration) (libdns.TXT, error) {
+	if d.Selector == "" || strings.ContainsAny(d.Selector, " ;\"") || strings.HasPrefix(d.Selector, ".") || strings.HasSuffix(d.Selector, ".") {
+		return libdns.TXT{}, fmt.Errorf("DKIM: invalid selector %q", d.Selector)
+	}
+	value, err := d.Value()
+	if err != nil {
+		return libdns.TXT{}, err
+	}
+	return libdns.TXT{Name: prefixLabel(d.Selector+"._domainkey", name), TTL: ttl, Text: value}, nil
+}
+
+// prefixLabel returns name with label prepended
+func prefixLabel(label, name string) string {
+	if name == "" || name == "@" {
+		return label
+	}
+	return label + "." + name
+}
+
+// mailAuthTag returns the lowercased version tag ("v=spf1", "v=dmarc1" or
+// "v=dkim1") a TXT text starts with, or "" for other texts
+func mailAuthTag(text string) string {
+	text = strings.TrimSpace(text)
+	if end := strings.IndexAny(text, "; \t"); end >= 0 {
+		text = text[:end]
+	}
+	switch tag := strings.ToLower(text); tag {
+	case "v=spf1", "v=dmarc1", "v=dkim1":
+		return tag
+	}
+	return ""
+}
+
+// mailRRsetKey is rrsetKey with SPF, DMARC and DKIM TXT records kept apart
+// from other TXT records at the same name, so that each can be replaced
+// without touching the rest
+func mailRRsetKey(zone string, record libdns.Record) string {
+	key := rrsetKey(zone, record)
+	if rr := record.RR(); rr.Type == "TXT" {
+		if tag := mailAuthTag(rr.Data); tag != "" {
+			key += "|" + tag
+		}
+	}
+	return key
+}
+
+// SetMailAuthRecords publishes SPF, DMARC and DKIM TXT records, as built by
+// SPFBuilder, DMARCPolicy and DKIMRecord. Each record replaces the TXT
+// records of the same kind at its name, while other TXT records there, such
+// as site verification tokens, are kept. Records that are already up to
+// date are left alone, so applying the same policies again changes nothing.
+func (p *Provider) SetMailAuthRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	return p.withSubzone(ctx, zone, records, p.setMailAuthRecords)
+}
+
+func (p *Provider) setMailAuthRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	if !p.hasCredentials() {
+		return nil, fmt.Errorf("API token is required")
+	}
+
+	var keys []string
+	desiredSets := make(map[string][]libdns.Record)
+	for _, record := range records {
+		if rr := record.RR(); rr.Type != "TXT" || mailAuthTag(rr.Data) == "" {
+			return nil, fmt.Errorf("record %s %s is not an SPF, DMARC or DKIM record", rr.Name, rr.Type)
+		}
+		key := mailRRsetKey(zone, record)
+		if _, seen := desiredSets[key]; !seen {
+			keys = append(keys, key)
+		}
+		desiredSets[key] = append(desiredSets[key], record)
+	}
+
+	if err := p.validateRecords(records); err != nil {
+		return nil, err
+	}
+
+	existingRecords, err := p.getRecords(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
+	}
+
+	existingSets := make(map[string][]namesileoRecord)
+	for _, rec := range existingRecords {
+		nsRec, ok := rec.(namesileoRecord)
+		if !ok || rec.RR().Type != "TXT" || mailAuthTag(rec.RR().Data) == "" {
+			continue
+		}
+		key := mailRRsetKey(zone, rec)
+		existingSets[key] = append(existingSets[key], nsRec)
+	}
+
+	var resultRecords []libdns.Record
+	var changes []SyncChange
+	for _, key := range keys {
+		setChanges, unchanged := p.diffRRset(zone, existingSets[key], desiredSets[key], true)
+		resultRecords = append(resultRecords, unchanged...)
+		changes = append(changes, setChanges...)
+	}
+
+	applied, err := p.applyAll(ctx, zone, changes)
+	if err != nil {
+		return nil, err
+	}
+	return append(resultRecords, applied...), nil
+}
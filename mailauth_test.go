@@ -0,0 +1,183 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestSPFBuilder(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder *SPFBuilder
+		want    string
+		wantErr bool
+	}{
+		{"default soft fail", NewSPF().MX(), "v=spf1 mx ~all", false},
+		{
+			"mechanisms in order",
+			NewSPF().IP4("192.0.2.10", "198.51.100.7/24").IP6("2001:db8::/32").A().Include("_spf.google.com").All(SPFFail),
+			"v=spf1 ip4:192.0.2.10 ip4:198.51.100.0/24 ip6:2001:db8::/32 a include:_spf.google.com -all",
+			false,
+		},
+		{"duplicates dropped", NewSPF().Include("a.example", "a.example.").MX().MX(), "v=spf1 include:a.example mx ~all", false},
+		{"hosts", NewSPF().A("mail.example.com").MX("example.net"), "v=spf1 a:mail.example.com mx:example.net ~all", false},
+		{"redirect", NewSPF().Redirect("_spf.example.com"), "v=spf1 redirect=_spf.example.com", false},
+		{"redirect with all", NewSPF().Redirect("_spf.example.com").All(SPFFail), "", true},
+		{"IPv6 as ip4", NewSPF().IP4("2001:db8::1"), "", true},
+		{"invalid address", NewSPF().IP4("192.0.2"), "", true},
+		{"invalid qualifier", NewSPF().All("!"), "", true},
+		{"invalid include", NewSPF().Include("bad domain"), "", true},
+		{
+			"too many lookups",
+			NewSPF().Include("1.example", "2.example", "3.example", "4.example", "5.example", "6.example", "7.example", "8.example", "9.example", "10.example", "11.example"),
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.builder.Value()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Value() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Value() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDMARCPolicy(t *testing.T) {
+	policy := DMARCPolicy{
+		Policy:           DMARCQuarantine,
+		SubdomainPolicy:  DMARCReject,
+		Percent:          50,
+		AggregateReports: []string{"dmarc@example.com", "https://reports.example.net/dmarc"},
+		FailureReports:   []string{"mailto:forensic@example.com"},
+		StrictDKIM:       true,
+		FailureOptions:   "1",
+		ReportInterval:   time.Hour,
+	}
+	record, err := policy.Record("mail", time.Hour)
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	want := "v=DMARC1; p=quarantine; sp=reject; pct=50; rua=mailto:dmarc@example.com,https://reports.example.net/dmarc; ruf=mailto:forensic@example.com; adkim=s; fo=1; ri=3600"
+	if record.Name != "_dmarc.mail" || record.Text != want {
+		t.Errorf("Unexpected record %s %q", record.Name, record.Text)
+	}
+
+	if record, _ := (DMARCPolicy{Policy: DMARCNone, Percent: 100}).Record("@", 0); record.Name != "_dmarc" || record.Text != "v=DMARC1; p=none" {
+		t.Errorf("Unexpected apex record %s %q", record.Name, record.Text)
+	}
+
+	for _, invalid := range []DMARCPolicy{
+		{},
+		{Policy: "deny"},
+		{Policy: DMARCReject, Percent: 101},
+		{Policy: DMARCReject, AggregateReports: []string{"not-an-address"}},
+	} {
+		if _, err := invalid.Value(); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestDKIMRecord(t *testing.T) {
+	key := strings.Repeat("QUJD", 100)
+	pemKey := "-----BEGIN PUBLIC KEY-----\n" + key[:64] + "\n" + key[64:] + "\n-----END PUBLIC KEY-----\n"
+
+	for _, publicKey := range []string{key, key[:200] + "\n " + key[200:], pemKey} {
+		record, err := DKIMRecord{Selector: "s1", PublicKey: publicKey}.Record("@", time.Hour)
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		if record.Name != "s1._domainkey" || record.Text != "v=DKIM1; k=rsa; p="+key {
+			t.Errorf("Unexpected record %s %q", record.Name, record.Text)
+		}
+	}
+
+	record, err := DKIMRecord{Selector: "ed", KeyType: "ed25519", PublicKey: "MCowBQYDK2VwAyEA", Testing: true}.Record("news", 0)
+	if err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if record.Name != "ed._domainkey.news" || record.Text != "v=DKIM1; k=ed25519; t=y; p=MCowBQYDK2VwAyEA" {
+		t.Errorf("Unexpected record %s %q", record.Name, record.Text)
+	}
+
+	for _, invalid := range []DKIMRecord{
+		{Selector: "s1"},
+		{Selector: "s1", PublicKey: "not base64!"},
+		{Selector: "s1", KeyType: "dsa", PublicKey: key},
+		{PublicKey: key},
+	} {
+		if _, err := invalid.Record("@", 0); err == nil {
+			t.Errorf("Expected an error for %+v", invalid)
+		}
+	}
+}
+
+func TestSetMailAuthRecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "TXT", Host: "example.com", Value: "google-site-verification=abc", TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "example.com", Value: "v=spf1 mx -all", TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	spf, err := NewSPF().MX().Include("_spf.google.com").All(SPFFail).Record("@", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dmarc, err := DMARCPolicy{Policy: DMARCReject}.Record("@", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records := []libdns.Record{spf, dmarc}
+
+	if _, err := provider.SetMailAuthRecords(ctx, "example.com", records); err != nil {
+		t.Fatalf("SetMailAuthRecords failed: %v", err)
+	}
+
+	var texts []string
+	for _, rec := range srv.Records("example.com") {
+		texts = append(texts, rec.Host+" "+rec.Value)
+	}
+	got := strings.Join(texts, "\n")
+	for _, want := range []string{
+		"google-site-verification=abc",
+		"v=spf1 mx include:_spf.google.com -all",
+		"_dmarc.example.com v=DMARC1; p=reject",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q among records:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "v=spf1 mx -all") || len(texts) != 3 {
+		t.Errorf("Expected the old SPF policy to be replaced, got:\n%s", got)
+	}
+
+	// Applying the same policies again changes nothing
+	before := srv.Records("example.com")
+	if _, err := provider.SetMailAuthRecords(ctx, "example.com", records); err != nil {
+		t.Fatalf("Second SetMailAuthRecords failed: %v", err)
+	}
+	after := srv.Records("example.com")
+	for i := range before {
+		if before[i].ID != after[i].ID {
+			t.Errorf("Expected no changes, record %d went from %+v to %+v", i, before[i], after[i])
+		}
+	}
+
+	if _, err := provider.SetMailAuthRecords(ctx, "example.com", []libdns.Record{libdns.TXT{Name: "@", Text: "hello"}}); err == nil {
+		t.Error("Expected an error for a TXT record that is not a mail policy")
+	}
+}
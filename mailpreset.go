@@ -0,0 +1,215 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// MailPreset produces the records a hosted mail service needs for a domain.
+type MailPreset interface {
+	// Records returns the records for domain, named relative to it
+	Records(domain string) ([]libdns.Record, error)
+}
+
+// GoogleWorkspace is the MailPreset for Google Workspace (Gmail).
+type GoogleWorkspace struct {
+	// DKIMKey is the public key generated in the Admin console; without
+	// it no DKIM record is published
+	DKIMKey string
+
+	// DKIMSelector defaults to "google"
+	DKIMSelector string
+
+	// TTL of the records; 0 uses the provider default
+	TTL time.Duration
+}
+
+// Records implements MailPreset.
+func (g GoogleWorkspace) Records(domain string) ([]libdns.Record, error) {
+	spf, err := NewSPF().Include("_spf.google.com").Record("@", g.TTL)
+	if err != nil {
+		return nil, err
+	}
+	records := []libdns.Record{
+		libdns.MX{Name: "@", TTL: g.TTL, Preference: 1, Target: "smtp.google.com"},
+		spf,
+	}
+
+	if g.DKIMKey != "" {
+		selector := g.DKIMSelector
+		if selector == "" {
+			selector = "google"
+		}
+		dkim, err := DKIMRecord{Selector: selector, PublicKey: g.DKIMKey}.Record("@", g.TTL)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, dkim)
+	}
+
+	return records, nil
+}
+
+// Microsoft365 is the MailPreset for Microsoft 365 (Exchange Online).
+type Microsoft365 struct {
+	// Tenant is the initial domain of the organization, such as "contoso"
+	// or "contoso.onmicrosoft.com"; without it no DKIM records are
+	// published
+	Tenant string
+
+	// TTL of the records; 0 uses the provider default
+	TTL time.Duration
+}
+
+// Records implements MailPreset.
+func (m Microsoft365) Records(domain string) ([]libdns.Record, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if domain == "" {
+		return nil, fmt.Errorf("Microsoft 365: domain is required")
+	}
+	// Exchange Online names hosts after the domain with dots replaced
+	label := strings.ReplaceAll(domain, ".", "-")
+
+	spf, err := NewSPF().Include("spf.protection.outlook.com").All(SPFFail).Record("@", m.TTL)
+	if err != nil {
+		return nil, err
+	}
+	records := []libdns.Record{
+		libdns.MX{Name: "@", TTL: m.TTL, Preference: 0, Target: label + ".mail.protection.outlook.com"},
+		spf,
+		libdns.CNAME{Name: "autodiscover", TTL: m.TTL, Target: "autodiscover.outlook.com"},
+	}
+
+	if tenant := strings.TrimSuffix(strings.ToLower(m.Tenant), "."); tenant != "" {
+		if !strings.HasSuffix(tenant, ".onmicrosoft.com") {
+			tenant += ".onmicrosoft.com"
+		}
+		for _, selector := range []string{"selector1", "selector2"} {
+			records = append(records, libdns.CNAME{
+				Name:   selector + "._domainkey",
+				TTL:    m.TTL,
+				Target: selector + "-" + label + "._domainkey." + tenant,
+			})
+		}
+	}
+
+	return records, nil
+}
+
+// Fastmail is the MailPreset for Fastmail.
+type Fastmail struct {
+	// TTL of the records; 0 uses the provider default
+	TTL time.Duration
+}
+
+// Records implements MailPreset.
+func (f Fastmail) Records(domain string) ([]libdns.Record, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if domain == "" {
+		return nil, fmt.Errorf("Fastmail: domain is required")
+	}
+
+	spf, err := NewSPF().Include("spf.messagingengine.com").All(SPFNeutral).Record("@", f.TTL)
+	if err != nil {
+		return nil, err
+	}
+	records := []libdns.Record{
+		libdns.MX{Name: "@", TTL: f.TTL, Preference: 10, Target: "in1-smtp.messagingengine.com"},
+		libdns.MX{Name: "@", TTL: f.TTL, Preference: 20, Target: "in2-smtp.messagingengine.com"},
+		spf,
+	}
+
+	for _, selector := range []string{"fm1", "fm2", "fm3"} {
+		records = append(records, libdns.CNAME{
+			Name:   selector + "._domainkey",
+			TTL:    f.TTL,
+			Target: selector + "." + domain + ".dkim.fmhosted.com",
+		})
+	}
+
+	// Client autoconfiguration (RFC 6186, RFC 6764, RFC 8620)
+	for _, srv := range []struct {
+		service string
+		port    uint16
+		target  string
+	}{
+		{"submission", 587, "smtp.fastmail.com"},
+		{"imaps", 993, "imap.fastmail.com"},
+		{"jmap", 443, "api.fastmail.com"},
+		{"caldavs", 443, "caldav.fastmail.com"},
+		{"carddavs", 443, "carddav.fastmail.com"},
+	} {
+		records = append(records, libdns.SRV{
+			Service:   srv.service,
+			Transport: "tcp",
+			Name:      "@",
+			TTL:       f.TTL,
+			Priority:  0,
+			Weight:    1,
+			Port:      srv.port,
+			Target:    srv.target,
+		})
+	}
+
+	return records, nil
+}
+
+// ApplyMailPreset reconciles the records of a mail preset with the zone the
+// way SyncZone does, limited to the RRsets the preset covers: missing
+// records are created, differing ones updated, and other records in those
+// RRsets (such as the MX records of a previous mail host) deleted unless
+// NoDelete is set. SPF records replace only the existing SPF policy, leaving
+// other TXT records alone. Applying the same preset again changes nothing.
+//
+// It returns the plan that was applied (or, with DryRun, that would be).
+func (p *Provider) ApplyMailPreset(ctx context.Context, zone string, preset MailPreset, opts SyncOptions) (*SyncPlan, error) {
+	desired, err := preset.Records(strings.TrimSuffix(zone, "."))
+	if err != nil {
+		return nil, err
+	}
+
+	existingRecords, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
+	}
+
+	var keys []string
+	desiredSets := make(map[string][]libdns.Record)
+	for _, record := range desired {
+		if !opts.manages(record.RR().Type) {
+			continue
+		}
+		key := mailRRsetKey(zone, record)
+		if _, seen := desiredSets[key]; !seen {
+			keys = append(keys, key)
+		}
+		desiredSets[key] = append(desiredSets[key], record)
+	}
+
+	existingSets := make(map[string][]namesileoRecord)
+	for _, rec := range existingRecords {
+		nsRec, ok := rec.(namesileoRecord)
+		if !ok || isManagedByNameSilo(zone, rec.RR()) {
+			continue
+		}
+		key := mailRRsetKey(zone, rec)
+		if _, covered := desiredSets[key]; covered {
+			existingSets[key] = append(existingSets[key], nsRec)
+		}
+	}
+
+	plan := &SyncPlan{Zone: zone}
+	for _, key := range keys {
+		changes, _ := p.diffRRset(zone, existingSets[key], desiredSets[key], !opts.NoDelete)
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+	return p.ApplySyncPlan(ctx, plan)
+}
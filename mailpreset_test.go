@@ -0,0 +1,154 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestMailPresetRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		preset MailPreset
+		want   []string
+	}{
+		{
+			"Google Workspace",
+			GoogleWorkspace{DKIMKey: "MIIBIjAN"},
+			[]string{
+				"@ MX 1 smtp.google.com",
+				"@ TXT v=spf1 include:_spf.google.com ~all",
+				"google._domainkey TXT v=DKIM1; k=rsa; p=MIIBIjAN",
+			},
+		},
+		{
+			"Microsoft 365",
+			Microsoft365{Tenant: "contoso"},
+			[]string{
+				"@ MX 0 example-co-uk.mail.protection.outlook.com",
+				"@ TXT v=spf1 include:spf.protection.outlook.com -all",
+				"autodiscover CNAME autodiscover.outlook.com",
+				"selector1._domainkey CNAME selector1-example-co-uk._domainkey.contoso.onmicrosoft.com",
+				"selector2._domainkey CNAME selector2-example-co-uk._domainkey.contoso.onmicrosoft.com",
+			},
+		},
+		{
+			"Fastmail",
+			Fastmail{},
+			[]string{
+				"@ MX 10 in1-smtp.messagingengine.com",
+				"@ MX 20 in2-smtp.messagingengine.com",
+				"@ TXT v=spf1 include:spf.messagingengine.com ?all",
+				"fm1._domainkey CNAME fm1.example.co.uk.dkim.fmhosted.com",
+				"fm2._domainkey CNAME fm2.example.co.uk.dkim.fmhosted.com",
+				"fm3._domainkey CNAME fm3.example.co.uk.dkim.fmhosted.com",
+				"_submission._tcp SRV 0 1 587 smtp.fastmail.com",
+				"_imaps._tcp SRV 0 1 993 imap.fastmail.com",
+				"_jmap._tcp SRV 0 1 443 api.fastmail.com",
+				"_caldavs._tcp SRV 0 1 443 caldav.fastmail.com",
+				"_carddavs._tcp SRV 0 1 443 carddav.fastmail.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := tt.preset.Records("example.co.uk.")
+			if err != nil {
+				t.Fatalf("Records failed: %v", err)
+			}
+			var got []string
+			for _, record := range records {
+				rr := record.RR()
+				got = append(got, rr.Name+" "+rr.Type+" "+rr.Data)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Unexpected records:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	if records, _ := (GoogleWorkspace{}).Records("example.com"); len(records) != 2 {
+		t.Errorf("Expected no DKIM record without a key, got %v", records)
+	}
+	if records, _ := (Microsoft365{}).Records("example.com"); len(records) != 3 {
+		t.Errorf("Expected no DKIM records without a tenant, got %v", records)
+	}
+}
+
+func TestApplyMailPreset(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "MX", Host: "example.com", Value: "mx1.oldhost.example", Distance: 10, TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "example.com", Value: "mx2.oldhost.example", Distance: 20, TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "example.com", Value: "v=spf1 include:oldhost.example ~all", TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "example.com", Value: "google-site-verification=abc", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "www.example.com", Value: "192.0.2.1", TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+	preset := GoogleWorkspace{TTL: time.Hour}
+
+	plan, err := provider.ApplyMailPreset(ctx, "example.com.", preset, SyncOptions{})
+	if err != nil {
+		t.Fatalf("ApplyMailPreset failed: %v", err)
+	}
+	// The old MX and SPF records are replaced, one MX is deleted
+	if len(plan.Changes) != 3 {
+		t.Errorf("Expected 3 changes, got %+v", plan.Changes)
+	}
+
+	var got []string
+	for _, rec := range srv.Records("example.com") {
+		got = append(got, rec.Type+" "+rec.Host+" "+rec.Value)
+	}
+	want := []string{
+		"A www.example.com 192.0.2.1",
+		"MX example.com smtp.google.com",
+		"TXT example.com google-site-verification=abc",
+		"TXT example.com v=spf1 include:_spf.google.com ~all",
+	}
+	for _, w := range want {
+		if !strings.Contains(strings.Join(got, "\n"), w) {
+			t.Errorf("Expected %q among records:\n%s", w, strings.Join(got, "\n"))
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d records, got:\n%s", len(want), strings.Join(got, "\n"))
+	}
+
+	plan, err = provider.ApplyMailPreset(ctx, "example.com.", preset, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Second ApplyMailPreset failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected re-applying the preset to change nothing, got %+v", plan.Changes)
+	}
+}
+
+func TestApplyMailPresetNoDelete(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "MX", Host: "example.com", Value: "mx1.oldhost.example", Distance: 10, TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "example.com", Value: "mx2.oldhost.example", Distance: 20, TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+
+	plan, err := provider.ApplyMailPreset(context.Background(), "example.com.", Fastmail{}, SyncOptions{NoDelete: true, Types: []string{"MX"}})
+	if err != nil {
+		t.Fatalf("ApplyMailPreset failed: %v", err)
+	}
+	for _, change := range plan.Changes {
+		if change.Action == SyncDelete || change.Record.RR().Type != "MX" {
+			t.Errorf("Unexpected change %+v", change)
+		}
+	}
+	if n := len(srv.Records("example.com")); n != 2 {
+		t.Errorf("Expected the MX records to be updated in place, got %d records", n)
+	}
+}
@@ -0,0 +1,194 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SaleType is the way a domain is offered on the NameSilo marketplace.
+type SaleType string
+
+// Sale types supported by the NameSilo marketplace.
+const (
+	SaleBuyNow  SaleType = "buy_now" // Fixed price
+	SaleOffer   SaleType = "offer"   // Buyers make offers, optionally with a buy-now price
+	SaleAuction SaleType = "auction" // Auction with a reserve price
+)
+
+// Sale is an active marketplace listing.
+type Sale struct {
+	Domain         string
+	Status         string
+	Type           SaleType
+	Reserve        float64
+	BuyNow         float64
+	Portfolio      string
+	EndDate        time.Time // Zero for listings without an end date
+	AutoExtendDays int
+	PrivateSaleURL string
+	ActiveBid      bool
+}
+
+// saleRecord represents a marketplace listing from NameSilo API
+type saleRecord struct {
+	Domain         string  `xml:"domain"`
+	Status         string  `xml:"status"`
+	Type           string  `xml:"sale_type"`
+	Reserve        float64 `xml:"reserve"`
+	BuyNow         float64 `xml:"buy_now"`
+	Portfolio      string  `xml:"portfolio"`
+	EndDate        string  `xml:"end_date"`
+	AutoExtendDays int     `xml:"auto_extend_days"`
+	PrivateSaleURL string  `xml:"private_sale_url"`
+	ActiveBid      string  `xml:"active_bid_or_offer"`
+}
+
+// salesOverviewResponse represents the response from
+// marketplaceActiveSalesOverview
+type salesOverviewResponse struct {
+	apiResponse
+	Sales []saleRecord `xml:"reply>sale_details"`
+}
+
+// MarketplaceSales lists the account's active marketplace listings.
+func (p *Provider) MarketplaceSales(ctx context.Context) ([]Sale, error) {
+	var response salesOverviewResponse
+	if err := p.callAPI(ctx, "marketplaceActiveSalesOverview", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	sales := make([]Sale, 0, len(response.Sales))
+	for _, rec := range response.Sales {
+		endDate, _ := parseAPITime(rec.EndDate)
+		sales = append(sales, Sale{
+			Domain:         rec.Domain,
+			Status:         rec.Status,
+			Type:           SaleType(rec.Type),
+			Reserve:        rec.Reserve,
+			BuyNow:         rec.BuyNow,
+			Portfolio:      rec.Portfolio,
+			EndDate:        endDate,
+			AutoExtendDays: rec.AutoExtendDays,
+			PrivateSaleURL: rec.PrivateSaleURL,
+			ActiveBid:      yesNo(rec.ActiveBid),
+		})
+	}
+
+	return sales, nil
+}
+
+// SaleListing describes a marketplace listing to create or modify.
+type SaleListing struct {
+	Type         SaleType
+	Reserve      float64 // Minimum price for auctions
+	BuyNow       float64 // Fixed price; optional for offers
+	EndDate      time.Time
+	Description  string
+	NotifyBuyers bool
+}
+
+// params validates the listing and returns its parameters
+func (l SaleListing) params() (map[string]string, error) {
+	params := map[string]string{
+		"sale_type": string(l.Type),
+	}
+
+	switch l.Type {
+	case SaleBuyNow:
+		if l.BuyNow <= 0 {
+			return nil, fmt.Errorf("buy-now sales require a BuyNow price")
+		}
+	case SaleAuction:
+		if l.Reserve <= 0 {
+			return nil, fmt.Errorf("auctions require a Reserve price")
+		}
+		params["reserve"] = formatPrice(l.Reserve)
+	case SaleOffer:
+	default:
+		return nil, fmt.Errorf("unsupported sale type %q", l.Type)
+	}
+
+	if l.BuyNow > 0 {
+		params["buy_now"] = formatPrice(l.BuyNow)
+	}
+	if !l.EndDate.IsZero() {
+		params["end_date"] = l.EndDate.Format("2006-01-02")
+	}
+	params["description"] = l.Description
+	params["notify_buyers"] = boolParam(l.NotifyBuyers)
+
+	return params, nil
+}
+
+// ListForSale lists the domain for sale on the NameSilo marketplace.
+func (p *Provider) ListForSale(ctx context.Context, domain string, listing SaleListing) error {
+	return p.marketplaceSale(ctx, "add", domain, listing)
+}
+
+// ModifySale changes an existing marketplace listing.
+func (p *Provider) ModifySale(ctx context.Context, domain string, listing SaleListing) error {
+	return p.marketplaceSale(ctx, "modify", domain, listing)
+}
+
+// EndSale removes the domain from the marketplace.
+func (p *Provider) EndSale(ctx context.Context, domain string) error {
+	params := map[string]string{
+		"domain": strings.TrimSuffix(domain, "."),
+		"action": "end",
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "marketplaceAddOrModifySale", params, &response)
+}
+
+// marketplaceSale adds or modifies a marketplace listing
+func (p *Provider) marketplaceSale(ctx context.Context, action, domain string, listing SaleListing) error {
+	params, err := listing.params()
+	if err != nil {
+		return err
+	}
+	params["domain"] = strings.TrimSuffix(domain, ".")
+	params["action"] = action
+
+	var response apiResponse
+	return p.callAPI(ctx, "marketplaceAddOrModifySale", params, &response)
+}
+
+// LandingPage configures the marketplace landing page shown for a domain
+// listed for sale. Colors are hex values without the leading '#'.
+type LandingPage struct {
+	Template         string
+	BackgroundColor  string
+	TextColor        string
+	Message          string
+	ShowBuyNow       bool
+	ShowMoreInfo     bool
+	ShowRenewalPrice bool
+	ShowOtherForSale bool
+}
+
+// UpdateLandingPage updates the marketplace landing page of a domain.
+func (p *Provider) UpdateLandingPage(ctx context.Context, domain string, page LandingPage) error {
+	params := map[string]string{
+		"domain":                 strings.TrimSuffix(domain, "."),
+		"mp_template":            page.Template,
+		"mp_bgcolor":             strings.TrimPrefix(page.BackgroundColor, "#"),
+		"mp_textcolor":           strings.TrimPrefix(page.TextColor, "#"),
+		"mp_message":             page.Message,
+		"mp_show_buy_now":        boolParam(page.ShowBuyNow),
+		"mp_show_more_info":      boolParam(page.ShowMoreInfo),
+		"mp_show_renewal_price":  boolParam(page.ShowRenewalPrice),
+		"mp_show_other_for_sale": boolParam(page.ShowOtherForSale),
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "marketplaceLandingPageUpdate", params, &response)
+}
+
+// formatPrice formats a USD amount for the API
+func formatPrice(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
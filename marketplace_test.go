@@ -0,0 +1,75 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestMarketplace(t *testing.T) {
+	var query url.Values
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		switch r.URL.Path {
+		case "/api/marketplaceActiveSalesOverview":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<sale_details><domain>example.com</domain><status>Active</status><sale_type>auction</sale_type><reserve>500.00</reserve><buy_now>1500.00</buy_now><end_date>2024-06-30</end_date><auto_extend_days>3</auto_extend_days><active_bid_or_offer>Yes</active_bid_or_offer></sale_details>
+</reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+	ctx := context.Background()
+
+	sales, err := provider.MarketplaceSales(ctx)
+	if err != nil {
+		t.Fatalf("MarketplaceSales failed: %v", err)
+	}
+	if len(sales) != 1 {
+		t.Fatalf("Expected 1 sale, got %d", len(sales))
+	}
+	sale := sales[0]
+	if sale.Type != SaleAuction || sale.Reserve != 500 || sale.BuyNow != 1500 || !sale.ActiveBid || sale.AutoExtendDays != 3 {
+		t.Errorf("Unexpected sale: %+v", sale)
+	}
+	if !sale.EndDate.Equal(time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected end date %v", sale.EndDate)
+	}
+
+	err = provider.ListForSale(ctx, "example.net.", SaleListing{Type: SaleBuyNow, BuyNow: 999.5})
+	if err != nil {
+		t.Fatalf("ListForSale failed: %v", err)
+	}
+	if query.Get("action") != "add" || query.Get("domain") != "example.net" || query.Get("buy_now") != "999.50" || query.Get("sale_type") != "buy_now" {
+		t.Errorf("Unexpected query %v", query)
+	}
+
+	if err := provider.EndSale(ctx, "example.net"); err != nil {
+		t.Fatalf("EndSale failed: %v", err)
+	}
+	if query.Get("action") != "end" {
+		t.Errorf("Unexpected action %q", query.Get("action"))
+	}
+
+	if err := provider.UpdateLandingPage(ctx, "example.net", LandingPage{BackgroundColor: "#ffffff", ShowBuyNow: true}); err != nil {
+		t.Fatalf("UpdateLandingPage failed: %v", err)
+	}
+	if query.Get("mp_bgcolor") != "ffffff" || query.Get("mp_show_buy_now") != "1" {
+		t.Errorf("Unexpected query %v", query)
+	}
+}
+
+func TestSaleListingValidation(t *testing.T) {
+	invalid := []SaleListing{
+		{Type: SaleBuyNow},
+		{Type: SaleAuction, BuyNow: 100},
+		{Type: "lease"},
+	}
+	for _, listing := range invalid {
+		if _, err := listing.params(); err == nil {
+			t.Errorf("Expected error for %+v", listing)
+		}
+	}
+}
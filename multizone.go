@@ -0,0 +1,61 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/libdns/libdns"
+)
+
+const defaultZoneConcurrency = 4
+
+// zoneConcurrency returns how many zones GetAllRecords fetches at once
+func (p *Provider) zoneConcurrency() int {
+	if p.ZoneConcurrency > 0 {
+		return p.ZoneConcurrency
+	}
+	return defaultZoneConcurrency
+}
+
+// GetAllRecords lists the records of several zones in parallel, fetching
+// at most ZoneConcurrency zones at a time. Requests still share the
+// Provider's rate limiter. It returns the records by zone, as given in
+// zones; zones that failed are missing from the map and their errors are
+// joined in the returned error.
+func (p *Provider) GetAllRecords(ctx context.Context, zones []string) (map[string][]libdns.Record, error) {
+	results := make(map[string][]libdns.Record, len(zones))
+	errs := make([]error, len(zones))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, p.zoneConcurrency())
+
+	for i, zone := range zones {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = fmt.Errorf("%s: %w", zone, ctx.Err())
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, zone string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			records, err := p.GetRecords(ctx, zone)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", zone, err)
+				return
+			}
+			mu.Lock()
+			results[zone] = records
+			mu.Unlock()
+		}(i, zone)
+	}
+	wg.Wait()
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,55 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetAllRecords(t *testing.T) {
+	var mu sync.Mutex
+	active, peak := 0, 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		active++
+		if active > peak {
+			peak = active
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		domain := r.URL.Query().Get("domain")
+		if domain == "missing.com" {
+			w.Write([]byte(`<namesilo><reply><code>200</code><detail>Domain is not active, or does not belong to this user</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>www.` + domain + `</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+	})
+	provider.ZoneConcurrency = 2
+
+	zones := []string{"a.com", "b.com", "c.com", "missing.com", "d.com."}
+	results, err := provider.GetAllRecords(context.Background(), zones)
+	if !errors.Is(err, ErrDomainNotInAccount) {
+		t.Errorf("Expected the failed zone's error, got %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("Expected records of 4 zones, got %v", results)
+	}
+	if recs := results["d.com."]; len(recs) != 1 || recs[0].RR().Name != "www" {
+		t.Errorf("Expected zones keyed as given, got %v", results)
+	}
+	if _, ok := results["missing.com"]; ok {
+		t.Error("Expected the failed zone to be missing from the results")
+	}
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", peak)
+	}
+}
@@ -0,0 +1,207 @@
+// Package namesilodns converts between the records of a namesilo.Provider
+// and the resource records of github.com/miekg/dns, so that GetRecords
+// output can be fed to DNS servers, validators or zone-signing tools, and
+// records built with miekg/dns can be published through the Provider:
+//
+//	records, err := provider.GetRecords(ctx, "example.com")
+//	...
+//	rrs, err := namesilodns.ToRRs("example.com", records)
+//
+// Owner names are fully qualified in dns.RR form and relative to the zone in
+// libdns form, with "@" for the apex. Host names in record data, such as
+// CNAME and MX targets, are fully qualified in dns.RR form and have no
+// trailing dot in libdns form, as NameSilo lists them.
+//
+// The package is a separate module so that the Provider does not pull in
+// miekg/dns, or its Go version requirement, for users who do not need it.
+package namesilodns
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/r6c/namesilo"
+)
+
+// maxTXTStringLength is the maximum length of a TXT character-string
+const maxTXTStringLength = 255
+
+// ToRR converts a record of zone to a dns.RR. NameSilo ALIAS records have no
+// DNS type and cannot be converted.
+func ToRR(zone string, record libdns.Record) (dns.RR, error) {
+	if namesilo.IsAlias(record) {
+		return nil, fmt.Errorf("ALIAS record %s has no DNS type", record.RR().Name)
+	}
+
+	rr := record.RR()
+	parsed, err := rr.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := dns.RR_Header{
+		Name:   dns.Fqdn(libdns.AbsoluteName(rr.Name, zone)),
+		Rrtype: dns.StringToType[strings.ToUpper(rr.Type)],
+		Class:  dns.ClassINET,
+		Ttl:    uint32(rr.TTL / time.Second),
+	}
+
+	switch r := parsed.(type) {
+	case libdns.Address:
+		if r.IP.Is4() {
+			return &dns.A{Hdr: hdr, A: r.IP.AsSlice()}, nil
+		}
+		return &dns.AAAA{Hdr: hdr, AAAA: r.IP.AsSlice()}, nil
+	case libdns.CNAME:
+		return &dns.CNAME{Hdr: hdr, Target: dns.Fqdn(r.Target)}, nil
+	case libdns.NS:
+		return &dns.NS{Hdr: hdr, Ns: dns.Fqdn(r.Target)}, nil
+	case libdns.MX:
+		return &dns.MX{Hdr: hdr, Preference: r.Preference, Mx: dns.Fqdn(r.Target)}, nil
+	case libdns.SRV:
+		return &dns.SRV{Hdr: hdr, Priority: r.Priority, Weight: r.Weight, Port: r.Port, Target: dns.Fqdn(r.Target)}, nil
+	case libdns.TXT:
+		return &dns.TXT{Hdr: hdr, Txt: splitTXT(r.Text)}, nil
+	}
+
+	// Other types, such as CAA and TLSA, are read from their master file
+	// form
+	text := fmt.Sprintf("%s %d IN %s %s", hdr.Name, hdr.Ttl, rr.Type, rr.Data)
+	converted, err := dns.NewRR(text)
+	if err != nil {
+		return nil, fmt.Errorf("%s record %s: %w", rr.Type, rr.Name, err)
+	}
+	if converted == nil {
+		return nil, fmt.Errorf("%s record %s has no data", rr.Type, rr.Name)
+	}
+	return converted, nil
+}
+
+// ToRRs converts the records of zone, e.g. from GetRecords, to dns.RRs. It
+// fails for the first record that cannot be converted.
+func ToRRs(zone string, records []libdns.Record) ([]dns.RR, error) {
+	rrs := make([]dns.RR, 0, len(records))
+	for _, record := range records {
+		rr, err := ToRR(zone, record)
+		if err != nil {
+			return nil, err
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs, nil
+}
+
+// FromRR converts a dns.RR of zone to a record that a namesilo.Provider
+// accepts: a typed libdns record where libdns has one, a namesilo.TLSA for
+// TLSA records, and a libdns.RR otherwise. Records of types unknown to
+// miekg/dns keep their RFC 3597 data.
+func FromRR(zone string, rr dns.RR) (libdns.Record, error) {
+	hdr := rr.Header()
+	name := libdns.RelativeName(hdr.Name, dns.Fqdn(zone))
+	ttl := time.Duration(hdr.Ttl) * time.Second
+
+	switch r := rr.(type) {
+	case *dns.A:
+		ip, ok := netip.AddrFromSlice(r.A.To4())
+		if !ok {
+			return nil, fmt.Errorf("A record %s has no IPv4 address", hdr.Name)
+		}
+		return libdns.Address{Name: name, TTL: ttl, IP: ip}, nil
+	case *dns.AAAA:
+		ip, ok := netip.AddrFromSlice(r.AAAA.To16())
+		if !ok {
+			return nil, fmt.Errorf("AAAA record %s has no IPv6 address", hdr.Name)
+		}
+		return libdns.Address{Name: name, TTL: ttl, IP: ip}, nil
+	case *dns.CNAME:
+		return libdns.CNAME{Name: name, TTL: ttl, Target: hostName(r.Target)}, nil
+	case *dns.NS:
+		return libdns.NS{Name: name, TTL: ttl, Target: hostName(r.Ns)}, nil
+	case *dns.MX:
+		return libdns.MX{Name: name, TTL: ttl, Preference: r.Preference, Target: hostName(r.Mx)}, nil
+	case *dns.SRV:
+		data := fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, hostName(r.Target))
+		return libdns.RR{Name: name, TTL: ttl, Type: "SRV", Data: data}.Parse()
+	case *dns.TXT:
+		return libdns.TXT{Name: name, TTL: ttl, Text: strings.Join(r.Txt, "")}, nil
+	case *dns.TLSA:
+		return namesilo.TLSA{
+			Name:         name,
+			TTL:          ttl,
+			Usage:        r.Usage,
+			Selector:     r.Selector,
+			MatchingType: r.MatchingType,
+			CertData:     r.Certificate,
+		}, nil
+	case *dns.RFC3597:
+		// Generic data of a type miekg/dns knows is converted to that type
+		if typed, err := dns.NewRR(r.String()); err == nil {
+			if _, generic := typed.(*dns.RFC3597); !generic {
+				return FromRR(zone, typed)
+			}
+		}
+		data := fmt.Sprintf(`\# %d %s`, len(r.Rdata)/2, r.Rdata)
+		return libdns.RR{Name: name, TTL: ttl, Type: dns.Type(hdr.Rrtype).String(), Data: data}, nil
+	}
+
+	return libdns.RR{Name: name, TTL: ttl, Type: dns.Type(hdr.Rrtype).String(), Data: rdata(rr)}.Parse()
+}
+
+// ParseRR parses a resource record of zone in master file form, such as
+// "www 3600 IN A 192.0.2.1" or its RFC 3597 form "www 3600 IN A \# 4
+// C0000201", and converts it like FromRR. Relative names are taken to be
+// relative to zone.
+func ParseRR(zone, text string) (libdns.Record, error) {
+	zp := dns.NewZoneParser(strings.NewReader(text), dns.Fqdn(zone), "")
+	rr, ok := zp.Next()
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no resource record in %q", text)
+	}
+	return FromRR(zone, rr)
+}
+
+// RFC3597 returns a record of zone in the generic master file form of RFC
+// 3597, e.g. "www.example.com. 3600 CLASS1 TYPE1 \# 4 c0000201", which
+// tools that do not know the record type can still read and sign.
+func RFC3597(zone string, record libdns.Record) (string, error) {
+	rr, err := ToRR(zone, record)
+	if err != nil {
+		return "", err
+	}
+	var generic dns.RFC3597
+	if err := generic.ToRFC3597(rr); err != nil {
+		return "", err
+	}
+	return generic.String(), nil
+}
+
+// splitTXT splits text into character-strings of at most 255 bytes
+func splitTXT(text string) []string {
+	var parts []string
+	for len(text) > maxTXTStringLength {
+		parts = append(parts, text[:maxTXTStringLength])
+		text = text[maxTXTStringLength:]
+	}
+	return append(parts, text)
+}
+
+// hostName returns a host name of record data the way NameSilo lists it,
+// without a trailing dot
+func hostName(name string) string {
+	if name == "." {
+		return name
+	}
+	return strings.TrimSuffix(name, ".")
+}
+
+// rdata returns the data of rr in master file form
+func rdata(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
@@ -0,0 +1,157 @@
+package namesilodns
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/miekg/dns"
+	"github.com/r6c/namesilo"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+const testHash = "2bb6b2bdf6ea03f6e4b9fa36f8b7a2e1a5fdd1b4b1f3b3d0d5d2d7c6a4e1f0b9"
+
+func TestRoundTrip(t *testing.T) {
+	records := []libdns.Record{
+		libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")},
+		libdns.Address{Name: "@", TTL: time.Hour, IP: netip.MustParseAddr("2001:db8::1")},
+		libdns.CNAME{Name: "shop", TTL: time.Hour, Target: "shops.example.net"},
+		libdns.NS{Name: "sub", TTL: time.Hour, Target: "ns1.example.net"},
+		libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mail.example.com"},
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", TTL: time.Hour, Priority: 1, Weight: 5, Port: 5060, Target: "sip.example.com"},
+		libdns.TXT{Name: "@", TTL: time.Hour, Text: `v=spf1 include:"x" -all`},
+		libdns.TXT{Name: "long", TTL: time.Hour, Text: strings.Repeat("a", 300)},
+		libdns.CAA{Name: "@", TTL: time.Hour, Tag: "issue", Value: "letsencrypt.org"},
+		namesilo.TLSA{Name: "_443._tcp.www", TTL: time.Hour, Usage: 3, Selector: 1, MatchingType: 1, CertData: testHash},
+	}
+
+	for _, record := range records {
+		rr, err := ToRR("example.com", record)
+		if err != nil {
+			t.Fatalf("ToRR(%+v) failed: %v", record, err)
+		}
+		back, err := FromRR("example.com.", rr)
+		if err != nil {
+			t.Fatalf("FromRR(%s) failed: %v", rr, err)
+		}
+		if !reflect.DeepEqual(back, record) {
+			t.Errorf("Round trip through %s:\n got %#v\nwant %#v", rr, back, record)
+		}
+	}
+}
+
+func TestToRR(t *testing.T) {
+	tests := []struct {
+		record libdns.Record
+		want   string
+	}{
+		{libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour}, "www.example.com.\t3600\tIN\tA\t192.0.2.1"},
+		{libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com", TTL: time.Hour}, "example.com.\t3600\tIN\tMX\t10 mail.example.com."},
+		{libdns.TXT{Name: "@", Text: "hello world", TTL: 5 * time.Minute}, "example.com.\t300\tIN\tTXT\t\"hello world\""},
+		{libdns.RR{Name: "_443._tcp", Type: "TLSA", Data: "3 1 1 " + testHash, TTL: time.Hour}, "_443._tcp.example.com.\t3600\tIN\tTLSA\t3 1 1 " + testHash},
+	}
+	for _, tt := range tests {
+		rr, err := ToRR("example.com", tt.record)
+		if err != nil {
+			t.Fatalf("ToRR(%+v) failed: %v", tt.record, err)
+		}
+		if rr.String() != tt.want {
+			t.Errorf("ToRR(%+v) = %q, want %q", tt.record, rr.String(), tt.want)
+		}
+	}
+
+	if _, err := ToRR("example.com", namesilo.NewAlias("@", "example.net", time.Hour)); err == nil {
+		t.Error("Expected an error for an ALIAS record")
+	}
+	if _, err := ToRR("example.com", libdns.RR{Name: "x", Type: "CAA", Data: "bogus", TTL: time.Hour}); err == nil {
+		t.Error("Expected an error for bad record data")
+	}
+}
+
+func TestRFC3597(t *testing.T) {
+	text, err := RFC3597("example.com", libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("RFC3597 failed: %v", err)
+	}
+	if want := "www.example.com.\t3600\tCLASS1\tTYPE1\t\\# 4 c0000201"; text != want {
+		t.Errorf("RFC3597 = %q, want %q", text, want)
+	}
+
+	// The generic form parses back to the typed record
+	record, err := ParseRR("example.com", text)
+	if err != nil {
+		t.Fatalf("ParseRR failed: %v", err)
+	}
+	if want := (libdns.Address{Name: "www", TTL: time.Hour, IP: netip.MustParseAddr("192.0.2.1")}); record != want {
+		t.Errorf("ParseRR = %#v, want %#v", record, want)
+	}
+
+	// Unknown types keep their generic data
+	record, err = ParseRR("example.com", `x 60 IN TYPE65400 \# 2 abcd`)
+	if err != nil {
+		t.Fatalf("ParseRR failed: %v", err)
+	}
+	if want := (libdns.RR{Name: "x", TTL: time.Minute, Type: "TYPE65400", Data: `\# 2 abcd`}); record != want {
+		t.Errorf("ParseRR = %#v, want %#v", record, want)
+	}
+	var generic dns.RFC3597
+	generic.Hdr = dns.RR_Header{Name: "mail.example.com.", Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: 60}
+	generic.Rdata = "c0000202"
+	if record, err := FromRR("example.com", &generic); err != nil || record.RR().Data != "192.0.2.2" {
+		t.Errorf("Expected generic data of a known type to be converted, got %v, %v", record, err)
+	}
+}
+
+func TestParseRR(t *testing.T) {
+	record, err := ParseRR("example.com", "www 3600 IN CNAME target")
+	if err != nil {
+		t.Fatalf("ParseRR failed: %v", err)
+	}
+	if want := (libdns.CNAME{Name: "www", TTL: time.Hour, Target: "target.example.com"}); record != want {
+		t.Errorf("ParseRR = %#v, want %#v", record, want)
+	}
+
+	for _, text := range []string{"", "www 3600 IN A not-an-address"} {
+		if _, err := ParseRR("example.com", text); err == nil {
+			t.Errorf("Expected an error for %q", text)
+		}
+	}
+}
+
+func TestGetRecordsToRRs(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "test-token"
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "@", Value: "mail.example.com", TTL: 3600, Distance: 10},
+		namesilotest.Record{Type: "TXT", Host: "@", Value: "v=spf1 -all", TTL: 3600},
+	)
+	provider := &namesilo.Provider{APIToken: "test-token", HTTPClient: srv.Client()}
+
+	records, err := provider.GetRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	rrs, err := ToRRs("example.com", records)
+	if err != nil {
+		t.Fatalf("ToRRs failed: %v", err)
+	}
+	var got []string
+	for _, rr := range rrs {
+		got = append(got, rr.String())
+	}
+	want := []string{
+		"www.example.com.\t3600\tIN\tA\t192.0.2.1",
+		"example.com.\t3600\tIN\tMX\t10 mail.example.com.",
+		"example.com.\t3600\tIN\tTXT\t\"v=spf1 -all\"",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ToRRs = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+module github.com/r6c/namesilo/namesilodns
+
+go 1.24.0
+
+require (
+	github.com/libdns/libdns v1.1.0
+	github.com/miekg/dns v1.1.72
+	github.com/r6c/namesilo v0.0.0-00010101000000-000000000000
+)
+
+require (
+	golang.org/x/mod v0.31.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/tools v0.40.0 // indirect
+)
+
+replace github.com/r6c/namesilo => ../
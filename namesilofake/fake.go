@@ -0,0 +1,431 @@
+// Package namesilofake provides an in-memory implementation of the libdns
+// record interfaces that applies NameSilo's rules, for unit tests of code
+// that manages DNS records through a namesilo.Provider:
+//
+//	fake := &namesilofake.Provider{}
+//	fake.AddZone("example.com")
+//
+//	// code under test, taking a libdns.RecordSetter
+//	err := publishRecords(ctx, fake, "example.com")
+//
+// Like NameSilo, the fake replaces TTLs below the minimum, keeps a distance
+// only for MX and SRV records, rejects duplicate records and CNAMEs next to
+// other records, and fails for zones that are not in the account. Records
+// it returns carry record IDs, readable with namesilo.RecordID. Unlike
+// namesilotest, no HTTP server is involved.
+package namesilofake
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo"
+)
+
+// Reply codes of the errors returned by the fake, matching the NameSilo API
+const (
+	codeDomainNotActive = 200
+	codeDNSModification = 280
+)
+
+// supportedTypes are the record types NameSilo accepts
+var supportedTypes = map[string]bool{
+	"A": true, "AAAA": true, "ALIAS": true, "CAA": true, "CNAME": true,
+	"MX": true, "NS": true, "SRV": true, "TLSA": true, "TXT": true,
+}
+
+// Provider is an in-memory fake of a namesilo.Provider. The zero value is
+// an account without zones; it is safe for concurrent use.
+type Provider struct {
+	// MinTTL, DefaultTTL, TTLPolicy, TTLSnap and AcceptedTTLs apply as
+	// for namesilo.Provider
+	MinTTL       time.Duration
+	DefaultTTL   time.Duration
+	TTLPolicy    namesilo.TTLPolicy
+	TTLSnap      namesilo.TTLSnap
+	AcceptedTTLs []time.Duration
+
+	mu     sync.Mutex
+	zones  map[string][]namesilo.Record
+	nextID int
+}
+
+// AddZone adds a zone to the fake account with the given records, which
+// are checked like records passed to AppendRecords.
+func (p *Provider) AddZone(zone string, records ...libdns.Record) error {
+	p.mu.Lock()
+	if p.zones == nil {
+		p.zones = make(map[string][]namesilo.Record)
+	}
+	if _, ok := p.zones[zoneKey(zone)]; !ok {
+		p.zones[zoneKey(zone)] = []namesilo.Record{}
+	}
+	p.mu.Unlock()
+
+	_, err := p.AppendRecords(context.Background(), zone, records)
+	return err
+}
+
+// Records returns a copy of the records of a zone as NameSilo stores them,
+// sorted by host, type and value. It returns nil if the zone is not in the
+// account.
+func (p *Provider) Records(zone string) []namesilo.Record {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, ok := p.zones[zoneKey(zone)]
+	if !ok {
+		return nil
+	}
+	records := append([]namesilo.Record{}, stored...)
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+	return records
+}
+
+// GetRecords implements libdns.RecordGetter.
+func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, err := p.zone("dnsListRecords", zone)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]libdns.Record, 0, len(stored))
+	for _, rec := range stored {
+		records = append(records, toLibdns(zone, rec))
+	}
+	return records, nil
+}
+
+// AppendRecords implements libdns.RecordAppender. Records are checked
+// before any is added; a record that NameSilo would refuse, such as a
+// duplicate, fails with a *namesilo.APIError after the records before it
+// were added.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	converted, err := p.convertAll(zone, records)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, err := p.zone("dnsAddRecord", zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var added []libdns.Record
+	for _, rec := range converted {
+		if err := p.checkConflicts(zone, stored, rec); err != nil {
+			p.zones[zoneKey(zone)] = stored
+			return added, err
+		}
+		rec.ID = p.newID()
+		stored = append(stored, rec)
+		added = append(added, toLibdns(zone, rec))
+	}
+	p.zones[zoneKey(zone)] = stored
+	return added, nil
+}
+
+// SetRecords implements libdns.RecordSetter: the RRsets (name and type) of
+// the given records are replaced by them, leaving other RRsets alone.
+// Records that stay the same keep their ID; changed records get a new one,
+// as with NameSilo. The change is all or nothing.
+func (p *Provider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	converted, err := p.convertAll(zone, records)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, err := p.zone("dnsUpdateRecord", zone)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced := make(map[string]bool)
+	for _, rec := range converted {
+		replaced[rrsetKey(rec)] = true
+	}
+	var kept, previous []namesilo.Record
+	for _, rec := range stored {
+		if replaced[rrsetKey(rec)] {
+			previous = append(previous, rec)
+		} else {
+			kept = append(kept, rec)
+		}
+	}
+
+	var results []libdns.Record
+	for _, rec := range converted {
+		if i := indexOfSameData(previous, rec); i >= 0 {
+			if previous[i].TTL == rec.TTL {
+				rec.ID = previous[i].ID
+			}
+			previous = append(previous[:i:i], previous[i+1:]...)
+		}
+		if err := p.checkConflicts(zone, kept, rec); err != nil {
+			return nil, err
+		}
+		if rec.ID == "" {
+			rec.ID = p.newID()
+		}
+		kept = append(kept, rec)
+		results = append(results, toLibdns(zone, rec))
+	}
+
+	p.zones[zoneKey(zone)] = kept
+	return results, nil
+}
+
+// DeleteRecords implements libdns.RecordDeleter. A record with a NameSilo
+// ID deletes that record; otherwise records are matched by name, and by
+// type and value where given. Records not in the zone are ignored.
+func (p *Provider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stored, err := p.zone("dnsDeleteRecord", zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []libdns.Record
+	for _, record := range records {
+		id, hasID := namesilo.RecordID(record)
+		want := namesilo.RecordFromLibdns(zone, record)
+		rr := record.RR()
+
+		for i := 0; i < len(stored); i++ {
+			rec := stored[i]
+			if hasID {
+				if rec.ID != id {
+					continue
+				}
+			} else if !strings.EqualFold(rec.Host, want.Host) ||
+				rr.Type != "" && rec.Type != want.Type ||
+				rr.Data != "" && !sameData(rec, want) {
+				continue
+			}
+
+			deleted = append(deleted, toLibdns(zone, rec))
+			stored = append(stored[:i:i], stored[i+1:]...)
+			i--
+			if hasID || rr.Type != "" && rr.Data != "" {
+				// A fully specified record deletes a single match
+				break
+			}
+		}
+	}
+
+	p.zones[zoneKey(zone)] = stored
+	return deleted, nil
+}
+
+// zone returns the records of a zone, or the error NameSilo replies with
+// for a domain that is not in the account. The caller must hold p.mu.
+func (p *Provider) zone(operation, zone string) ([]namesilo.Record, error) {
+	stored, ok := p.zones[zoneKey(zone)]
+	if !ok {
+		return nil, &namesilo.APIError{
+			Operation: operation,
+			Domain:    zoneKey(zone),
+			Code:      codeDomainNotActive,
+			Detail:    "Domain is not active, or does not belong to this user",
+		}
+	}
+	return append([]namesilo.Record(nil), stored...), nil
+}
+
+// newID returns a new record ID. The caller must hold p.mu.
+func (p *Provider) newID() string {
+	p.nextID++
+	return fmt.Sprintf("%032x", p.nextID)
+}
+
+// convertAll checks records and converts them to the form NameSilo stores
+// them in, before any change is made
+func (p *Provider) convertAll(zone string, records []libdns.Record) ([]namesilo.Record, error) {
+	converted := make([]namesilo.Record, 0, len(records))
+	for _, record := range records {
+		rec, err := p.convert(zone, record)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, rec)
+	}
+	return converted, nil
+}
+
+// convert checks a record and returns it as NameSilo stores it
+func (p *Provider) convert(zone string, record libdns.Record) (namesilo.Record, error) {
+	rr := record.RR()
+	rec := namesilo.RecordFromLibdns(zone, record)
+	rec.ID = ""
+
+	if !supportedTypes[rec.Type] {
+		return rec, fmt.Errorf("%s record %q: unsupported record type", rec.Type, rr.Name)
+	}
+	if strings.Contains(strings.TrimPrefix(rec.Host, "*."), "*") && rec.Host != "*" {
+		return rec, fmt.Errorf("%s record %q: wildcard must be the leftmost label", rec.Type, rr.Name)
+	}
+	if _, err := rec.ToLibdns(zone); err != nil {
+		return rec, err
+	}
+
+	switch rec.Type {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(rec.Value)
+		if err != nil || addr.Is4() != (rec.Type == "A") {
+			return rec, fmt.Errorf("%s record %q: invalid address %q", rec.Type, rr.Name, rec.Value)
+		}
+	case "CNAME", "MX", "NS", "ALIAS":
+		if rec.Value == "" {
+			return rec, fmt.Errorf("%s record %q: target is required", rec.Type, rr.Name)
+		}
+	case "TXT":
+		if rec.Value == "" {
+			return rec, fmt.Errorf("TXT record %q: text is required", rr.Name)
+		}
+	}
+	if rec.Type == "CNAME" && rec.Host == "@" {
+		return rec, &namesilo.ApexCNAMEError{Zone: zoneKey(zone), Target: rec.Value}
+	}
+
+	// NameSilo stores a distance for MX and SRV records only
+	if rec.Type != "MX" && rec.Type != "SRV" {
+		rec.Distance = 0
+	}
+
+	// Low TTLs are rejected or replaced according to TTLPolicy, and other
+	// TTLs according to TTLSnap
+	if p.TTLPolicy == namesilo.TTLError && rr.TTL > 0 && rr.TTL < p.minTTL() {
+		return rec, fmt.Errorf("%s record %q: TTL %s is below the minimum of %s", rec.Type, rr.Name, rr.TTL, p.minTTL())
+	}
+	ttl := p.rules().NormalizeRecord(zone, record).TTL
+	if p.TTLSnap == namesilo.TTLSnapError && rr.TTL > 0 {
+		unsnapped := p.rules()
+		unsnapped.TTLSnap = namesilo.TTLSnapOff
+		if ttl != unsnapped.NormalizeRecord(zone, record).TTL {
+			return rec, fmt.Errorf("%s record %q: TTL %s is not accepted by NameSilo, the nearest accepted TTL is %s", rec.Type, rr.Name, rr.TTL, ttl)
+		}
+	}
+	rec.TTL = int(ttl / time.Second)
+
+	return rec, nil
+}
+
+// checkConflicts returns the error NameSilo replies with when rec cannot be
+// added next to the records of the zone
+func (p *Provider) checkConflicts(zone string, stored []namesilo.Record, rec namesilo.Record) error {
+	for _, existing := range stored {
+		if !strings.EqualFold(existing.Host, rec.Host) {
+			continue
+		}
+		var detail string
+		switch {
+		case existing.Type == rec.Type && sameData(existing, rec):
+			detail = "The record already exists"
+		case existing.Type == "CNAME" || rec.Type == "CNAME":
+			detail = "A CNAME record cannot share its host with other records"
+		default:
+			continue
+		}
+		return &namesilo.APIError{
+			Operation: "dnsAddRecord",
+			Domain:    zoneKey(zone),
+			Code:      codeDNSModification,
+			Detail:    detail,
+			Params:    map[string]string{"rrhost": rec.Host, "rrtype": rec.Type, "rrvalue": rec.Value},
+		}
+	}
+	return nil
+}
+
+// rules returns a namesilo.Provider with the TTL settings of the fake, used
+// to normalize records the way the real client does
+func (p *Provider) rules() *namesilo.Provider {
+	return &namesilo.Provider{
+		MinTTL:       p.MinTTL,
+		DefaultTTL:   p.DefaultTTL,
+		TTLPolicy:    p.TTLPolicy,
+		TTLSnap:      p.TTLSnap,
+		AcceptedTTLs: p.AcceptedTTLs,
+	}
+}
+
+// minTTL returns the minimum TTL, defaulting like namesilo.Provider
+func (p *Provider) minTTL() time.Duration {
+	if p.MinTTL > 0 {
+		return p.MinTTL
+	}
+	return 5 * time.Minute
+}
+
+// toLibdns converts a stored record, which is always well formed
+func toLibdns(zone string, rec namesilo.Record) libdns.Record {
+	record, _ := rec.ToLibdns(zone)
+	return record
+}
+
+// rrsetKey identifies the RRset of a record
+func rrsetKey(rec namesilo.Record) string {
+	return strings.ToLower(rec.Host) + ":" + rec.Type
+}
+
+// sameData reports whether two records of the same type have the same
+// value and distance, ignoring the case of host names
+func sameData(a, b namesilo.Record) bool {
+	if a.Distance != b.Distance {
+		return false
+	}
+	switch a.Type {
+	case "CNAME", "MX", "NS", "ALIAS", "SRV":
+		return strings.EqualFold(strings.TrimSuffix(a.Value, "."), strings.TrimSuffix(b.Value, "."))
+	}
+	return a.Value == b.Value
+}
+
+// indexOfSameData returns the index of the record in records with the same
+// host, type and data as rec, or -1
+func indexOfSameData(records []namesilo.Record, rec namesilo.Record) int {
+	for i, existing := range records {
+		if strings.EqualFold(existing.Host, rec.Host) && existing.Type == rec.Type && sameData(existing, rec) {
+			return i
+		}
+	}
+	return -1
+}
+
+// zoneKey returns the account key of a zone
+func zoneKey(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, "."))
+}
+
+// Interface guards
+var (
+	_ libdns.RecordGetter   = (*Provider)(nil)
+	_ libdns.RecordAppender = (*Provider)(nil)
+	_ libdns.RecordSetter   = (*Provider)(nil)
+	_ libdns.RecordDeleter  = (*Provider)(nil)
+)
@@ -0,0 +1,195 @@
+package namesilofake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo"
+)
+
+func TestProvider(t *testing.T) {
+	fake := &Provider{}
+	if err := fake.AddZone("example.com.",
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+	); err != nil {
+		t.Fatalf("AddZone failed: %v", err)
+	}
+	ctx := context.Background()
+
+	added, err := fake.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com.", TTL: time.Hour},
+		libdns.TXT{Name: "@", Text: "v=spf1 mx -all", TTL: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if len(added) != 2 {
+		t.Fatalf("Expected 2 added records, got %v", added)
+	}
+	if _, ok := added[0].(libdns.MX); ok {
+		t.Error("Expected added records to carry an ID")
+	}
+	if id, ok := namesilo.RecordID(added[0]); !ok || id == "" {
+		t.Error("Expected a record ID")
+	}
+
+	want := []namesilo.Record{
+		{Host: "@", Type: "MX", Value: "mail.example.com.", TTL: 3600, Distance: 10},
+		{Host: "@", Type: "TXT", Value: "v=spf1 mx -all", TTL: 3600}, // below the minimum
+		{Host: "www", Type: "A", Value: "192.0.2.1", TTL: 3600},
+	}
+	got := fake.Records("example.com")
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d records, got %+v", len(want), got)
+	}
+	for i := range want {
+		got[i].ID = ""
+		if got[i] != want[i] {
+			t.Errorf("Record %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	records, err := fake.GetRecords(ctx, "example.com")
+	if err != nil || len(records) != 3 {
+		t.Fatalf("GetRecords = %v, %v", records, err)
+	}
+
+	deleted, err := fake.DeleteRecords(ctx, "example.com", []libdns.Record{added[0], libdns.RR{Name: "www"}})
+	if err != nil || len(deleted) != 2 {
+		t.Fatalf("DeleteRecords = %v, %v", deleted, err)
+	}
+	if got := fake.Records("example.com"); len(got) != 1 || got[0].Type != "TXT" {
+		t.Errorf("Expected only the TXT record to remain, got %+v", got)
+	}
+}
+
+func TestProviderSetRecords(t *testing.T) {
+	fake := &Provider{}
+	fake.AddZone("example.com",
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "AAAA", Data: "2001:db8::1", TTL: time.Hour},
+	)
+	before := fake.Records("example.com")
+
+	results, err := fake.SetRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.3", TTL: time.Hour},
+	})
+	if err != nil || len(results) != 2 {
+		t.Fatalf("SetRecords = %v, %v", results, err)
+	}
+
+	after := fake.Records("example.com")
+	if len(after) != 3 {
+		t.Fatalf("Expected 3 records, got %+v", after)
+	}
+	if after[0].Value != "192.0.2.1" || after[0].ID != before[0].ID {
+		t.Errorf("Expected the unchanged record to keep its ID, got %+v", after[0])
+	}
+	if after[1].Value != "192.0.2.3" || after[2].Type != "AAAA" {
+		t.Errorf("Unexpected records %+v", after)
+	}
+
+	// A conflict leaves the zone unchanged
+	_, err = fake.SetRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.RR{Name: "mail", Type: "A", Data: "192.0.2.9"},
+		libdns.CNAME{Name: "www", Target: "other.example.net"},
+	})
+	if err == nil {
+		t.Fatal("Expected a CNAME conflict")
+	}
+	if got := fake.Records("example.com"); len(got) != 3 {
+		t.Errorf("Expected no changes, got %+v", got)
+	}
+}
+
+func TestProviderRules(t *testing.T) {
+	fake := &Provider{}
+	fake.AddZone("example.com", libdns.TXT{Name: "dup", Text: "hello"})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		zone   string
+		record libdns.Record
+		target error // sentinel the error must match, if any
+	}{
+		{"duplicate", "example.com", libdns.TXT{Name: "dup", Text: "hello"}, namesilo.ErrRecordExists},
+		{"unknown zone", "example.net", libdns.TXT{Name: "x", Text: "hello"}, namesilo.ErrDomainNotInAccount},
+		{"CNAME next to TXT", "example.com", libdns.CNAME{Name: "dup", Target: "x.example.net"}, nil},
+		{"CNAME at apex", "example.com", libdns.CNAME{Name: "@", Target: "x.example.net"}, nil},
+		{"IPv6 in A", "example.com", libdns.RR{Name: "v6", Type: "A", Data: "2001:db8::1"}, nil},
+		{"unsupported type", "example.com", libdns.RR{Name: "x", Type: "HINFO", Data: "a b"}, nil},
+		{"wildcard in the middle", "example.com", libdns.RR{Name: "a.*.b", Type: "A", Data: "192.0.2.1"}, nil},
+		{"malformed SRV", "example.com", libdns.RR{Name: "_sip._tcp", Type: "SRV", Data: "garbage"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fake.AppendRecords(ctx, tt.zone, []libdns.Record{tt.record})
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if tt.target != nil && !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+
+	if got := fake.Records("example.com"); len(got) != 1 {
+		t.Errorf("Expected no records to be added, got %+v", got)
+	}
+
+	var apexErr *namesilo.ApexCNAMEError
+	_, err := fake.AppendRecords(ctx, "example.com", []libdns.Record{libdns.CNAME{Name: "@", Target: "x.example.net"}})
+	if !errors.As(err, &apexErr) || apexErr.Zone != "example.com" {
+		t.Errorf("Expected an ApexCNAMEError, got %v", err)
+	}
+}
+
+func TestProviderTTLAndDistance(t *testing.T) {
+	fake := &Provider{TTLPolicy: namesilo.TTLClamp}
+	fake.AddZone("example.com",
+		libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1", TTL: time.Minute},
+		libdns.RR{Name: "b", Type: "A", Data: "192.0.2.2"},
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Priority: 5, Weight: 1, Port: 5060, Target: "sip.example.com", TTL: time.Hour},
+	)
+
+	for _, rec := range fake.Records("example.com") {
+		switch rec.Host {
+		case "a":
+			if rec.TTL != 300 {
+				t.Errorf("Expected the TTL to be clamped to 300, got %d", rec.TTL)
+			}
+		case "b":
+			if rec.TTL != 3600 || rec.Distance != 0 {
+				t.Errorf("Expected the default TTL and no distance, got %+v", rec)
+			}
+		case "_sip._tcp":
+			if rec.Distance != 5 || rec.Value != "1 5060 sip.example.com" {
+				t.Errorf("Unexpected SRV record %+v", rec)
+			}
+		default:
+			t.Errorf("Unexpected record %+v", rec)
+		}
+	}
+
+	strict := &Provider{TTLPolicy: namesilo.TTLError}
+	if err := strict.AddZone("example.com", libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1", TTL: time.Minute}); err == nil {
+		t.Error("Expected TTLError to reject a low TTL")
+	}
+
+	snapping := &Provider{TTLSnap: namesilo.TTLSnapNearest}
+	snapping.AddZone("example.com", libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1", TTL: 1000 * time.Second})
+	if got := snapping.Records("example.com"); got[0].TTL != 900 {
+		t.Errorf("Expected the TTL to be snapped to 900, got %d", got[0].TTL)
+	}
+	snapping.TTLSnap = namesilo.TTLSnapError
+	if err := snapping.AddZone("example.net", libdns.RR{Name: "a", Type: "A", Data: "192.0.2.1", TTL: 1000 * time.Second}); err == nil {
+		t.Error("Expected TTLSnapError to reject a TTL that is not accepted")
+	}
+}
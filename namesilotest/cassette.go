@@ -0,0 +1,188 @@
+package namesilotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sync"
+)
+
+// Mode selects whether a Cassette records or replays API exchanges.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette file and never touches the
+	// network. Requests without a recorded match fail.
+	ModeReplay Mode = iota
+
+	// ModeRecord forwards requests to the real API and captures the exchanges;
+	// call Save to write them to the cassette file.
+	ModeRecord
+)
+
+// maskedKey replaces the API key in recorded URLs
+const maskedKey = "REDACTED"
+
+// replyIP matches the caller's IP address NameSilo echoes in every reply
+var replyIP = regexp.MustCompile(`<ip>[^<]*</ip>`)
+
+// Interaction is a recorded request and its response.
+type Interaction struct {
+	Method string `json:"method"`
+	URL    string `json:"url"` // API key masked
+	Status int    `json:"status"`
+	Body   string `json:"body"`
+}
+
+// Cassette is an http.RoundTripper that records real NameSilo API exchanges
+// to a JSON fixture file and replays them later, so that tests exercise
+// genuine response shapes without credentials or network access.
+//
+// Recorded exchanges are sanitized: the API key is masked in URLs and
+// bodies, and the client IP NameSilo echoes back is removed. Set Sanitize
+// to scrub anything else before it is saved.
+type Cassette struct {
+	Path string
+	Mode Mode
+
+	// Transport performs requests in ModeRecord. Defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Sanitize, if set, is applied to each interaction before it is stored.
+	Sanitize func(*Interaction)
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewCassette returns a cassette for the fixture file at path. In ModeReplay
+// mode the file is loaded immediately.
+func NewCassette(path string, mode Mode) (*Cassette, error) {
+	c := &Cassette{Path: path, Mode: mode}
+	if mode != ModeReplay {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading cassette: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.interactions); err != nil {
+		return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+	}
+	c.used = make([]bool, len(c.interactions))
+
+	return c, nil
+}
+
+// Client returns an HTTP client that uses the cassette as its transport.
+func (c *Cassette) Client() *http.Client {
+	return &http.Client{Transport: c}
+}
+
+// RoundTrip records or replays a single request.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	if c.Mode == ModeRecord {
+		return c.record(req)
+	}
+	return c.replay(req)
+}
+
+// Save writes the recorded interactions to the cassette file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, append(data, '\n'), 0o644)
+}
+
+// record performs the request and stores the sanitized exchange
+func (c *Cassette) record(req *http.Request) (*http.Response, error) {
+	transport := c.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	in := Interaction{
+		Method: req.Method,
+		URL:    maskURL(req.URL),
+		Status: resp.StatusCode,
+		Body:   string(body),
+	}
+	if key := req.URL.Query().Get("key"); key != "" {
+		in.Body = string(bytes.ReplaceAll(body, []byte(key), []byte(maskedKey)))
+	}
+	in.Body = replyIP.ReplaceAllString(in.Body, "<ip></ip>")
+	if c.Sanitize != nil {
+		c.Sanitize(&in)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.used = append(c.used, false)
+	c.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay returns the first unused interaction matching the request
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	masked := maskURL(req.URL)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, in := range c.interactions {
+		if c.used[i] || in.Method != req.Method || in.URL != masked {
+			continue
+		}
+		c.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			StatusCode:    in.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/xml"}},
+			Body:          io.NopCloser(bytes.NewReader([]byte(in.Body))),
+			ContentLength: int64(len(in.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("cassette %s has no unused interaction for %s %s", c.Path, req.Method, masked)
+}
+
+// maskURL returns the URL with the API key masked and query parameters in
+// canonical order
+func maskURL(u *url.URL) string {
+	masked := *u
+	q := masked.Query()
+	if q.Has("key") {
+		q.Set("key", maskedKey)
+	}
+	masked.RawQuery = q.Encode()
+	return masked.String()
+}
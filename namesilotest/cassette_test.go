@@ -0,0 +1,65 @@
+package namesilotest
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCassette(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", Record{Type: "A", Host: "www", Value: "192.0.2.1"})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	const apiURL = "https://www.namesilo.com/api/dnsListRecords?version=1&type=xml&key=secret-key&domain=example.com"
+
+	rec, err := NewCassette(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Transport = srv.Client().Transport
+	recorded := get(t, rec.Client(), apiURL)
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// The fake server is gone; replay must not need it
+	srv.Close()
+
+	play, err := NewCassette(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("NewCassette failed: %v", err)
+	}
+	if len(play.interactions) != 1 || strings.Contains(play.interactions[0].URL, "secret-key") {
+		t.Fatalf("Unexpected recorded interactions: %+v", play.interactions)
+	}
+
+	// Parameter order does not matter, and the key may differ
+	replayed := get(t, play.Client(), "https://www.namesilo.com/api/dnsListRecords?domain=example.com&key=other&type=xml&version=1")
+	if replayed != recorded || !strings.Contains(replayed, "<value>192.0.2.1</value>") {
+		t.Errorf("Replayed body differs:\n%s\n%s", replayed, recorded)
+	}
+
+	if _, err := play.Client().Get(apiURL); err == nil {
+		t.Error("Expected error once the interaction has been used")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
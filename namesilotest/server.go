@@ -0,0 +1,347 @@
+// Package namesilotest provides an in-memory fake of the NameSilo DNS API
+// for tests that should run without real credentials.
+//
+// A Server is wired into a namesilo.Provider through its HTTP client:
+//
+//	srv := namesilotest.NewServer()
+//	defer srv.Close()
+//	srv.AddZone("example.com")
+//
+//	provider := &namesilo.Provider{
+//		APIToken:   "test",
+//		HTTPClient: srv.Client(),
+//	}
+package namesilotest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultTTL is the TTL NameSilo assigns when rrttl is omitted
+const defaultTTL = 7207
+
+// Reply codes returned by the fake, matching the NameSilo API
+const (
+	codeSuccess           = 300
+	codeInvalidVersion    = 103
+	codeInvalidType       = 105
+	codeInvalidOperation  = 107
+	codeMissingParameters = 108
+	codeNoAPIKey          = 109
+	codeInvalidAPIKey     = 110
+	codeDomainNotActive   = 200
+	codeDNSModification   = 280
+)
+
+// Record is a resource record stored by the fake server. Host is the fully
+// qualified name without the trailing dot, as NameSilo reports it.
+type Record struct {
+	ID       string
+	Type     string
+	Host     string
+	Value    string
+	TTL      int
+	Distance int
+}
+
+// Server is a fake NameSilo API serving listDomains, dnsListRecords,
+// dnsAddRecord, dnsUpdateRecord and dnsDeleteRecord from memory. It is safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// APIKey, if set, is the only API key the server accepts; requests
+	// with another key get reply code 110. Any key is accepted otherwise.
+	APIKey string
+
+	mu     sync.Mutex
+	zones  map[string][]Record
+	nextID int
+}
+
+// NewServer starts a fake NameSilo API server with no zones. The caller
+// must call Close when done.
+func NewServer() *Server {
+	s := &Server{zones: make(map[string][]Record)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an HTTP client that sends requests for any host, including
+// the real NameSilo endpoints, to the fake server.
+func (s *Server) Client() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{Transport: rewriteTransport{target: target, base: s.Server.Client().Transport}}
+}
+
+// AddZone adds a domain to the fake account with the given records. Record
+// IDs are assigned if empty; hosts may be relative to the domain, "@" or
+// fully qualified.
+func (s *Server) AddZone(domain string, records ...Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domain = normalizeDomain(domain)
+	zone := s.zones[domain]
+	if zone == nil {
+		zone = []Record{}
+	}
+	for _, rec := range records {
+		if rec.ID == "" {
+			rec.ID = s.newID()
+		}
+		rec.Host = qualify(rec.Host, domain)
+		rec.Type = strings.ToUpper(rec.Type)
+		if rec.TTL == 0 {
+			rec.TTL = defaultTTL
+		}
+		zone = append(zone, rec)
+	}
+	s.zones[domain] = zone
+}
+
+// Records returns a copy of the records of a domain, sorted by host, type
+// and value. It returns nil if the domain is not in the account.
+func (s *Server) Records(domain string) []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zone, ok := s.zones[normalizeDomain(domain)]
+	if !ok {
+		return nil
+	}
+
+	records := append([]Record{}, zone...)
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+	return records
+}
+
+// newID returns a new record ID. The caller must hold s.mu.
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("%032x", s.nextID)
+}
+
+// reply is the body of every response
+type reply struct {
+	XMLName   xml.Name    `xml:"namesilo"`
+	Operation string      `xml:"request>operation"`
+	Code      int         `xml:"reply>code"`
+	Detail    string      `xml:"reply>detail"`
+	RecordID  string      `xml:"reply>record_id,omitempty"`
+	Records   []xmlRecord `xml:"reply>resource_record"`
+	Domains   []string    `xml:"reply>domains>domain,omitempty"`
+}
+
+// xmlRecord is a resource record as returned by dnsListRecords
+type xmlRecord struct {
+	ID       string `xml:"record_id"`
+	Type     string `xml:"type"`
+	Host     string `xml:"host"`
+	Value    string `xml:"value"`
+	TTL      int    `xml:"ttl"`
+	Distance int    `xml:"distance"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := reply{Operation: path.Base(r.URL.Path)}
+	s.handle(r.Form, &resp)
+	if resp.Detail == "" {
+		resp.Detail = "success"
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(resp)
+}
+
+// handle performs the operation and fills in the reply
+func (s *Server) handle(form url.Values, resp *reply) {
+	switch {
+	case form.Get("version") != "1":
+		resp.Code, resp.Detail = codeInvalidVersion, "Invalid API version"
+		return
+	case form.Get("type") != "xml":
+		resp.Code, resp.Detail = codeInvalidType, "Invalid API type"
+		return
+	case form.Get("key") == "":
+		resp.Code, resp.Detail = codeNoAPIKey, "No API key specified"
+		return
+	case s.APIKey != "" && form.Get("key") != s.APIKey:
+		resp.Code, resp.Detail = codeInvalidAPIKey, "Invalid API key"
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resp.Operation == "listDomains" {
+		for domain := range s.zones {
+			resp.Domains = append(resp.Domains, domain)
+		}
+		sort.Strings(resp.Domains)
+		resp.Code = codeSuccess
+		return
+	}
+
+	domain := normalizeDomain(form.Get("domain"))
+	if domain == "" {
+		resp.Code, resp.Detail = codeMissingParameters, "Missing domain parameter"
+		return
+	}
+	zone, ok := s.zones[domain]
+	if !ok {
+		resp.Code, resp.Detail = codeDomainNotActive, "Domain is not active, or does not belong to this user"
+		return
+	}
+
+	switch resp.Operation {
+	case "dnsListRecords":
+		for _, rec := range zone {
+			resp.Records = append(resp.Records, xmlRecord(rec))
+		}
+		resp.Code = codeSuccess
+
+	case "dnsAddRecord":
+		rec, errDetail := recordFromForm(form, domain)
+		rec.Type = strings.ToUpper(form.Get("rrtype"))
+		if errDetail == "" && rec.Type == "" {
+			errDetail = "Missing rrtype parameter"
+		}
+		if errDetail != "" {
+			resp.Code, resp.Detail = codeMissingParameters, errDetail
+			return
+		}
+		for _, existing := range zone {
+			if existing.Type == rec.Type && strings.EqualFold(existing.Host, rec.Host) && existing.Value == rec.Value {
+				resp.Code, resp.Detail = codeDNSModification, "DNS modification error: resource record already exists"
+				return
+			}
+		}
+		rec.ID = s.newID()
+		s.zones[domain] = append(zone, rec)
+		resp.Code, resp.RecordID = codeSuccess, rec.ID
+
+	case "dnsUpdateRecord":
+		i := indexOf(zone, form.Get("rrid"))
+		if i < 0 {
+			resp.Code, resp.Detail = codeDNSModification, "DNS modification error: invalid record ID"
+			return
+		}
+		rec, errDetail := recordFromForm(form, domain)
+		if errDetail != "" {
+			resp.Code, resp.Detail = codeMissingParameters, errDetail
+			return
+		}
+		// Like NameSilo, an update replaces the record under a new ID
+		rec.Type = zone[i].Type
+		rec.ID = s.newID()
+		zone[i] = rec
+		resp.Code, resp.RecordID = codeSuccess, rec.ID
+
+	case "dnsDeleteRecord":
+		i := indexOf(zone, form.Get("rrid"))
+		if i < 0 {
+			resp.Code, resp.Detail = codeDNSModification, "DNS modification error: invalid record ID"
+			return
+		}
+		s.zones[domain] = append(zone[:i:i], zone[i+1:]...)
+		resp.Code = codeSuccess
+
+	default:
+		resp.Code, resp.Detail = codeInvalidOperation, "Invalid API operation"
+	}
+}
+
+// recordFromForm reads the rrhost, rrvalue, rrttl and rrdistance parameters
+func recordFromForm(form url.Values, domain string) (Record, string) {
+	rec := Record{
+		Host:  qualify(form.Get("rrhost"), domain),
+		Value: form.Get("rrvalue"),
+		TTL:   defaultTTL,
+	}
+	if rec.Value == "" {
+		return rec, "Missing rrvalue parameter"
+	}
+	if ttl := form.Get("rrttl"); ttl != "" {
+		n, err := strconv.Atoi(ttl)
+		if err != nil {
+			return rec, "Invalid rrttl parameter"
+		}
+		rec.TTL = n
+	}
+	if distance := form.Get("rrdistance"); distance != "" {
+		n, err := strconv.Atoi(distance)
+		if err != nil {
+			return rec, "Invalid rrdistance parameter"
+		}
+		rec.Distance = n
+	}
+	return rec, ""
+}
+
+// indexOf returns the index of the record with the given ID, or -1
+func indexOf(zone []Record, id string) int {
+	for i, rec := range zone {
+		if id != "" && rec.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeDomain lowercases a domain and strips the trailing dot
+func normalizeDomain(domain string) string {
+	return strings.ToLower(strings.TrimSuffix(domain, "."))
+}
+
+// qualify converts a host relative to domain into the fully qualified form
+// NameSilo reports
+func qualify(host, domain string) string {
+	host = strings.TrimSuffix(host, ".")
+	switch {
+	case host == "" || host == "@" || strings.EqualFold(host, domain):
+		return domain
+	case strings.HasSuffix(strings.ToLower(host), "."+domain):
+		return host
+	default:
+		return host + "." + domain
+	}
+}
+
+// rewriteTransport sends every request to the fake server
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = ""
+	return t.base.RoundTrip(req)
+}
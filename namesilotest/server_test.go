@@ -0,0 +1,100 @@
+package namesilotest
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type testReply struct {
+	Code     int    `xml:"reply>code"`
+	Detail   string `xml:"reply>detail"`
+	RecordID string `xml:"reply>record_id"`
+	Records  []struct {
+		ID   string `xml:"record_id"`
+		Host string `xml:"host"`
+	} `xml:"reply>resource_record"`
+	Domains []string `xml:"reply>domains>domain"`
+}
+
+func call(t *testing.T, srv *Server, operation string, params url.Values) testReply {
+	t.Helper()
+
+	params.Set("version", "1")
+	params.Set("type", "xml")
+	if params.Get("key") == "" {
+		params.Set("key", "key")
+	}
+
+	// Use the real endpoint to check that Client redirects to the fake
+	resp, err := srv.Client().Get("https://www.namesilo.com/api/" + operation + "?" + params.Encode())
+	if err != nil {
+		t.Fatalf("%s failed: %v", operation, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s: unexpected status %d", operation, resp.StatusCode)
+	}
+
+	var reply testReply
+	if err := xml.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		t.Fatalf("%s: decoding reply: %v", operation, err)
+	}
+	return reply
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com.", Record{Type: "a", Host: "@", Value: "192.0.2.1"})
+
+	list := call(t, srv, "dnsListRecords", url.Values{"domain": {"example.com"}})
+	if list.Code != 300 || len(list.Records) != 1 || list.Records[0].Host != "example.com" {
+		t.Fatalf("Unexpected list reply: %+v", list)
+	}
+
+	add := call(t, srv, "dnsAddRecord", url.Values{"domain": {"example.com"}, "rrtype": {"TXT"}, "rrhost": {"_acme-challenge"}, "rrvalue": {"token"}})
+	if add.Code != 300 || add.RecordID == "" {
+		t.Fatalf("Unexpected add reply: %+v", add)
+	}
+
+	dup := call(t, srv, "dnsAddRecord", url.Values{"domain": {"example.com"}, "rrtype": {"TXT"}, "rrhost": {"_acme-challenge"}, "rrvalue": {"token"}})
+	if dup.Code != 280 {
+		t.Errorf("Expected code 280 for a duplicate record, got %+v", dup)
+	}
+
+	update := call(t, srv, "dnsUpdateRecord", url.Values{"domain": {"example.com"}, "rrid": {add.RecordID}, "rrhost": {"_acme-challenge"}, "rrvalue": {"new"}, "rrttl": {"300"}})
+	if update.Code != 300 || update.RecordID == add.RecordID {
+		t.Fatalf("Unexpected update reply: %+v", update)
+	}
+	records := srv.Records("example.com")
+	if len(records) != 2 || records[0].Value != "new" || records[0].TTL != 300 || records[0].Type != "TXT" {
+		t.Fatalf("Unexpected records after update: %+v", records)
+	}
+
+	if del := call(t, srv, "dnsDeleteRecord", url.Values{"domain": {"example.com"}, "rrid": {update.RecordID}}); del.Code != 300 {
+		t.Fatalf("Unexpected delete reply: %+v", del)
+	}
+	if del := call(t, srv, "dnsDeleteRecord", url.Values{"domain": {"example.com"}, "rrid": {update.RecordID}}); del.Code != 280 {
+		t.Errorf("Expected code 280 for an unknown record ID, got %+v", del)
+	}
+	if records := srv.Records("example.com"); len(records) != 1 {
+		t.Errorf("Unexpected records after delete: %+v", records)
+	}
+
+	if r := call(t, srv, "dnsListRecords", url.Values{"domain": {"example.net"}}); r.Code != 200 {
+		t.Errorf("Expected code 200 for an unknown domain, got %+v", r)
+	}
+	if r := call(t, srv, "listDomains", url.Values{}); r.Code != 300 || len(r.Domains) != 1 || r.Domains[0] != "example.com" {
+		t.Errorf("Unexpected listDomains reply: %+v", r)
+	}
+	if r := call(t, srv, "registerDomain", url.Values{"domain": {"example.com"}}); r.Code != 107 {
+		t.Errorf("Expected code 107 for an unsupported operation, got %+v", r)
+	}
+
+	srv.APIKey = "secret"
+	if r := call(t, srv, "dnsListRecords", url.Values{"domain": {"example.com"}, "key": {"wrong"}}); r.Code != 110 {
+		t.Errorf("Expected code 110 for a wrong API key, got %+v", r)
+	}
+}
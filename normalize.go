@@ -0,0 +1,71 @@
+package namesilo
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// NormalizeRecord returns record in the form NameSilo stores it for zone:
+// the name relative to the zone in lower case ("@" for the apex), the
+// NameSilo record type (e.g. "ALIAS"), the TTL after applying TTLPolicy,
+// and the data as sent to NameSilo with host names in lower case and
+// without a trailing dot. Comparing normalized records avoids spurious
+// differences between a desired state and the output of GetRecords.
+func (p *Provider) NormalizeRecord(zone string, record libdns.Record) libdns.RR {
+	params := p.recordParams(zone, record)
+	recordType := apiRecordType(record)
+
+	data := normalizeValue(recordType, params["rrvalue"])
+	if distance, ok := params["rrdistance"]; ok {
+		data = distance + " " + data
+	}
+
+	ttl, _ := strconv.Atoi(params["rrttl"])
+	return libdns.RR{
+		Name: strings.ToLower(params["rrhost"]),
+		TTL:  time.Duration(ttl) * time.Second,
+		Type: recordType,
+		Data: data,
+	}
+}
+
+// RecordsEqual reports whether a and b are the same record once normalized
+// with NormalizeRecord, including the TTL.
+func (p *Provider) RecordsEqual(zone string, a, b libdns.Record) bool {
+	return p.NormalizeRecord(zone, a) == p.NormalizeRecord(zone, b)
+}
+
+// sameData reports whether a and b have the same name, type and data once
+// normalized, regardless of their TTLs
+func (p *Provider) sameData(zone string, a, b libdns.Record) bool {
+	na, nb := p.NormalizeRecord(zone, a), p.NormalizeRecord(zone, b)
+	return na.Name == nb.Name && na.Type == nb.Type && na.Data == nb.Data
+}
+
+// normalizeValue canonicalizes the host name in the value of records that
+// point to a host
+func normalizeValue(recordType, value string) string {
+	switch recordType {
+	case "CNAME", "NS", "MX", aliasType:
+		return canonicalHost(value)
+	case "SRV":
+		// "weight port target"
+		if fields := strings.Fields(value); len(fields) == 3 {
+			fields[2] = canonicalHost(fields[2])
+			return strings.Join(fields, " ")
+		}
+	}
+	return value
+}
+
+// canonicalHost lower-cases a host name and removes its trailing dot, keeping
+// the root name "." as is
+func canonicalHost(host string) string {
+	if host == "." {
+		return host
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
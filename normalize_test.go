@@ -0,0 +1,67 @@
+package namesilo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestNormalizeRecord(t *testing.T) {
+	p := &Provider{}
+	tests := []struct {
+		record libdns.Record
+		want   libdns.RR
+	}{
+		{
+			libdns.CNAME{Name: "WWW.Example.com.", Target: "LB.Example.NET.", TTL: time.Hour},
+			libdns.RR{Name: "www", Type: "CNAME", TTL: time.Hour, Data: "lb.example.net"},
+		},
+		{
+			libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com.", TTL: time.Minute},
+			libdns.RR{Name: "@", Type: "MX", TTL: time.Hour, Data: "10 mail.example.com"},
+		},
+		{
+			libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Priority: 1, Weight: 2, Port: 5060, Target: "SIP.example.com."},
+			libdns.RR{Name: "_sip._tcp", Type: "SRV", TTL: time.Hour, Data: "1 2 5060 sip.example.com"},
+		},
+		{
+			NewAlias("example.com.", "lb.example.net.", 10*time.Minute),
+			libdns.RR{Name: "@", Type: "ALIAS", TTL: 10 * time.Minute, Data: "lb.example.net"},
+		},
+		{
+			libdns.TXT{Name: "_dmarc", Text: "v=DMARC1; p=none"},
+			libdns.RR{Name: "_dmarc", Type: "TXT", TTL: time.Hour, Data: "v=DMARC1; p=none"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := p.NormalizeRecord("example.com.", tt.record); got != tt.want {
+			t.Errorf("NormalizeRecord(%+v) = %+v, want %+v", tt.record, got, tt.want)
+		}
+	}
+}
+
+func TestRecordsEqual(t *testing.T) {
+	p := &Provider{}
+	local := libdns.MX{Name: "@", Preference: 10, Target: "Mail.Example.com.", TTL: time.Minute}
+	live := namesileoRecord{
+		Record: libdns.MX{Name: "example.com", Preference: 10, Target: "mail.example.com", TTL: time.Hour},
+		ID:     "1",
+	}
+	if !p.RecordsEqual("example.com", local, live) {
+		t.Error("Expected records to be equal")
+	}
+
+	clamp := &Provider{TTLPolicy: TTLClamp}
+	if clamp.RecordsEqual("example.com", local, live) {
+		t.Error("Expected TTL difference after clamping")
+	}
+	if !clamp.sameData("example.com", local, live) {
+		t.Error("Expected same data regardless of TTL")
+	}
+
+	if p.RecordsEqual("example.com", libdns.CNAME{Name: "www", Target: "a.example.net"}, NewAlias("www", "a.example.net", 0)) {
+		t.Error("CNAME and ALIAS records should differ")
+	}
+}
@@ -0,0 +1,143 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Order is an order placed in the account.
+type Order struct {
+	Number string
+	Date   time.Time
+	Method string  // Payment method
+	Total  float64 // Order total, in USD
+}
+
+// OrderLineItem is a single item of an order.
+type OrderLineItem struct {
+	Description    string
+	Years          int
+	Price          float64
+	Subtotal       float64
+	Status         string
+	CreditedDate   time.Time // Zero if the item was not credited
+	CreditedAmount float64
+}
+
+// OrderDetails is an order with its line items.
+type OrderDetails struct {
+	Order
+	Items []OrderLineItem
+}
+
+// orderRecord represents an order from NameSilo API
+type orderRecord struct {
+	Number string  `xml:"order_number"`
+	Date   string  `xml:"order_date"`
+	Method string  `xml:"method"`
+	Total  float64 `xml:"total"`
+}
+
+// order converts the record to an Order
+func (r orderRecord) order() Order {
+	date, _ := parseAPITime(r.Date)
+	return Order{
+		Number: r.Number,
+		Date:   date,
+		Method: r.Method,
+		Total:  r.Total,
+	}
+}
+
+// orderLineItem represents an order line item from NameSilo API
+type orderLineItem struct {
+	Description    string  `xml:"description"`
+	Years          int     `xml:"years_qty"`
+	Price          float64 `xml:"price"`
+	Subtotal       float64 `xml:"subtotal"`
+	Status         string  `xml:"status"`
+	CreditedDate   string  `xml:"credited_date"`
+	CreditedAmount float64 `xml:"credited_amount"`
+}
+
+// listOrdersResponse represents the response from listOrders
+type listOrdersResponse struct {
+	apiResponse
+	Orders []orderRecord `xml:"reply>order"`
+}
+
+// orderDetailsResponse represents the response from orderDetails
+type orderDetailsResponse struct {
+	apiResponse
+	Date   string          `xml:"reply>order_date"`
+	Method string          `xml:"reply>method"`
+	Total  float64         `xml:"reply>total"`
+	Items  []orderLineItem `xml:"reply>order_details"`
+}
+
+// ListOrders lists the orders placed in the account.
+func (p *Provider) ListOrders(ctx context.Context) ([]Order, error) {
+	var response listOrdersResponse
+	if err := p.callAPI(ctx, "listOrders", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, 0, len(response.Orders))
+	for _, rec := range response.Orders {
+		orders = append(orders, rec.order())
+	}
+
+	return orders, nil
+}
+
+// OrderDetails returns an order and its line items.
+func (p *Provider) OrderDetails(ctx context.Context, orderNumber string) (*OrderDetails, error) {
+	if orderNumber == "" {
+		return nil, fmt.Errorf("order number is required")
+	}
+
+	params := map[string]string{
+		"order_number": orderNumber,
+	}
+
+	var response orderDetailsResponse
+	if err := p.callAPI(ctx, "orderDetails", params, &response); err != nil {
+		return nil, err
+	}
+
+	details := &OrderDetails{
+		Order: orderRecord{
+			Number: orderNumber,
+			Date:   response.Date,
+			Method: response.Method,
+			Total:  response.Total,
+		}.order(),
+	}
+
+	for _, item := range response.Items {
+		credited, _ := parseAPITime(item.CreditedDate)
+		details.Items = append(details.Items, OrderLineItem{
+			Description:    item.Description,
+			Years:          item.Years,
+			Price:          item.Price,
+			Subtotal:       item.Subtotal,
+			Status:         item.Status,
+			CreditedDate:   credited,
+			CreditedAmount: item.CreditedAmount,
+		})
+	}
+
+	return details, nil
+}
+
+// parseAPITime parses a timestamp as formatted by NameSilo, with or without
+// a time of day
+func parseAPITime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return t, nil
+	}
+	return parseAPIDate(s)
+}
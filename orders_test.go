@@ -0,0 +1,59 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListOrders(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<order><order_number>1001</order_number><order_date>2024-03-01 10:15:00</order_date><method>Account Funds</method><total>10.95</total></order>
+<order><order_number>1002</order_number><order_date>2024-04-01</order_date><method>Credit Card</method><total>21.90</total></order>
+</reply></namesilo>`))
+	})
+
+	orders, err := provider.ListOrders(context.Background())
+	if err != nil {
+		t.Fatalf("ListOrders failed: %v", err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("Expected 2 orders, got %d", len(orders))
+	}
+	want := Order{Number: "1001", Date: time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC), Method: "Account Funds", Total: 10.95}
+	if orders[0] != want {
+		t.Errorf("Unexpected order: %+v", orders[0])
+	}
+	if orders[1].Date.IsZero() {
+		t.Error("Expected date-only timestamps to be parsed")
+	}
+}
+
+func TestOrderDetails(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("order_number") != "1001" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<order_date>2024-03-01 10:15:00</order_date><method>Account Funds</method><total>21.90</total>
+<order_details><description>Domain Registration (example.com)</description><years_qty>1</years_qty><price>10.95</price><subtotal>10.95</subtotal><status>Complete</status></order_details>
+<order_details><description>Domain Renewal (example.net)</description><years_qty>1</years_qty><price>10.95</price><subtotal>10.95</subtotal><status>Credited</status><credited_date>2024-03-02</credited_date><credited_amount>10.95</credited_amount></order_details>
+</reply></namesilo>`))
+	})
+
+	details, err := provider.OrderDetails(context.Background(), "1001")
+	if err != nil {
+		t.Fatalf("OrderDetails failed: %v", err)
+	}
+	if details.Number != "1001" || details.Total != 21.90 || details.Method != "Account Funds" {
+		t.Errorf("Unexpected order: %+v", details.Order)
+	}
+	if len(details.Items) != 2 {
+		t.Fatalf("Expected 2 line items, got %d", len(details.Items))
+	}
+	if item := details.Items[1]; item.Status != "Credited" || item.CreditedAmount != 10.95 || item.CreditedDate.IsZero() {
+		t.Errorf("Unexpected credited item: %+v", item)
+	}
+}
@@ -0,0 +1,161 @@
+package namesilo
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// resourceRecord is a resource_record element as it appears in a
+// dnsListRecords reply. The numeric fields are kept as text so that a bad
+// value affects only its own record instead of failing the whole listing.
+type resourceRecord struct {
+	ID       string `xml:"record_id"`
+	Type     string `xml:"type"`
+	Host     string `xml:"host"`
+	Value    string `xml:"value"`
+	TTL      string `xml:"ttl"`
+	Distance string `xml:"distance"`
+}
+
+// dnsRecord converts the element to a dnsRecord. Empty numeric fields are
+// read as zero, as NameSilo omits the distance of most record types.
+func (r resourceRecord) dnsRecord() (dnsRecord, error) {
+	rec := dnsRecord{
+		ID:    strings.TrimSpace(r.ID),
+		Type:  strings.TrimSpace(r.Type),
+		Host:  strings.TrimSpace(r.Host),
+		Value: r.Value,
+	}
+
+	var err error
+	if rec.TTL, err = parseRecordNumber(r.TTL); err != nil {
+		return rec, malformedRecord(rec, "invalid TTL %q", r.TTL)
+	}
+	if rec.Distance, err = parseRecordNumber(r.Distance); err != nil {
+		return rec, malformedRecord(rec, "invalid distance %q", r.Distance)
+	}
+	return rec, nil
+}
+
+// parseRecordNumber parses a non-negative numeric field of a record
+func parseRecordNumber(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 31)
+	return int(n), err
+}
+
+// malformedRecord returns an error describing bad data in rec
+func malformedRecord(rec dnsRecord, format string, args ...interface{}) error {
+	return fmt.Errorf("%s record %s (ID %s): %s", rec.Type, rec.Host, rec.ID, fmt.Sprintf(format, args...))
+}
+
+// genericRecord returns rec as a libdns.RR with its data unchanged
+func genericRecord(rec dnsRecord) libdns.RR {
+	return libdns.RR{
+		Name: rec.Host,
+		Type: rec.Type,
+		Data: rec.Value,
+		TTL:  time.Duration(rec.TTL) * time.Second,
+	}
+}
+
+// parseRecord converts a record returned by NameSilo to the matching libdns
+// type. Data that does not fit the type is reported as an error rather
+// than defaulted, so that a record is never returned with different data
+// than NameSilo holds. Unknown types are returned as generic RRs.
+func parseRecord(rec dnsRecord) (libdns.Record, error) {
+	if rec.Type == "" {
+		return nil, malformedRecord(rec, "type is missing")
+	}
+	if rec.Host == "" {
+		return nil, malformedRecord(rec, "host is missing")
+	}
+	if rec.TTL < 0 {
+		return nil, malformedRecord(rec, "invalid TTL %d", rec.TTL)
+	}
+	ttl := time.Duration(rec.TTL) * time.Second
+
+	switch strings.ToUpper(rec.Type) {
+	case "A", "AAAA":
+		addr, err := netip.ParseAddr(rec.Value)
+		if err != nil || addr.Zone() != "" {
+			return nil, malformedRecord(rec, "invalid IP address %q", rec.Value)
+		}
+		if addr.Is4() != strings.EqualFold(rec.Type, "A") {
+			return nil, malformedRecord(rec, "address %s does not match the record type", rec.Value)
+		}
+		return libdns.RR{Name: rec.Host, Type: rec.Type, Data: rec.Value, TTL: ttl}, nil
+	case "MX":
+		preference, err := recordDistance(rec)
+		if err != nil {
+			return nil, err
+		}
+		target, err := recordTarget(rec)
+		if err != nil {
+			return nil, err
+		}
+		return libdns.MX{Name: rec.Host, TTL: ttl, Preference: preference, Target: target}, nil
+	case "TXT":
+		return libdns.TXT{Name: rec.Host, TTL: ttl, Text: joinTXT(rec.Value)}, nil
+	case "CNAME":
+		target, err := recordTarget(rec)
+		if err != nil {
+			return nil, err
+		}
+		return libdns.CNAME{Name: rec.Host, TTL: ttl, Target: target}, nil
+	case aliasType:
+		target, err := recordTarget(rec)
+		if err != nil {
+			return nil, err
+		}
+		return NewAlias(rec.Host, target, ttl), nil
+	case "NS":
+		target, err := recordTarget(rec)
+		if err != nil {
+			return nil, err
+		}
+		return libdns.NS{Name: rec.Host, TTL: ttl, Target: target}, nil
+	case "SRV":
+		if _, err := recordDistance(rec); err != nil {
+			return nil, err
+		}
+		return parseSRV(rec)
+	case "TLSA":
+		tlsa, err := parseTLSA(rec.Host, ttl, rec.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w (ID %s)", err, rec.ID)
+		}
+		return tlsa, nil
+	}
+	return genericRecord(rec), nil
+}
+
+// recordDistance returns the distance of rec as an MX preference or SRV
+// priority
+func recordDistance(rec dnsRecord) (uint16, error) {
+	if rec.Distance < 0 || rec.Distance > 65535 {
+		return 0, malformedRecord(rec, "invalid distance %d", rec.Distance)
+	}
+	return uint16(rec.Distance), nil
+}
+
+// recordTarget returns the host name a record points to, which must be a
+// single non-empty field
+func recordTarget(rec dnsRecord) (string, error) {
+	target := strings.TrimSpace(rec.Value)
+	if target == "" {
+		return "", malformedRecord(rec, "target is missing")
+	}
+	if strings.ContainsAny(target, " \t\r\n") {
+		return "", malformedRecord(rec, "invalid target %q", rec.Value)
+	}
+	return target, nil
+}
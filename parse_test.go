@@ -0,0 +1,184 @@
+package namesilo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  dnsRecord
+		want libdns.Record // nil if the record is malformed
+	}{
+		{"A", dnsRecord{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+			libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour}},
+		{"AAAA", dnsRecord{Type: "AAAA", Host: "www", Value: "2001:db8::1", TTL: 3600},
+			libdns.RR{Name: "www", Type: "AAAA", Data: "2001:db8::1", TTL: time.Hour}},
+		{"MX", dnsRecord{Type: "MX", Host: "@", Value: " mail.example.com ", TTL: 3600, Distance: 10},
+			libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com", TTL: time.Hour}},
+		{"CNAME", dnsRecord{Type: "CNAME", Host: "www", Value: "example.net", TTL: 3600},
+			libdns.CNAME{Name: "www", Target: "example.net", TTL: time.Hour}},
+		{"ALIAS", dnsRecord{Type: "ALIAS", Host: "@", Value: "example.net", TTL: 3600},
+			NewAlias("@", "example.net", time.Hour)},
+		{"NS", dnsRecord{Type: "NS", Host: "sub", Value: "ns1.example.net", TTL: 3600},
+			libdns.NS{Name: "sub", Target: "ns1.example.net", TTL: time.Hour}},
+		{"TXT", dnsRecord{Type: "TXT", Host: "@", Value: `"v=spf1 -all"`, TTL: 3600},
+			libdns.TXT{Name: "@", Text: "v=spf1 -all", TTL: time.Hour}},
+		{"unknown type", dnsRecord{Type: "CAA", Host: "@", Value: `0 issue "letsencrypt.org"`, TTL: 3600},
+			libdns.RR{Name: "@", Type: "CAA", Data: `0 issue "letsencrypt.org"`, TTL: time.Hour}},
+
+		{"no type", dnsRecord{Host: "www", Value: "192.0.2.1"}, nil},
+		{"no host", dnsRecord{Type: "A", Value: "192.0.2.1"}, nil},
+		{"negative TTL", dnsRecord{Type: "A", Host: "www", Value: "192.0.2.1", TTL: -1}, nil},
+		{"A with IPv6", dnsRecord{Type: "A", Host: "www", Value: "2001:db8::1"}, nil},
+		{"AAAA with IPv4", dnsRecord{Type: "AAAA", Host: "www", Value: "192.0.2.1"}, nil},
+		{"A with garbage", dnsRecord{Type: "A", Host: "www", Value: "192.0.2"}, nil},
+		{"A with zone", dnsRecord{Type: "AAAA", Host: "www", Value: "fe80::1%eth0"}, nil},
+		{"MX without target", dnsRecord{Type: "MX", Host: "@", Value: " ", Distance: 10}, nil},
+		{"MX with negative distance", dnsRecord{Type: "MX", Host: "@", Value: "mail.example.com", Distance: -1}, nil},
+		{"MX with large distance", dnsRecord{Type: "MX", Host: "@", Value: "mail.example.com", Distance: 65536}, nil},
+		{"CNAME with two targets", dnsRecord{Type: "CNAME", Host: "www", Value: "a.example b.example"}, nil},
+		{"ALIAS without target", dnsRecord{Type: "ALIAS", Host: "@"}, nil},
+		{"NS without target", dnsRecord{Type: "NS", Host: "sub"}, nil},
+		{"SRV with large priority", dnsRecord{Type: "SRV", Host: "_sip._tcp", Value: "5 5060 sip.example.com", Distance: 70000}, nil},
+		{"SRV with large port", dnsRecord{Type: "SRV", Host: "_sip._tcp", Value: "5 70000 sip.example.com"}, nil},
+		{"TLSA with bad hex", dnsRecord{Type: "TLSA", Host: "_443._tcp", Value: "3 1 1 xyz"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRecord(tt.rec)
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRecord failed: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRecord = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceRecordNumbers(t *testing.T) {
+	rec, err := resourceRecord{ID: " 1 ", Type: "MX", Host: "example.com", Value: "mail.example.com", TTL: " 3600 "}.dnsRecord()
+	if err != nil {
+		t.Fatalf("dnsRecord failed: %v", err)
+	}
+	if rec.ID != "1" || rec.TTL != 3600 || rec.Distance != 0 {
+		t.Errorf("Unexpected record %+v", rec)
+	}
+
+	for _, bad := range []resourceRecord{
+		{Type: "A", Host: "www", TTL: "1h"},
+		{Type: "A", Host: "www", TTL: "-5"},
+		{Type: "MX", Host: "@", Distance: "ten"},
+		{Type: "MX", Host: "@", Distance: "99999999999"},
+	} {
+		if _, err := bad.dnsRecord(); err == nil {
+			t.Errorf("Expected an error for %+v", bad)
+		}
+	}
+}
+
+func TestDecodeListBadNumbers(t *testing.T) {
+	body := `<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>soon</ttl></resource_record>
+<resource_record><record_id>2</record_id><type>A</type><host>api.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`
+
+	var resp dnsListResponse
+	if err := decodeResponse(strings.NewReader(body), &resp); err != nil {
+		t.Fatalf("Expected a bad TTL to affect only its record, got %v", err)
+	}
+	if len(resp.Records) != 2 || len(resp.Malformed) != 1 {
+		t.Fatalf("Expected 2 records with 1 malformed, got %v and %v", resp.Records, resp.Malformed)
+	}
+	if id, _ := RecordID(resp.Records[0]); id != "1" {
+		t.Errorf("Expected the malformed record to keep its ID, got %+v", resp.Records[0])
+	}
+}
+
+// FuzzParseRecord checks that records are either rejected or converted to
+// a form that the package sends back to NameSilo unchanged
+func FuzzParseRecord(f *testing.F) {
+	f.Add("A", "www.example.com", "192.0.2.1", 3600, 0)
+	f.Add("AAAA", "www.example.com", "2001:db8::1", 3600, 0)
+	f.Add("MX", "example.com", "mail.example.com", 3600, 10)
+	f.Add("TXT", "example.com", `"v=spf1" " -all"`, 3600, 0)
+	f.Add("TXT", "example.com", `say "hello"`, 3600, 0)
+	f.Add("CNAME", "www.example.com", "example.net", 3600, 0)
+	f.Add("ALIAS", "example.com", "example.net", 3600, 0)
+	f.Add("NS", "sub.example.com", "ns1.example.net", 3600, 0)
+	f.Add("SRV", "_sip._tcp.example.com", "5 5060 sip.example.com", 3600, 1)
+	f.Add("SRV", "_sip._tcp.example.com", "1 5 5060 sip.example.com", 3600, 0)
+	f.Add("TLSA", "_443._tcp.www.example.com", "3 1 1 "+testTLSAHash, 3600, 0)
+	f.Add("CAA", "example.com", `0 issue "letsencrypt.org"`, 3600, 0)
+
+	f.Fuzz(func(t *testing.T, recordType, host, value string, ttl, distance int) {
+		rec := dnsRecord{ID: "1", Type: recordType, Host: host, Value: value, TTL: ttl, Distance: distance}
+		parsed, err := parseRecord(rec)
+		if err != nil {
+			return
+		}
+		if parsed.RR().TTL < 0 {
+			t.Fatalf("Negative TTL in %+v", parsed)
+		}
+
+		sent, sentDistance := extractRecordData(parsed)
+		again, err := parseRecord(dnsRecord{ID: "1", Type: rec.Type, Host: rec.Host, Value: sent, TTL: ttl, Distance: sentDistance})
+		if err != nil {
+			t.Fatalf("Value %q sent for %+v is rejected: %v", sent, parsed, err)
+		}
+		if !reflect.DeepEqual(again, parsed) {
+			t.Fatalf("Round trip changed the record:\n got %#v\nwant %#v", again, parsed)
+		}
+	})
+}
+
+// FuzzDecodeList checks that every record in a listing is returned, and
+// that each one is either well-formed or reported as malformed
+func FuzzDecodeList(f *testing.F) {
+	f.Add(`<namesilo><reply><code>300</code><resource_record><record_id>1</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>3600</ttl><distance>0</distance></resource_record></reply></namesilo>`)
+	f.Add(`<namesilo><reply><code>300</code><resource_record><record_id>2</record_id><type>MX</type><host>example.com</host><value>mail.example.com</value><ttl>x</ttl><distance>10</distance></resource_record></reply></namesilo>`)
+	f.Add(`<namesilo><reply><code>300</code><resource_record><type>SRV</type><host>_sip._tcp</host><value>bogus</value></resource_record></reply></namesilo>`)
+
+	f.Fuzz(func(t *testing.T, body string) {
+		var resp dnsListResponse
+		if err := decodeResponse(strings.NewReader(body), &resp); err != nil {
+			return
+		}
+		if len(resp.Malformed) > len(resp.Records) {
+			t.Fatalf("%d malformed records out of %d", len(resp.Malformed), len(resp.Records))
+		}
+		for _, rec := range resp.Records {
+			if _, ok := rec.(namesileoRecord); !ok {
+				t.Fatalf("Record %#v lacks its ID wrapper", rec)
+			}
+		}
+	})
+}
+
+// FuzzSplitQuotedStrings checks that quoted TXT values survive the quoting
+// applied when they are sent
+func FuzzSplitQuotedStrings(f *testing.F) {
+	f.Add(`"abc" "def"`)
+	f.Add(`"a\"b" "c\\d"`)
+	f.Add(`"unterminated`)
+	f.Add(`plain text`)
+
+	f.Fuzz(func(t *testing.T, text string) {
+		if got := joinTXT(txtValue(text)); got != text {
+			t.Fatalf("TXT text %q came back as %q", text, got)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// portfolioListResponse represents the response from portfolioList
+type portfolioListResponse struct {
+	apiResponse
+	Portfolios []string `xml:"reply>portfolios>portfolio"`
+}
+
+// PortfolioList lists the portfolio names of the account.
+func (p *Provider) PortfolioList(ctx context.Context) ([]string, error) {
+	var response portfolioListResponse
+	if err := p.callAPI(ctx, "portfolioList", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Portfolios, nil
+}
+
+// PortfolioAdd creates a portfolio.
+func (p *Provider) PortfolioAdd(ctx context.Context, portfolio string) error {
+	return p.portfolioOperation(ctx, "portfolioAdd", portfolio)
+}
+
+// PortfolioDelete deletes a portfolio. It must not contain any domains.
+func (p *Provider) PortfolioDelete(ctx context.Context, portfolio string) error {
+	return p.portfolioOperation(ctx, "portfolioDelete", portfolio)
+}
+
+// portfolioOperation performs an operation that only takes a portfolio name
+func (p *Provider) portfolioOperation(ctx context.Context, operation, portfolio string) error {
+	if strings.TrimSpace(portfolio) == "" {
+		return fmt.Errorf("portfolio name is required")
+	}
+
+	params := map[string]string{
+		"portfolio": portfolio,
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, operation, params, &response)
+}
+
+// PortfolioDomainAssociate moves the given domains into a portfolio.
+func (p *Provider) PortfolioDomainAssociate(ctx context.Context, portfolio string, domains []string) error {
+	if strings.TrimSpace(portfolio) == "" {
+		return fmt.Errorf("portfolio name is required")
+	}
+	if len(domains) == 0 {
+		return fmt.Errorf("at least one domain is required")
+	}
+
+	names := make([]string, len(domains))
+	for i, domain := range domains {
+		names[i] = strings.TrimSuffix(domain, ".")
+	}
+
+	params := map[string]string{
+		"portfolio": portfolio,
+		"domains":   strings.Join(names, ","),
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "portfolioDomainAssociate", params, &response)
+}
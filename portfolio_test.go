@@ -0,0 +1,50 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestPortfolios(t *testing.T) {
+	var query map[string][]string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		switch r.URL.Path {
+		case "/api/portfolioList":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><portfolios><portfolio>clients</portfolio><portfolio>parked</portfolio></portfolios></reply></namesilo>`))
+		case "/api/listDomains":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domains><domain>example.com</domain></domains></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+	ctx := context.Background()
+
+	portfolios, err := provider.PortfolioList(ctx)
+	if err != nil {
+		t.Fatalf("PortfolioList failed: %v", err)
+	}
+	if len(portfolios) != 2 || portfolios[0] != "clients" {
+		t.Errorf("Unexpected portfolios: %v", portfolios)
+	}
+
+	if err := provider.PortfolioDomainAssociate(ctx, "clients", []string{"example.com.", "example.net"}); err != nil {
+		t.Fatalf("PortfolioDomainAssociate failed: %v", err)
+	}
+	if query["domains"][0] != "example.com,example.net" {
+		t.Errorf("Unexpected domains parameter: %v", query["domains"])
+	}
+
+	domains, err := provider.ListDomains(ctx, ListDomainsOptions{Portfolio: "clients"})
+	if err != nil {
+		t.Fatalf("ListDomains failed: %v", err)
+	}
+	if query["portfolio"][0] != "clients" || len(domains) != 1 {
+		t.Errorf("Unexpected ListDomains result %v for query %v", domains, query)
+	}
+
+	if err := provider.PortfolioAdd(ctx, " "); err == nil {
+		t.Error("Expected error for empty portfolio name")
+	}
+}
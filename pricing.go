@@ -0,0 +1,84 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+)
+
+// TLDPrice holds the account prices for a TLD, in USD.
+type TLDPrice struct {
+	Registration float64
+	Transfer     float64
+	Renewal      float64
+}
+
+// Prices maps TLDs (without leading dot, e.g. "com") to their prices.
+type Prices map[string]TLDPrice
+
+// tldPriceElement represents a per-TLD element of the getPrices reply,
+// whose element name is the TLD itself
+type tldPriceElement struct {
+	XMLName      xml.Name
+	Registration float64 `xml:"registration"`
+	Transfer     float64 `xml:"transfer"`
+	Renew        float64 `xml:"renew"`
+}
+
+// pricesResponse represents the response from getPrices. Its TLD elements
+// are named after the TLD, so the reply is decoded by UnmarshalXML.
+type pricesResponse struct {
+	apiResponse
+	TLDs []tldPriceElement
+}
+
+// UnmarshalXML decodes the reply code and detail into the embedded
+// apiResponse and every other reply element into TLDs
+func (r *pricesResponse) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var decoded struct {
+		Reply struct {
+			Code   int               `xml:"code"`
+			Detail string            `xml:"detail"`
+			TLDs   []tldPriceElement `xml:",any"`
+		} `xml:"reply"`
+	}
+	if err := d.DecodeElement(&decoded, &start); err != nil {
+		return err
+	}
+	r.Code, r.Detail, r.TLDs = decoded.Reply.Code, decoded.Reply.Detail, decoded.Reply.TLDs
+	return nil
+}
+
+// GetPrices returns registration, transfer and renewal prices for the
+// account. If tlds are given, only those TLDs are included.
+func (p *Provider) GetPrices(ctx context.Context, tlds ...string) (Prices, error) {
+	var response pricesResponse
+	if err := p.callAPI(ctx, "getPrices", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(tlds))
+	for _, tld := range tlds {
+		wanted[normalizeTLD(tld)] = true
+	}
+
+	prices := make(Prices)
+	for _, elem := range response.TLDs {
+		tld := normalizeTLD(elem.XMLName.Local)
+		if len(wanted) > 0 && !wanted[tld] {
+			continue
+		}
+		prices[tld] = TLDPrice{
+			Registration: elem.Registration,
+			Transfer:     elem.Transfer,
+			Renewal:      elem.Renew,
+		}
+	}
+
+	return prices, nil
+}
+
+// normalizeTLD lowercases a TLD and strips dots
+func normalizeTLD(tld string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+}
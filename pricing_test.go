@@ -0,0 +1,65 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestGetPrices(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<com><registration>10.95</registration><transfer>10.95</transfer><renew>13.95</renew></com>
+<net><registration>12.95</registration><transfer>12.95</transfer><renew>14.95</renew></net>
+<io><registration>34.99</registration><transfer>34.99</transfer><renew>44.99</renew></io>
+</reply></namesilo>`))
+	})
+
+	prices, err := provider.GetPrices(context.Background())
+	if err != nil {
+		t.Fatalf("GetPrices failed: %v", err)
+	}
+	if len(prices) != 3 {
+		t.Errorf("Expected 3 TLDs, got %d", len(prices))
+	}
+	if want := (TLDPrice{Registration: 10.95, Transfer: 10.95, Renewal: 13.95}); prices["com"] != want {
+		t.Errorf("Unexpected .com price: %+v", prices["com"])
+	}
+
+	prices, err = provider.GetPrices(context.Background(), ".NET", "io")
+	if err != nil {
+		t.Fatalf("GetPrices failed: %v", err)
+	}
+	if _, ok := prices["com"]; ok || len(prices) != 2 {
+		t.Errorf("Expected only filtered TLDs, got %v", prices)
+	}
+}
+
+func TestGetPricesCaptureResponses(t *testing.T) {
+	body := `<namesilo><reply><code>300</code><detail>success</detail><com><registration>10.95`
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	provider.CaptureResponses = true
+
+	_, err := provider.GetPrices(context.Background())
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) {
+		t.Fatalf("Expected *RequestError, got %v", err)
+	}
+	if reqErr.Response != body {
+		t.Errorf("Expected the raw reply, got %q", reqErr.Response)
+	}
+}
+
+func TestGetPricesStrictDecoding(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><request><operation>listDomains</operation></request><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.StrictDecoding = true
+
+	if _, err := provider.GetPrices(context.Background()); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Errorf("Expected ErrUnexpectedResponse, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// defaultPropagationInterval is the delay between WaitForPropagation polls
+const defaultPropagationInterval = 5 * time.Second
+
+// WaitForPropagation polls the zone's authoritative nameservers until all of
+// them serve record, or ctx is done. It is meant for callers that must not
+// proceed before a change is visible, such as ACME DNS-01 challenges.
+//
+// The nameservers are PropagationNameservers if set, otherwise the zone's
+// NS records. A, AAAA, CNAME, MX, NS, SRV and TXT records are supported.
+func (p *Provider) WaitForPropagation(ctx context.Context, zone string, record libdns.Record) error {
+	if nsRec, ok := record.(namesileoRecord); ok {
+		record = nsRec.Record
+	}
+	if rr, ok := record.(libdns.RR); ok {
+		parsed, err := rr.Parse()
+		if err != nil {
+			return err
+		}
+		record = parsed
+	}
+	switch record.(type) {
+	case libdns.TXT, libdns.Address, libdns.CNAME, libdns.MX, libdns.NS, libdns.SRV:
+		if IsAlias(record) {
+			return fmt.Errorf("cannot check propagation of ALIAS records")
+		}
+	default:
+		return fmt.Errorf("cannot check propagation of %s records", record.RR().Type)
+	}
+
+	servers, err := p.propagationServers(ctx, zone)
+	if err != nil {
+		return err
+	}
+	name := qualifyName(normalizeRecordName(record.RR().Name, zone), fqdn(zone))
+
+	interval := p.PropagationInterval
+	if interval <= 0 {
+		interval = defaultPropagationInterval
+	}
+
+	for {
+		var pending []string
+		var lastErr error
+		for _, server := range servers {
+			visible, err := recordVisible(ctx, resolverFor(server), name, record)
+			if err != nil {
+				lastErr = err
+			}
+			if !visible {
+				pending = append(pending, server)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			msg := fmt.Sprintf("%s %s record not visible on %s", name, record.RR().Type, strings.Join(pending, ", "))
+			if lastErr != nil {
+				msg += fmt.Sprintf(" (last error: %v)", lastErr)
+			}
+			return fmt.Errorf("%s: %w", msg, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// propagationServers returns the nameserver addresses to poll for a zone
+func (p *Provider) propagationServers(ctx context.Context, zone string) ([]string, error) {
+	servers := p.PropagationNameservers
+	if len(servers) == 0 {
+		// A zone below a registered domain is served by the domain's
+		// nameservers
+		sub, err := p.resolveZone(ctx, zone)
+		if err != nil {
+			return nil, err
+		}
+		if sub != nil {
+			zone = sub.domain
+		}
+		nss, err := net.DefaultResolver.LookupNS(ctx, fqdn(zone))
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up nameservers of %s: %w", zone, err)
+		}
+		for _, ns := range nss {
+			servers = append(servers, strings.TrimSuffix(ns.Host, "."))
+		}
+	}
+
+	addrs := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		addrs = append(addrs, server)
+	}
+	return addrs, nil
+}
+
+// resolverFor returns a resolver that sends all queries to server
+func resolverFor(server string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// recordVisible reports whether the resolver returns record for name
+func recordVisible(ctx context.Context, r *net.Resolver, name string, record libdns.Record) (bool, error) {
+	switch rec := record.(type) {
+	case libdns.TXT:
+		txts, err := r.LookupTXT(ctx, name)
+		return slices.Contains(txts, rec.Text), err
+	case libdns.Address:
+		network := "ip4"
+		if rec.IP.Is6() {
+			network = "ip6"
+		}
+		ips, err := r.LookupNetIP(ctx, network, name)
+		for _, ip := range ips {
+			if ip.Unmap() == rec.IP.Unmap() {
+				return true, nil
+			}
+		}
+		return false, err
+	case libdns.CNAME:
+		target, err := r.LookupCNAME(ctx, name)
+		return err == nil && canonicalHost(target) == canonicalHost(rec.Target), err
+	case libdns.MX:
+		mxs, err := r.LookupMX(ctx, name)
+		return slices.ContainsFunc(mxs, func(mx *net.MX) bool {
+			return mx.Pref == rec.Preference && canonicalHost(mx.Host) == canonicalHost(rec.Target)
+		}), err
+	case libdns.NS:
+		nss, err := r.LookupNS(ctx, name)
+		return slices.ContainsFunc(nss, func(ns *net.NS) bool {
+			return canonicalHost(ns.Host) == canonicalHost(rec.Target)
+		}), err
+	case libdns.SRV:
+		_, srvs, err := r.LookupSRV(ctx, "", "", name)
+		return slices.ContainsFunc(srvs, func(srv *net.SRV) bool {
+			return srv.Priority == rec.Priority && srv.Weight == rec.Weight && srv.Port == rec.Port &&
+				canonicalHost(srv.Target) == canonicalHost(rec.Target)
+		}), err
+	}
+	return false, fmt.Errorf("cannot check propagation of %s records", record.RR().Type)
+}
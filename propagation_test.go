@@ -0,0 +1,141 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// txtServer is a minimal authoritative DNS server answering TXT queries
+type txtServer struct {
+	conn net.PacketConn
+
+	mu      sync.Mutex
+	records map[string][]string // lowercase FQDN to TXT values
+	queries int
+}
+
+func newTXTServer(t *testing.T) *txtServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen on UDP: %v", err)
+	}
+	s := &txtServer{conn: conn, records: make(map[string][]string)}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+	return s
+}
+
+func (s *txtServer) set(name string, values ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[name] = values
+}
+
+func (s *txtServer) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.answer(buf[:n]); resp != nil {
+			s.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+// answer builds the reply to a query with a single question
+func (s *txtServer) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+	var labels []string
+	i := 12
+	for i < len(query) && query[i] != 0 {
+		l := int(query[i])
+		if i+1+l > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[i+1:i+1+l]))
+		i += 1 + l
+	}
+	if i+5 > len(query) {
+		return nil
+	}
+	question := query[12 : i+5]
+	qtype := binary.BigEndian.Uint16(query[i+1:])
+	name := strings.ToLower(strings.Join(labels, ".")) + "."
+
+	s.mu.Lock()
+	s.queries++
+	var values []string
+	if qtype == 16 {
+		values = s.records[name]
+	}
+	s.mu.Unlock()
+
+	resp := make([]byte, 12, 512)
+	copy(resp, query[:2])
+	binary.BigEndian.PutUint16(resp[2:], 0x8400|uint16(query[2]&1)<<8) // QR, AA, RD copied
+	binary.BigEndian.PutUint16(resp[4:], 1)
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(values)))
+	resp = append(resp, question...)
+	for _, v := range values {
+		resp = append(resp, 0xc0, 12, 0, 16, 0, 1, 0, 0, 0, 60)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(v)+1))
+		resp = append(resp, byte(len(v)))
+		resp = append(resp, v...)
+	}
+	return resp
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	srv := newTXTServer(t)
+	provider := &Provider{
+		PropagationNameservers: []string{srv.conn.LocalAddr().String()},
+		PropagationInterval:    10 * time.Millisecond,
+	}
+	record := libdns.TXT{Name: "_acme-challenge", Text: "token"}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		srv.set("_acme-challenge.example.com.", "other", "token")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.WaitForPropagation(ctx, "example.com.", record); err != nil {
+		t.Fatalf("WaitForPropagation failed: %v", err)
+	}
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.queries < 2 {
+		t.Errorf("Expected repeated polling, got %d queries", srv.queries)
+	}
+}
+
+func TestWaitForPropagationTimeout(t *testing.T) {
+	srv := newTXTServer(t)
+	provider := &Provider{
+		PropagationNameservers: []string{srv.conn.LocalAddr().String()},
+		PropagationInterval:    10 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := provider.WaitForPropagation(ctx, "example.com", libdns.TXT{Name: "_acme-challenge", Text: "token"})
+	if err == nil || !strings.Contains(err.Error(), "not visible") {
+		t.Errorf("Expected not visible error, got %v", err)
+	}
+
+	if err := provider.WaitForPropagation(context.Background(), "example.com", libdns.CAA{Name: "@", Tag: "issue", Value: "letsencrypt.org"}); err == nil {
+		t.Error("Expected error for unsupported record type")
+	}
+}
@@ -3,40 +3,321 @@
 package namesilo
 
 import (
+	"bufio"
+	"bytes"
 	"context"
-	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"path"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/libdns/libdns"
 )
 
 const (
-	apiEndpoint = "https://www.namesilo.com/api/"
-	minTTL      = 300  // Minimum TTL in seconds (5 minutes)
-	defaultTTL  = 3600 // Default TTL in seconds (1 hour)
+	apiEndpoint     = "https://www.namesilo.com/api/"
+	sandboxEndpoint = "https://sandbox.namesilo.com/api/"
+	minTTL          = 300              // Minimum TTL in seconds (5 minutes)
+	defaultTTL      = 3600             // Default TTL in seconds (1 hour)
+	defaultTimeout  = 30 * time.Second // HTTP timeout when no HTTPClient is configured
+
+	// postNotAllowedCode is the reply code NameSilo returns when an
+	// operation does not accept POST requests
+	postNotAllowedCode = 120
 )
 
 // Provider facilitates DNS record manipulation with NameSilo.
 type Provider struct {
+	// APIToken is the NameSilo API key. It, APITokens, UserAgent and the
+	// values of Headers may contain environment placeholders such as
+	// "{env.NAMESILO_API_TOKEN}", which are expanded when the value is
+	// first used. Other text, including "$NAME", is used as is.
 	APIToken string `json:"api_token,omitempty"`
+
+	// APITokens are additional API keys, used after APIToken according to
+	// KeyRotation. Useful when several keys must work at once, e.g. during
+	// a key rotation window.
+	APITokens []string `json:"api_tokens,omitempty"`
+
+	// TokenSource, if set, supplies the API key for every request instead
+	// of APIToken and APITokens, so keys can come from a secret manager or
+	// be rotated without recreating the Provider.
+	TokenSource TokenSource `json:"-"`
+
+	// Accounts route the domains of other NameSilo accounts to their own
+	// API keys, matched by exact name or by suffix. Domains that match no
+	// account, and operations not about a domain such as ListDomains, use
+	// APIToken, APITokens or TokenSource.
+	Accounts []Account `json:"accounts,omitempty"`
+
+	// KeyRotation selects how the API keys are used when several are
+	// configured. Defaults to KeyFailover.
+	KeyRotation KeyRotation `json:"key_rotation,omitempty"`
+
+	// HTTPClient is used for all API requests. If nil, a client with
+	// Timeout is used.
+	HTTPClient *http.Client `json:"-"`
+
+	// Timeout is the time limit of each HTTP request when HTTPClient is
+	// nil. Defaults to 30 seconds.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// UserAgent is sent with every API request. Defaults to
+	// "libdns-namesilo/<version>".
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// Headers are additional HTTP headers sent with every API request,
+	// e.g. for routing through a corporate gateway.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// MaxResponseSize is the largest API reply, in bytes, that is read;
+	// larger replies fail with a ResponseTooLargeError. Defaults to 16 MiB.
+	MaxResponseSize int64 `json:"max_response_size,omitempty"`
+
+	// MaxRetries is the number of times a request is retried after a
+	// transient failure (network error, HTTP 5xx, or a NameSilo "try again
+	// later" reply, unless RetryPolicy classifies failures otherwise).
+	// Operations that are not idempotent, such as dnsAddRecord, are only
+	// retried if the request was never sent. Zero disables retries.
+	MaxRetries int `json:"max_retries,omitempty"`
+
+	// RetryBaseDelay is the delay before the first retry; it doubles with
+	// each subsequent attempt. Defaults to one second.
+	RetryBaseDelay time.Duration `json:"retry_base_delay,omitempty"`
+
+	// ThrottleRetries is the number of times a request is retried after
+	// NameSilo throttled it (HTTP 429 or a "request still processing"
+	// reply), independent of MaxRetries. Each retry waits longer, or as
+	// long as NameSilo's Retry-After header asks, and delays the other
+	// requests of the Provider as well. Defaults to 5; a negative value
+	// disables these retries.
+	ThrottleRetries int `json:"throttle_retries,omitempty"`
+
+	// RetryPolicy classifies failed requests as transient, throttled or
+	// not worth retrying. Defaults to DefaultRetryPolicy, which never
+	// retries authentication failures or rejected requests.
+	RetryPolicy RetryPolicy `json:"-"`
+
+	// RetryJitter shortens each backoff delay by a random fraction of up
+	// to RetryJitter (0 to 1), so that clients failing together do not
+	// retry in lockstep. Delays requested by NameSilo are kept.
+	RetryJitter float64 `json:"retry_jitter,omitempty"`
+
+	// RetryBudget bounds the time spent on a request including its
+	// retries: a retry that would start later than RetryBudget after the
+	// first attempt is not made. Zero means no bound.
+	RetryBudget time.Duration `json:"retry_budget,omitempty"`
+
+	// RequestsPerSecond limits the rate of API requests made by this
+	// Provider across all operations. Zero means no limit. Changes to it
+	// or to Burst take effect with the next request.
+	RequestsPerSecond float64 `json:"requests_per_second,omitempty"`
+
+	// Burst is the number of requests that may be made at once before
+	// RequestsPerSecond applies. Defaults to 1.
+	Burst int `json:"burst,omitempty"`
+
+	// TTLPolicy decides what happens to record TTLs below MinTTL.
+	// Defaults to TTLUseDefault.
+	TTLPolicy TTLPolicy `json:"ttl_policy,omitempty"`
+
+	// MinTTL is the lowest TTL sent to NameSilo. Defaults to 5 minutes.
+	MinTTL time.Duration `json:"min_ttl,omitempty"`
+
+	// DefaultTTL is used for records without a TTL and, with
+	// TTLUseDefault, for records below MinTTL. Defaults to one hour.
+	DefaultTTL time.Duration `json:"default_ttl,omitempty"`
+
+	// TTLSnap limits TTLs to AcceptedTTLs, replacing other TTLs with the
+	// nearest accepted one or rejecting them. Defaults to TTLSnapOff.
+	TTLSnap TTLSnap `json:"ttl_snap,omitempty"`
+
+	// AcceptedTTLs are the TTLs TTLSnap allows. Defaults to 5, 10, 15 and
+	// 30 minutes, 1, 2, 4, 8 and 12 hours, 1 and 2 days, and 1 week.
+	AcceptedTTLs []time.Duration `json:"accepted_ttls,omitempty"`
+
+	// BreakerThreshold enables a circuit breaker that opens after this many
+	// consecutive failed requests (network errors, timeouts or HTTP 5xx).
+	// While open, requests fail fast with ErrCircuitOpen instead of waiting
+	// on an unavailable API. Zero disables the breaker.
+	BreakerThreshold int `json:"breaker_threshold,omitempty"`
+
+	// BreakerCooldown is how long the circuit breaker stays open before a
+	// trial request is let through. Defaults to 30 seconds.
+	BreakerCooldown time.Duration `json:"breaker_cooldown,omitempty"`
+
+	// ZoneConcurrency is the number of zones GetAllRecords fetches at the
+	// same time. Defaults to 4.
+	ZoneConcurrency int `json:"zone_concurrency,omitempty"`
+
+	// CacheMaxAge enables an in-memory cache of GetRecords results for the
+	// given duration. Any change made through this Provider invalidates the
+	// cached zone. Zero disables caching.
+	CacheMaxAge time.Duration `json:"cache_max_age,omitempty"`
+
+	// PropagationNameservers are the nameservers WaitForPropagation polls,
+	// as "host" or "host:port". Defaults to the zone's NS records.
+	PropagationNameservers []string `json:"propagation_nameservers,omitempty"`
+
+	// PropagationInterval is the delay between WaitForPropagation polls.
+	// Defaults to 5 seconds.
+	PropagationInterval time.Duration `json:"propagation_interval,omitempty"`
+
+	// IPDetectors find the public IP address for UpdateHostIP, tried in
+	// order until one succeeds. Defaults to an HTTPS echo service followed
+	// by STUN.
+	IPDetectors []IPDetector `json:"-"`
+
+	// Auditor, if set, receives an event for every record created, updated
+	// or deleted. Use WithActor to attribute changes to a user or service.
+	Auditor Auditor `json:"-"`
+
+	// WebhookURL, if set, receives a JSON WebhookPayload by POST for every
+	// successful record change. Notifications are delivered in the
+	// background; see WebhookNotifier and FlushWebhook. Like APIToken, it
+	// may contain environment placeholders.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Tracer, if set, creates a span around every API call. See Tracer
+	// for adapting an OpenTelemetry tracer.
+	Tracer Tracer `json:"-"`
+
+	// Metrics, if set, receives a measurement of every API call and of
+	// rate limiter throttling.
+	Metrics Metrics `json:"-"`
+
+	// DebugWriter, if set, receives a dump of every HTTP request URL (with
+	// the API key masked) and the raw response body. Meant for
+	// troubleshooting unexpected replies, not for production use.
+	DebugWriter io.Writer `json:"-"`
+
+	// OnWarning, if set, is called when NameSilo performs an operation but
+	// replies with a warning (reply code 301 or 302). Such replies are
+	// treated as success.
+	OnWarning func(Warning) `json:"-"`
+
+	// Logger receives structured logs of each API operation: successful
+	// calls at debug level, failures at info level. The API key is never
+	// logged. If nil, nothing is logged.
+	Logger *slog.Logger `json:"-"`
+
+	// Subzones maps zones to the registered NameSilo domain they are kept
+	// in, e.g. {"dev.example.com": "example.com"}; records of such a zone
+	// are stored in the domain with their hosts prefixed by "dev". When
+	// set, the Provider manages only the listed zones and only the records
+	// inside them, so a team can be given a sub-namespace of a domain.
+	// A zone mapped to itself is managed as a whole.
+	Subzones map[string]string `json:"subzones,omitempty"`
+
+	// AutoAttachZone delegates a domain registered in the account to
+	// NameSilo's nameservers when it is not yet using NameSilo DNS.
+	AutoAttachZone bool `json:"auto_attach_zone,omitempty"`
+
+	// ApexCNAMEToAlias creates an ALIAS record instead of failing with an
+	// ApexCNAMEError when a CNAME record is given for the zone apex.
+	ApexCNAMEToAlias bool `json:"apex_cname_to_alias,omitempty"`
+
+	// AtomicAppend makes AppendRecords all-or-nothing: when a record fails,
+	// the records already added by the same call are deleted again.
+	AtomicAppend bool `json:"atomic_append,omitempty"`
+
+	// SkipExisting makes AppendRecords skip records that already exist with
+	// the same name, type, value and TTL instead of failing, so that
+	// provisioning can safely be repeated. Skipped records are not
+	// returned as added.
+	SkipExisting bool `json:"skip_existing,omitempty"`
+
+	// RejectDuplicates makes AppendRecords fail with ErrDuplicate, before
+	// adding anything, when a record already exists with the same name,
+	// type, value and TTL. SkipExisting takes precedence.
+	RejectDuplicates bool `json:"reject_duplicates,omitempty"`
+
+	// FollowChallengeCNAME writes _acme-challenge TXT records at the target
+	// of a CNAME when the challenge name is a CNAME into another zone of
+	// the account, supporting challenges delegated to a dedicated zone.
+	// With Subzones set, the CNAME targets must lie inside the configured
+	// subzones as well.
+	FollowChallengeCNAME bool `json:"follow_challenge_cname,omitempty"`
+
+	// UsePOST sends API parameters, including the API key, in a POST form
+	// body instead of the URL query string, keeping the key out of proxy
+	// and access logs. Operations that reject POST fall back to GET.
+	UsePOST bool `json:"use_post,omitempty"`
+
+	// ResponseFormat selects the format NameSilo replies in. Defaults to
+	// FormatXML.
+	ResponseFormat ResponseFormat `json:"response_format,omitempty"`
+
+	// Sandbox sends all requests to NameSilo's sandbox (OTE) environment,
+	// which requires a separate sandbox account and API key.
+	Sandbox bool `json:"sandbox,omitempty"`
+
+	// StrictDecoding makes replies that lack the root, request or reply
+	// code elements, carry an undocumented reply code, or miss elements
+	// an operation must return fail with ErrUnexpectedResponse instead of
+	// being decoded into zero values. Replies are buffered in full to be
+	// checked.
+	StrictDecoding bool `json:"strict_decoding,omitempty"`
+
+	// CaptureResponses attaches the start of the raw reply (up to 4 KiB,
+	// with the API key masked) to APIError and RequestError values as
+	// Response, for reporting unexpected replies.
+	CaptureResponses bool `json:"capture_responses,omitempty"`
+
+	clientOnce    sync.Once
+	defaultClient *http.Client
+
+	envMu sync.Mutex
+	env   map[string]string // configuration values with {env.NAME} expanded
+
+	mu             sync.Mutex
+	limiter        *rateLimiter
+	throttledUntil time.Time // delays all requests after a throttled one
+	breaker        circuitBreaker
+	cache          map[string]cacheEntry
+	fetches        map[string]*zoneFetch   // zone listings in flight
+	getOnly        map[string]bool         // operations that rejected POST
+	subzones       map[string]subzoneEntry // zones below a registered domain
+	keyNext        int                     // index of the next API key to use
+	webhook        *WebhookNotifier        // notifier for WebhookURL
+
+	sourceTokens []string // recent keys from TokenSource, for redaction
 }
 
 // apiResponse represents the common response structure from NameSilo API
 type apiResponse struct {
 	Code   int    `xml:"reply>code"`
 	Detail string `xml:"reply>detail"`
+
+	raw []byte // start of the reply body, with CaptureResponses
+}
+
+// replyCode returns the NameSilo reply code of the response
+func (r apiResponse) replyCode() int {
+	return r.Code
 }
 
-// dnsListResponse represents the response from dnsListRecords
+// replyDetail returns the NameSilo reply detail of the response
+func (r apiResponse) replyDetail() string {
+	return r.Detail
+}
+
+// dnsListResponse represents the response from dnsListRecords. It is
+// decoded as a stream (see decodeStream), so Records holds the converted
+// records rather than the raw resource_record elements.
 type dnsListResponse struct {
 	apiResponse
-	Records []dnsRecord `xml:"reply>resource_record"`
+	Records   []libdns.Record
+	Malformed []error // records kept as generic RRs because of bad data
+
+	sink *recordSink // receives the records instead of Records, if set
 }
 
 // dnsRecord represents a DNS record from NameSilo API
@@ -61,19 +342,53 @@ type dnsUpdateResponse struct {
 	RecordID string `xml:"reply>record_id"`
 }
 
+// httpClient returns the configured HTTP client, or a default one that is
+// created once and shared by all requests so that connections are reused
+func (p *Provider) httpClient() *http.Client {
+	if p.HTTPClient != nil {
+		return p.HTTPClient
+	}
+
+	p.clientOnce.Do(func() {
+		timeout := p.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		p.defaultClient = &http.Client{
+			Timeout: timeout,
+		}
+	})
+	return p.defaultClient
+}
+
+// endpoint returns the base API URL for the configured environment
+func (p *Provider) endpoint() string {
+	if p.Sandbox {
+		return sandboxEndpoint
+	}
+	return apiEndpoint
+}
+
 // buildAPIURL constructs a properly encoded API URL
-func (p *Provider) buildAPIURL(operation string, params map[string]string) (string, error) {
-	u, err := url.Parse(apiEndpoint + operation)
+func (p *Provider) buildAPIURL(operation, token string, params map[string]string) (string, error) {
+	u, err := url.Parse(p.endpoint() + operation)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse API endpoint: %w", err)
 	}
 
-	q := u.Query()
+	u.RawQuery = p.apiValues(token, params).Encode()
+	return u.String(), nil
+}
+
+// apiValues returns the standard parameters (including the API key) and the
+// non-empty operation parameters
+func (p *Provider) apiValues(token string, params map[string]string) url.Values {
+	q := url.Values{}
 
 	// Add standard parameters
 	q.Set("version", "1")
-	q.Set("type", "xml")
-	q.Set("key", p.APIToken)
+	q.Set("type", string(p.responseFormat()))
+	q.Set("key", token)
 
 	// Add custom parameters
 	for key, value := range params {
@@ -82,35 +397,243 @@ func (p *Provider) buildAPIURL(operation string, params map[string]string) (stri
 		}
 	}
 
-	u.RawQuery = q.Encode()
-	return u.String(), nil
+	return q
+}
+
+// newAPIRequest creates the HTTP request for an operation. With UsePOST the
+// parameters, including the API key, are sent as a form body, unless the
+// operation is known to reject POST.
+func (p *Provider) newAPIRequest(ctx context.Context, operation, token string, params map[string]string, method string) (*http.Request, error) {
+	if method == http.MethodGet {
+		apiURL, err := p.buildAPIURL(operation, token, params)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.setHeaders(req)
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint()+operation, strings.NewReader(p.apiValues(token, params).Encode()))
+	if err != nil {
+		return nil, err
+	}
+	p.setHeaders(req)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+// requestMethod returns the HTTP method to use for an operation
+func (p *Provider) requestMethod(operation string) string {
+	if !p.UsePOST {
+		return http.MethodGet
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.getOnly[operation] {
+		return http.MethodGet
+	}
+	return http.MethodPost
+}
+
+// postRejected reports whether NameSilo refused a POST request, in which
+// case the operation is remembered as GET-only
+func (p *Provider) postRejected(operation string, err error, resp replyCoder) bool {
+	var statusErr *httpStatusError
+	rejected := (err == nil && resp.replyCode() == postNotAllowedCode) ||
+		(errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusMethodNotAllowed)
+	if !rejected {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.getOnly == nil {
+		p.getOnly = make(map[string]bool)
+	}
+	p.getOnly[operation] = true
+	return true
 }
 
 // normalizeRecordName converts a record name relative to the zone
 func normalizeRecordName(name, zone string) string {
 	zone = strings.TrimSuffix(zone, ".")
 
+	// Absolute names may carry the trailing dot
+	if strings.HasSuffix(name, ".") {
+		name = strings.TrimSuffix(name, ".")
+		if !strings.EqualFold(name, zone) && !hasZoneSuffix(name, zone) {
+			return name
+		}
+	}
+
 	// Handle root record
-	if name == "@" || name == "" || name == zone {
+	if name == "@" || name == "" || strings.EqualFold(name, zone) {
 		return "@"
 	}
 
 	// Handle already absolute names
-	if strings.HasSuffix(name, "."+zone) {
-		return strings.TrimSuffix(name, "."+zone)
+	if hasZoneSuffix(name, zone) {
+		return name[:len(name)-len(zone)-1]
 	}
 
-	// Return as-is for relative names
+	// Return as-is for relative names, including wildcards such as "*" and
+	// "*.sub"
 	return name
 }
 
-// validateTTL ensures TTL is within acceptable range
-func validateTTL(ttl time.Duration) int {
-	seconds := int(ttl.Seconds())
-	if seconds < minTTL {
-		return defaultTTL
+// relativeRecord returns record with its name relative to zone, as libdns
+// expects from GetRecords. NameSilo lists fully qualified hosts.
+func relativeRecord(zone string, record libdns.Record) libdns.Record {
+	return renameRecord(record, func(name string) string {
+		return normalizeRecordName(name, zone)
+	})
+}
+
+// renameRecord returns record with its name replaced by rename(name),
+// keeping its type and NameSilo record ID. For SRV records only the owner
+// name after the _service._proto labels is renamed.
+func renameRecord(record libdns.Record, rename func(string) string) libdns.Record {
+	switch r := record.(type) {
+	case namesileoRecord:
+		r.Record = renameRecord(r.Record, rename)
+		return r
+	case libdns.RR:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.MX:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.TXT:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.CNAME:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.NS:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.SRV:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.CAA:
+		r.Name = rename(r.Name)
+		return r
+	case libdns.Address:
+		r.Name = rename(r.Name)
+		return r
+	case TLSA:
+		r.Name = rename(r.Name)
+		return r
+	}
+	rr := record.RR()
+	rr.Name = rename(rr.Name)
+	return rr
+}
+
+// hasZoneSuffix reports whether name is a subdomain of zone
+func hasZoneSuffix(name, zone string) bool {
+	return len(name) > len(zone)+1 && strings.EqualFold(name[len(name)-len(zone)-1:], "."+zone)
+}
+
+// validateRecordName checks the wildcard syntax NameSilo accepts: "*" may
+// only appear as the complete leftmost label, as in "*" or "*.sub"
+func validateRecordName(name string) error {
+	name = strings.TrimSuffix(name, ".")
+	if !strings.Contains(name, "*") {
+		return nil
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if !strings.Contains(label, "*") {
+			continue
+		}
+		if label != "*" {
+			return fmt.Errorf("invalid wildcard name %q: \"*\" must be a complete label", name)
+		}
+		if i != 0 {
+			return fmt.Errorf("invalid wildcard name %q: \"*\" is only allowed as the leftmost label", name)
+		}
+	}
+	return nil
+}
+
+// recordParams builds the rrhost/rrvalue/rrttl/rrdistance parameters shared by
+// dnsAddRecord and dnsUpdateRecord
+func (p *Provider) recordParams(zone string, record libdns.Record) map[string]string {
+	rr := record.RR()
+	value, priority := extractRecordData(record)
+
+	params := map[string]string{
+		"domain":  strings.TrimSuffix(zone, "."),
+		"rrhost":  normalizeRecordName(rr.Name, zone),
+		"rrvalue": value,
+		"rrttl":   strconv.Itoa(p.recordTTL(rr.TTL)),
+	}
+
+	// MX and SRV records always carry a distance/priority; zero is a valid
+	// value that NameSilo would otherwise replace with its default
+	if usesDistance(rr.Type) {
+		params["rrdistance"] = strconv.Itoa(priority)
+	}
+
+	return params
+}
+
+// validateRecord checks a record before it is sent to NameSilo
+func validateRecord(record libdns.Record) error {
+	if nsRec, ok := record.(namesileoRecord); ok {
+		record = nsRec.Record
+	}
+	if err := validateRecordName(record.RR().Name); err != nil {
+		return err
+	}
+	if rr, ok := record.(libdns.RR); ok && strings.EqualFold(rr.Type, "SRV") {
+		parsed, err := rr.Parse()
+		if err != nil {
+			return fmt.Errorf("SRV record %q: %w", rr.Name, err)
+		}
+		record = parsed
+	}
+	if rr, ok := record.(libdns.RR); ok && strings.EqualFold(rr.Type, "TLSA") {
+		_, err := parseTLSA(rr.Name, rr.TTL, rr.Data)
+		return err
+	}
+
+	switch r := record.(type) {
+	case libdns.SRV:
+		return validateSRV(r)
+	case TLSA:
+		return validateTLSA(r)
+	}
+	return nil
+}
+
+// validateRecords validates all records before any change is made
+func (p *Provider) validateRecords(records []libdns.Record) error {
+	for _, record := range records {
+		if err := validateRecord(record); err != nil {
+			return err
+		}
+		if err := p.checkTTL(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// usesDistance reports whether records of the type have a distance/priority
+func usesDistance(recordType string) bool {
+	switch strings.ToUpper(recordType) {
+	case "MX", "SRV":
+		return true
 	}
-	return seconds
+	return false
 }
 
 // extractRecordData extracts specific record data based on type
@@ -118,13 +641,31 @@ func extractRecordData(rec libdns.Record) (string, int) {
 	var priority int
 	var value string
 
+	// Look through the wrapper of records returned by GetRecords, and parse
+	// generic RRs so that e.g. an MX given as libdns.RR is handled as one
+	if nsRec, ok := rec.(namesileoRecord); ok {
+		rec = nsRec.Record
+	}
+	if rr, ok := rec.(libdns.RR); ok && (usesDistance(rr.Type) || strings.EqualFold(rr.Type, "TXT")) {
+		if parsed, err := rr.Parse(); err == nil {
+			rec = parsed
+		}
+	}
+	if rr, ok := rec.(libdns.RR); ok && strings.EqualFold(rr.Type, "TLSA") {
+		if parsed, err := parseTLSA(rr.Name, rr.TTL, rr.Data); err == nil {
+			rec = parsed
+		}
+	}
+
 	switch r := rec.(type) {
 	case libdns.MX:
 		priority = int(r.Preference)
 		value = r.Target
 	case libdns.SRV:
 		priority = int(r.Priority)
-		value = fmt.Sprintf("%d %d %s", r.Weight, r.Port, r.Target)
+		value = srvValue(r)
+	case libdns.TXT:
+		value = txtValue(r.Text)
 	default:
 		// For most record types, get the data from RR()
 		rr := rec.RR()
@@ -145,376 +686,575 @@ func (r namesileoRecord) RR() libdns.RR {
 	return r.Record.RR()
 }
 
-// createLibDNSRecord creates appropriate libdns.Record from NameSilo response
-func createLibDNSRecord(nsRecord dnsRecord) libdns.Record {
-	var baseRecord libdns.Record
-
-	switch strings.ToUpper(nsRecord.Type) {
-	case "A", "AAAA":
-		baseRecord = libdns.RR{
-			Name: nsRecord.Host,
-			Type: nsRecord.Type,
-			Data: nsRecord.Value,
-			TTL:  time.Duration(nsRecord.TTL) * time.Second,
-		}
-	case "MX":
-		baseRecord = libdns.MX{
-			Name:       nsRecord.Host,
-			TTL:        time.Duration(nsRecord.TTL) * time.Second,
-			Preference: uint16(nsRecord.Distance),
-			Target:     nsRecord.Value,
-		}
-	case "TXT":
-		baseRecord = libdns.TXT{
-			Name: nsRecord.Host,
-			TTL:  time.Duration(nsRecord.TTL) * time.Second,
-			Text: nsRecord.Value,
-		}
-	case "CNAME":
-		baseRecord = libdns.CNAME{
-			Name:   nsRecord.Host,
-			TTL:    time.Duration(nsRecord.TTL) * time.Second,
-			Target: nsRecord.Value,
-		}
-	case "NS":
-		baseRecord = libdns.NS{
-			Name:   nsRecord.Host,
-			TTL:    time.Duration(nsRecord.TTL) * time.Second,
-			Target: nsRecord.Value,
-		}
-	case "SRV":
-		// Parse SRV data: "weight port target"
-		parts := strings.Fields(nsRecord.Value)
-		if len(parts) >= 3 {
-			weight, err := strconv.ParseUint(parts[0], 10, 16)
-			if err != nil {
-				weight = 0 // Default weight if parsing fails
-			}
-			port, err := strconv.ParseUint(parts[1], 10, 16)
-			if err != nil {
-				// If port parsing fails, fall back to generic RR
-				baseRecord = libdns.RR{
-					Name: nsRecord.Host,
-					Type: nsRecord.Type,
-					Data: nsRecord.Value,
-					TTL:  time.Duration(nsRecord.TTL) * time.Second,
-				}
-			} else {
-				target := strings.Join(parts[2:], " ")
-				baseRecord = libdns.SRV{
-					Name:     nsRecord.Host,
-					TTL:      time.Duration(nsRecord.TTL) * time.Second,
-					Priority: uint16(nsRecord.Distance),
-					Weight:   uint16(weight),
-					Port:     uint16(port),
-					Target:   target,
-				}
-			}
-		} else {
-			baseRecord = libdns.RR{
-				Name: nsRecord.Host,
-				Type: nsRecord.Type,
-				Data: nsRecord.Value,
-				TTL:  time.Duration(nsRecord.TTL) * time.Second,
-			}
-		}
-	default:
-		// Generic RR for unsupported types
-		baseRecord = libdns.RR{
-			Name: nsRecord.Host,
-			Type: nsRecord.Type,
-			Data: nsRecord.Value,
-			TTL:  time.Duration(nsRecord.TTL) * time.Second,
-		}
+// withRecordID wraps a record with a NameSilo record ID
+func withRecordID(record libdns.Record, id string) libdns.Record {
+	if nsRec, ok := record.(namesileoRecord); ok {
+		record = nsRec.Record
+	}
+	return namesileoRecord{Record: record, ID: id}
+}
+
+// RecordID returns the NameSilo record ID of a record returned by this
+// package, such as from GetRecords or AppendRecords. It reports false for
+// records that did not come from NameSilo.
+func RecordID(record libdns.Record) (string, bool) {
+	nsRec, ok := record.(namesileoRecord)
+	if !ok || nsRec.ID == "" {
+		return "", false
+	}
+	return nsRec.ID, true
+}
+
+// createLibDNSRecord creates appropriate libdns.Record from NameSilo response.
+// Records with malformed data are returned as generic RRs along with an
+// error describing the problem.
+func createLibDNSRecord(nsRecord dnsRecord) (libdns.Record, error) {
+	baseRecord, err := parseRecord(nsRecord)
+	if err != nil {
+		// Keep malformed data available as a generic RR
+		baseRecord = genericRecord(nsRecord)
 	}
 
 	// Wrap with NameSilo-specific data
 	return namesileoRecord{
 		Record: baseRecord,
 		ID:     nsRecord.ID,
-	}
+	}, err
 }
 
-// GetRecords lists all the records in the zone.
+// GetRecords lists all the records in the zone. Record names are relative
+// to the zone, with "@" for the zone apex.
 func (p *Provider) GetRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
-	if p.APIToken == "" {
+	return p.withSubzone(ctx, zone, nil, func(ctx context.Context, zone string, _ []libdns.Record) ([]libdns.Record, error) {
+		return p.getRecords(ctx, zone)
+	})
+}
+
+// getRecords implements GetRecords for a registered domain
+func (p *Provider) getRecords(ctx context.Context, zone string) ([]libdns.Record, error) {
+	if !p.hasCredentials() {
 		return nil, fmt.Errorf("API token is required")
 	}
 
 	domain := strings.TrimSuffix(zone, ".")
-	params := map[string]string{
-		"domain": domain,
+	if records, ok := p.cachedRecords(domain); ok {
+		return records, nil
 	}
 
-	apiURL, err := p.buildAPIURL("dnsListRecords", params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build API URL: %w", err)
-	}
+	return p.sharedFetch(ctx, domain, func() ([]libdns.Record, error) {
+		params := map[string]string{
+			"domain": domain,
+		}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+		var response dnsListResponse
+		err := p.withZoneAttached(ctx, zone, func() error {
+			return p.callAPI(ctx, "dnsListRecords", params, &response)
+		})
+		if err != nil {
+			return nil, err
+		}
 
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+		if p.Logger != nil {
+			for _, err := range response.Malformed {
+				p.Logger.WarnContext(ctx, "namesilo returned a malformed record", "zone", domain, "error", err)
+			}
+		}
+		records := response.Records
+		for i, record := range records {
+			records[i] = relativeRecord(domain, record)
+		}
+		return records, nil
+	})
+}
 
-	var response dnsListResponse
-	if err := p.doHTTPRequest(client, req, &response); err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
+// AppendRecords adds records to the zone. It returns the records that were
+// added; use RecordID to get their NameSilo record IDs. If a record fails,
+// the records added before it remain unless AtomicAppend is set.
+func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	return p.withSubzone(ctx, zone, records, func(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+		return p.withChallengeDelegation(ctx, zone, records, p.appendRecords)
+	})
+}
 
-	if response.Code != 300 {
-		return nil, fmt.Errorf("API error for zone %q: code %d - %s", zone, response.Code, response.Detail)
+// appendRecords implements AppendRecords for a single zone
+func (p *Provider) appendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	if !p.hasCredentials() {
+		return nil, fmt.Errorf("API token is required")
 	}
 
-	var records []libdns.Record
-	for _, record := range response.Records {
-		rec := createLibDNSRecord(record)
-		records = append(records, rec)
+	records, err := p.apexAliases(zone, records)
+	if err != nil {
+		return nil, err
 	}
-
-	return records, nil
-}
-
-// AppendRecords adds records to the zone. It returns the records that were added.
-func (p *Provider) AppendRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	if p.APIToken == "" {
-		return nil, fmt.Errorf("API token is required")
+	if err := p.validateRecords(records); err != nil {
+		return nil, err
 	}
 
-	domain := strings.TrimSuffix(zone, ".")
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+	var existing *recordIndex
+	if p.SkipExisting || p.RejectDuplicates {
+		existingRecords, err := p.getRecords(ctx, zone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
+		}
+		existing = p.newRecordIndex(zone, existingRecords)
+	}
+	if existing != nil && !p.SkipExisting {
+		// Fail before making any change
+		for _, record := range records {
+			if existing.contains(record) {
+				rr := p.NormalizeRecord(zone, record)
+				return nil, fmt.Errorf("%w: %s %s %q", ErrDuplicate, rr.Name, rr.Type, rr.Data)
+			}
+		}
 	}
 
 	var appendedRecords []libdns.Record
 
 	for _, record := range records {
-		rr := record.RR()
-		normalizedName := normalizeRecordName(rr.Name, zone)
-		ttl := validateTTL(rr.TTL)
-		value, priority := extractRecordData(record)
-
-		params := map[string]string{
-			"domain":  domain,
-			"rrtype":  rr.Type,
-			"rrhost":  normalizedName,
-			"rrvalue": value,
-			"rrttl":   fmt.Sprintf("%d", ttl),
+		if existing != nil && existing.contains(record) {
+			continue
 		}
-
-		// Add distance/priority for MX/SRV records
-		if priority > 0 {
-			params["rrdistance"] = fmt.Sprintf("%d", priority)
+		if err := ctx.Err(); err != nil {
+			if p.AtomicAppend && len(appendedRecords) > 0 {
+				return nil, p.rollbackAppend(ctx, zone, appendedRecords, err)
+			}
+			return appendedRecords, err
 		}
 
-		apiURL, err := p.buildAPIURL("dnsAddRecord", params)
+		// Only the first failure can be caused by a detached zone
+		added, err := p.addRecord(ctx, zone, record, len(appendedRecords) == 0)
 		if err != nil {
-			return appendedRecords, fmt.Errorf("failed to build API URL: %w", err)
+			if p.AtomicAppend && len(appendedRecords) > 0 {
+				return nil, p.rollbackAppend(ctx, zone, appendedRecords, err)
+			}
+			return appendedRecords, err
 		}
+		appendedRecords = append(appendedRecords, added)
+	}
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-		if err != nil {
-			return appendedRecords, fmt.Errorf("failed to create request: %w", err)
-		}
+	return appendedRecords, nil
+}
 
-		var response dnsAddResponse
-		if err := p.doHTTPRequest(client, req, &response); err != nil {
-			return appendedRecords, fmt.Errorf("request failed: %w", err)
-		}
+// addRecord creates a single record, attaching the zone first if attach is
+// set and the zone turns out to be detached. It returns the record that was
+// passed in, carrying the new record ID so that RecordID works on it.
+func (p *Provider) addRecord(ctx context.Context, zone string, record libdns.Record, attach bool) (libdns.Record, error) {
+	params := p.recordParams(zone, record)
+	params["rrtype"] = apiRecordType(record)
 
-		if response.Code != 300 {
-			return appendedRecords, fmt.Errorf("failed to add record for zone %q: code %d - %s", zone, response.Code, response.Detail)
-		}
+	var response dnsAddResponse
+	add := func() error {
+		return p.callAPI(ctx, "dnsAddRecord", params, &response)
+	}
 
-		// Return the same record type that was passed in
-		appendedRecords = append(appendedRecords, record)
+	var err error
+	if attach {
+		err = p.withZoneAttached(ctx, zone, add)
+	} else {
+		err = add()
+	}
+	if err != nil {
+		p.audit(ctx, zone, SyncCreate, "", nil, record, err)
+		return nil, err
 	}
 
-	return appendedRecords, nil
+	added := withRecordID(record, response.RecordID)
+	p.audit(ctx, zone, SyncCreate, response.RecordID, nil, added, nil)
+	return added, nil
 }
 
 // SetRecords sets the records in the zone, either by updating existing records or creating new ones.
-// It returns the updated records.
+// For each (name, type) pair in the input, the existing RRset is replaced by exactly the
+// provided records; records of other names and types are left untouched.
+// It returns the updated records. If a change fails, the changes already made
+// are undone before the error is returned.
 func (p *Provider) SetRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	if p.APIToken == "" {
+	return p.withSubzone(ctx, zone, records, func(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+		return p.withChallengeDelegation(ctx, zone, records, p.setRecords)
+	})
+}
+
+// setRecords implements SetRecords for a single zone
+func (p *Provider) setRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	if !p.hasCredentials() {
 		return nil, fmt.Errorf("API token is required")
 	}
 
-	existingRecords, err := p.GetRecords(ctx, zone)
+	records, err := p.apexAliases(zone, records)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.validateRecords(records); err != nil {
+		return nil, err
+	}
+
+	existingRecords, err := p.getRecords(ctx, zone)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
 	}
 
-	// Create map of existing records by name+type for lookup
-	existingMap := make(map[string]libdns.Record)
+	// Group existing and desired records into RRsets by name+type
+	existingSets := make(map[string][]namesileoRecord)
 	for _, rec := range existingRecords {
-		rr := rec.RR()
-		key := rr.Name + ":" + rr.Type
-		existingMap[key] = rec
+		if nsRec, ok := rec.(namesileoRecord); ok {
+			key := rrsetKey(zone, rec)
+			existingSets[key] = append(existingSets[key], nsRec)
+		}
 	}
 
-	var resultRecords []libdns.Record
-
-	// For each input record, either update existing or create new
+	var keys []string
+	desiredSets := make(map[string][]libdns.Record)
 	for _, record := range records {
-		rr := record.RR()
-		key := rr.Name + ":" + rr.Type
-
-		if _, exists := existingMap[key]; exists {
-			// Update existing record via delete + add
-			// First delete the existing record
-			if err := p.deleteRecordByNameType(ctx, zone, rr.Name, rr.Type); err != nil {
-				return resultRecords, fmt.Errorf("failed to delete existing record: %w", err)
-			}
+		key := rrsetKey(zone, record)
+		if _, seen := desiredSets[key]; !seen {
+			keys = append(keys, key)
 		}
+		desiredSets[key] = append(desiredSets[key], record)
+	}
+
+	var resultRecords []libdns.Record
+	var changes []SyncChange
+	for _, key := range keys {
+		setChanges, unchanged := p.diffRRset(zone, existingSets[key], desiredSets[key], true)
+		resultRecords = append(resultRecords, unchanged...)
+		changes = append(changes, setChanges...)
+	}
+
+	applied, err := p.applyAll(ctx, zone, changes)
+	if err != nil {
+		return nil, err
+	}
+	return append(resultRecords, applied...), nil
+}
+
+// applyAll applies changes in order and returns the created and updated
+// records. If a change fails or ctx is cancelled, the changes applied so far
+// are rolled back so that no RRset is left half replaced.
+func (p *Provider) applyAll(ctx context.Context, zone string, changes []SyncChange) ([]libdns.Record, error) {
+	var results []libdns.Record
+	var applied []SyncChange
 
-		// Add the new record
-		addedRecords, err := p.AppendRecords(ctx, zone, []libdns.Record{record})
+	for _, change := range changes {
+		if err := ctx.Err(); err != nil {
+			return nil, p.rollbackChanges(ctx, zone, applied, err)
+		}
+		change, err := p.applyChange(ctx, zone, change)
 		if err != nil {
-			return resultRecords, fmt.Errorf("failed to add record: %w", err)
+			return nil, p.rollbackChanges(ctx, zone, applied, err)
+		}
+		applied = append(applied, change)
+		if change.Action != SyncDelete {
+			results = append(results, change.Record)
 		}
-
-		resultRecords = append(resultRecords, addedRecords...)
 	}
 
-	return resultRecords, nil
+	return results, nil
+}
+
+// rrsetKey identifies the RRset a record belongs to, independent of whether
+// its name is relative or fully qualified
+func rrsetKey(zone string, record libdns.Record) string {
+	return strings.ToLower(normalizeRecordName(record.RR().Name, zone)) + ":" + apiRecordType(record)
+}
+
+// indexOfSameData returns the index of the record in existing that has the same
+// normalized data as record, or -1
+func (p *Provider) indexOfSameData(zone string, existing []namesileoRecord, record libdns.Record) int {
+	for i, rec := range existing {
+		if p.sameData(zone, rec.Record, record) {
+			return i
+		}
+	}
+	return -1
 }
 
 // DeleteRecords deletes the records from the zone. It returns the records that were deleted.
+// The zone is listed once and all records are resolved against that listing.
+// As in libdns, a record with an empty type or value deletes all records
+// matching its other fields.
 func (p *Provider) DeleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
-	if p.APIToken == "" {
+	return p.withSubzone(ctx, zone, records, func(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+		return p.withChallengeDelegation(ctx, zone, records, p.deleteRecords)
+	})
+}
+
+// deleteRecords implements DeleteRecords for a single zone
+func (p *Provider) deleteRecords(ctx context.Context, zone string, records []libdns.Record) ([]libdns.Record, error) {
+	if !p.hasCredentials() {
 		return nil, fmt.Errorf("API token is required")
 	}
 
 	// Get existing records to find IDs
-	existingRecords, err := p.GetRecords(ctx, zone)
+	existingRecords, err := p.getRecords(ctx, zone)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
 	}
+	index := p.newRecordIndex(zone, existingRecords)
 
 	var deletedRecords []libdns.Record
 
 	for _, record := range records {
-		rr := record.RR()
-		recordID := p.findRecordID(existingRecords, rr.Name, rr.Type, rr.Data)
-
-		if recordID == "" {
-			// Record not found, skip silently as per libdns spec
-			continue
+		// Records not in the zone are skipped silently as per libdns spec
+		for _, existing := range index.match(record) {
+			if err := ctx.Err(); err != nil {
+				return deletedRecords, err
+			}
+			if err := p.deleteRecordByID(ctx, zone, existing.ID, existing); err != nil {
+				return deletedRecords, fmt.Errorf("failed to delete record: %w", err)
+			}
+			deletedRecords = append(deletedRecords, existing)
 		}
+	}
 
-		if err := p.deleteRecordByID(ctx, zone, recordID); err != nil {
-			return deletedRecords, fmt.Errorf("failed to delete record: %w", err)
-		}
+	return deletedRecords, nil
+}
+
+// updateRecord replaces the record with the given ID, currently before, by
+// record. It returns the ID of the updated record.
+func (p *Provider) updateRecord(ctx context.Context, zone, recordID string, before, record libdns.Record) (string, error) {
+	params := p.recordParams(zone, record)
+	params["rrid"] = recordID
 
-		deletedRecords = append(deletedRecords, record)
+	var response dnsUpdateResponse
+	if err := p.callAPI(ctx, "dnsUpdateRecord", params, &response); err != nil {
+		p.audit(ctx, zone, SyncUpdate, recordID, before, record, err)
+		return "", err
 	}
 
-	return deletedRecords, nil
+	// NameSilo may assign a new ID to the updated record
+	newID := recordID
+	if response.RecordID != "" {
+		newID = response.RecordID
+	}
+	p.audit(ctx, zone, SyncUpdate, newID, before, withRecordID(record, newID), nil)
+	return newID, nil
 }
 
-// Helper method to delete a record by name and type
-func (p *Provider) deleteRecordByNameType(ctx context.Context, zone, name, recordType string) error {
-	existingRecords, err := p.GetRecords(ctx, zone)
+// DeleteRecordByID deletes the record with the given NameSilo record ID
+// from the zone without listing the zone first, unless the zone lies below
+// a registered domain and the record has to be checked to be inside it.
+// Record IDs are returned by RecordID for records from GetRecords or
+// AppendRecords.
+func (p *Provider) DeleteRecordByID(ctx context.Context, zone, recordID string) error {
+	if recordID == "" {
+		return fmt.Errorf("record ID is required")
+	}
+
+	sub, err := p.resolveZone(ctx, zone)
 	if err != nil {
 		return err
 	}
-
-	recordID := p.findRecordIDByNameType(existingRecords, name, recordType)
-	if recordID == "" {
-		return fmt.Errorf("record not found: %s %s", name, recordType)
+	if sub != nil {
+		if err := p.checkRecordInZone(ctx, sub, recordID); err != nil {
+			return err
+		}
+		zone = sub.domain
 	}
 
-	return p.deleteRecordByID(ctx, zone, recordID)
+	return p.deleteRecordByID(ctx, zone, recordID, nil)
 }
 
-// Helper method to delete a record by ID
-func (p *Provider) deleteRecordByID(ctx context.Context, zone, recordID string) error {
-	domain := strings.TrimSuffix(zone, ".")
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
+// deleteRecordByID implements DeleteRecordByID for a registered domain.
+// before is the record being deleted, if known, for auditing.
+func (p *Provider) deleteRecordByID(ctx context.Context, zone, recordID string, before libdns.Record) error {
 	params := map[string]string{
-		"domain": domain,
+		"domain": strings.TrimSuffix(zone, "."),
 		"rrid":   recordID,
 	}
 
-	apiURL, err := p.buildAPIURL("dnsDeleteRecord", params)
+	var response apiResponse
+	err := p.callAPI(ctx, "dnsDeleteRecord", params, &response)
+	p.audit(ctx, zone, SyncDelete, recordID, before, nil, err)
+	return err
+}
+
+// callAPI performs a NameSilo API operation and decodes the reply into resp.
+// Unsuccessful reply codes are returned as *APIError.
+func (p *Provider) callAPI(ctx context.Context, operation string, params map[string]string, resp replyCoder) error {
+	return p.callAPIFor(ctx, params["domain"], operation, params, resp)
+}
+
+// callAPIFor is callAPI with the keys of the account that domain is routed
+// to, for operations such as listDomains that take no domain parameter
+func (p *Provider) callAPIFor(ctx context.Context, domain, operation string, params map[string]string, resp replyCoder) (err error) {
+	if !p.hasCredentials() {
+		return fmt.Errorf("API token is required")
+	}
+	tokens, routed, err := p.domainTokens(ctx, domain)
 	if err != nil {
-		return fmt.Errorf("failed to build API URL: %w", err)
+		return err
+	}
+
+	info := CallInfo{Operation: operation, Zone: domain}
+	ctx, span := p.startSpan(ctx, operation)
+	start := time.Now()
+	defer func() {
+		info.Duration = time.Since(start)
+		info.Err = err
+		p.observeCall(ctx, span, info)
+	}()
+
+	if mutatingOperations[operation] {
+		// Invalidate even if the request fails, since the outcome is unknown
+		defer p.invalidateZone(domain)
+	}
+
+	// Key rotation applies to the Provider's own keys; the keys of an
+	// account are tried in order
+	first := 0
+	if !routed {
+		first = p.selectKey(len(tokens))
 	}
+	for i := 0; ; i++ {
+		key := (first + i) % len(tokens)
+		err = p.callWithToken(ctx, operation, tokens[key], params, resp, &info)
+		if i+1 >= len(tokens) || !isKeyFailure(err) {
+			return err
+		}
+		// The key was refused, so the operation was not performed and is
+		// safe to repeat with the next key
+		if !routed {
+			p.keyFailed(key, len(tokens))
+		}
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+// callWithToken performs an API operation with the given API key
+func (p *Provider) callWithToken(ctx context.Context, operation, token string, params map[string]string, resp replyCoder, info *CallInfo) error {
+	method := p.requestMethod(operation)
+	req, err := p.newAPIRequest(ctx, operation, token, params, method)
 	if err != nil {
-		return fmt.Errorf("failed to create delete request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	var response apiResponse
-	if err := p.doHTTPRequest(client, req, &response); err != nil {
-		return fmt.Errorf("delete request failed: %w", err)
+	retries, err := p.doHTTPRequest(p.httpClient(), req, resp)
+	info.Retries += retries
+	if method == http.MethodPost && p.postRejected(operation, err, resp) {
+		// The operation was not performed, so it is safe to repeat with GET
+		if req, err = p.newAPIRequest(ctx, operation, token, params, http.MethodGet); err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		retries, err = p.doHTTPRequest(p.httpClient(), req, resp)
+		info.Retries += retries
+	}
+	if err != nil {
+		return &RequestError{
+			Operation: operation,
+			Domain:    params["domain"],
+			Params:    p.errorParams(params),
+			Response:  p.capturedResponse(resp, err),
+			Err:       p.redactError(err),
+		}
 	}
 
-	if response.Code != 300 {
-		return fmt.Errorf("failed to delete record for zone %q: code %d - %s", zone, response.Code, response.Detail)
+	info.ReplyCode = resp.replyCode()
+	if !isSuccessCode(info.ReplyCode) {
+		return &APIError{
+			Operation: operation,
+			Domain:    params["domain"],
+			Code:      info.ReplyCode,
+			Detail:    resp.replyDetail(),
+			Params:    p.errorParams(params),
+			Response:  p.capturedResponse(resp, nil),
+		}
+	}
+	if info.ReplyCode != codeSuccess {
+		p.warn(ctx, Warning{
+			Operation: operation,
+			Domain:    params["domain"],
+			Code:      info.ReplyCode,
+			Detail:    resp.replyDetail(),
+		})
 	}
 
 	return nil
 }
 
-// Helper method to find record ID by exact match
-func (p *Provider) findRecordID(records []libdns.Record, name, recordType, data string) string {
-	for _, rec := range records {
-		rr := rec.RR()
-		if rr.Name == name && rr.Type == recordType && rr.Data == data {
-			// Extract ID from the NameSilo record wrapper
-			if nsRec, ok := rec.(namesileoRecord); ok {
-				return nsRec.ID
-			}
-		}
-	}
-	return ""
+// httpStatusError is returned when the API responds with a non-200 HTTP status
+type httpStatusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // from the Retry-After header, if any
 }
 
-// Helper method to find record ID by name and type (first match)
-func (p *Provider) findRecordIDByNameType(records []libdns.Record, name, recordType string) string {
-	for _, rec := range records {
-		rr := rec.RR()
-		if rr.Name == name && rr.Type == recordType {
-			// Extract ID from the NameSilo record wrapper
-			if nsRec, ok := rec.(namesileoRecord); ok {
-				return nsRec.ID
-			}
-		}
-	}
-	return ""
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d: %s", e.StatusCode, e.Body)
 }
 
-// doHTTPRequest performs an HTTP request and unmarshals the XML response
-func (p *Provider) doHTTPRequest(client *http.Client, req *http.Request, resp interface{}) error {
+// doHTTPRequestOnce performs a single HTTP request and unmarshals the XML response
+func (p *Provider) doHTTPRequestOnce(client *http.Client, req *http.Request, resp interface{}) error {
+	start := time.Now()
 	response, err := client.Do(req)
 	if err != nil {
+		err = p.redactError(err)
+		p.debugDump(req, 0, []byte(err.Error()), time.Since(start))
 		return fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer response.Body.Close()
 
+	contentType := response.Header.Get("Content-Type")
 	if response.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(response.Body)
-		return fmt.Errorf("unexpected HTTP status %d: %s", response.StatusCode, string(respBody))
+		respBody, _ := io.ReadAll(io.LimitReader(response.Body, p.maxResponseSize()))
+		p.debugDump(req, response.StatusCode, respBody, time.Since(start))
+		statusErr := &httpStatusError{
+			StatusCode: response.StatusCode,
+			Body:       p.redact(string(respBody)),
+			RetryAfter: parseRetryAfter(response.Header.Get("Retry-After"), time.Now()),
+		}
+		if unexpectedContent(p.responseFormat(), respBody) {
+			return &ContentTypeError{
+				StatusCode:  response.StatusCode,
+				ContentType: contentType,
+				Snippet:     p.redact(bodySnippet(respBody)),
+				err:         statusErr,
+			}
+		}
+		return statusErr
 	}
 
-	result, err := io.ReadAll(response.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+	// Look at the start of the body to catch error pages before decoding
+	buffered := bufio.NewReaderSize(newLimitedBody(response.Body, p.maxResponseSize()), contentSniffLength)
+	if prefix, _ := buffered.Peek(contentSniffLength); unexpectedContent(p.responseFormat(), prefix) {
+		page, _ := io.ReadAll(io.LimitReader(buffered, maxErrorPageLength))
+		p.debugDump(req, response.StatusCode, page, time.Since(start))
+		return &ContentTypeError{
+			StatusCode:  response.StatusCode,
+			ContentType: contentType,
+			Snippet:     p.redact(bodySnippet(page)),
+		}
 	}
 
-	if err := xml.Unmarshal(result, resp); err != nil {
-		return fmt.Errorf("failed to unmarshal XML response: %w", err)
+	// Decode while reading instead of buffering the body, keeping a copy
+	// only when it has to be dumped
+	var body io.Reader = buffered
+	var raw bytes.Buffer
+	if p.DebugWriter != nil || p.StrictDecoding || p.CaptureResponses {
+		body = io.TeeReader(buffered, &raw)
 	}
 
+	if p.responseFormat() == FormatJSON {
+		err = decodeJSONResponse(body, resp)
+	} else {
+		err = decodeResponse(body, resp)
+	}
+	if p.DebugWriter != nil {
+		io.Copy(io.Discard, body)
+		p.debugDump(req, response.StatusCode, raw.Bytes(), time.Since(start))
+	}
+	if c, ok := resp.(rawCapturer); ok && p.CaptureResponses {
+		c.setRawResponse(raw.Bytes())
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal %s response: %w", strings.ToUpper(string(p.responseFormat())), err)
+	}
+
+	if sink := recordSinkFrom(req.Context()); sink != nil && sink.stopped {
+		// The rest of the reply was not read
+		return nil
+	}
+	if p.StrictDecoding {
+		return p.checkStrict(path.Base(req.URL.Path), raw.Bytes(), resp)
+	}
 	return nil
 }
 
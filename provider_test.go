@@ -2,11 +2,18 @@ package namesilo
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
 )
 
 var (
@@ -237,3 +244,426 @@ func TestErrorHandling(t *testing.T) {
 
 	t.Log("Error handling tests passed")
 }
+
+// rewriteTransport sends every request to a test server instead of the NameSilo API
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestProvider returns a Provider whose API calls are served by handler
+func newTestProvider(t *testing.T, handler http.HandlerFunc) *Provider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("failed to parse test server URL: %v", err)
+	}
+
+	return &Provider{
+		APIToken:   "test-token",
+		HTTPClient: &http.Client{Transport: rewriteTransport{target: target}},
+	}
+}
+
+func TestHTTPClientOverride(t *testing.T) {
+	var requested string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>abc123</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>3600</ttl><distance>0</distance></resource_record>
+</reply></namesilo>`))
+	})
+
+	records, err := provider.GetRecords(context.Background(), "example.com.")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	if requested != "/api/dnsListRecords" {
+		t.Errorf("Expected request to /api/dnsListRecords, got %s", requested)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if rr := records[0].RR(); rr.Type != "A" || rr.Data != "192.0.2.1" {
+		t.Errorf("Unexpected record: %+v", rr)
+	}
+}
+
+func TestDefaultHTTPClientShared(t *testing.T) {
+	provider := &Provider{APIToken: "test-token"}
+
+	client := provider.httpClient()
+	if client.Timeout != defaultTimeout {
+		t.Errorf("Expected default timeout %v, got %v", defaultTimeout, client.Timeout)
+	}
+	if provider.httpClient() != client {
+		t.Error("Expected the default client to be reused across calls")
+	}
+	if (&Provider{}).httpClient() == client {
+		t.Error("Expected each Provider to have its own default client")
+	}
+}
+
+func TestSandboxEndpoint(t *testing.T) {
+	provider := Provider{APIToken: "test-token", Sandbox: true}
+
+	apiURL, err := provider.buildAPIURL("dnsListRecords", provider.APIToken, map[string]string{"domain": "example.com"})
+	if err != nil {
+		t.Fatalf("buildAPIURL failed: %v", err)
+	}
+
+	u, err := url.Parse(apiURL)
+	if err != nil {
+		t.Fatalf("Invalid URL %q: %v", apiURL, err)
+	}
+	if u.Host != "sandbox.namesilo.com" || u.Path != "/api/dnsListRecords" {
+		t.Errorf("Expected sandbox endpoint, got %s", apiURL)
+	}
+}
+
+func TestSetRecordsReplacesRRset(t *testing.T) {
+	var operations []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		op := strings.TrimPrefix(r.URL.Path, "/api/")
+		if op == "dnsListRecords" {
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>r1</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r2</record_id><type>A</type><host>www.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r3</record_id><type>A</type><host>www.example.com</host><value>192.0.2.3</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r4</record_id><type>TXT</type><host>www.example.com</host><value>keep me</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+			return
+		}
+		q := r.URL.Query()
+		operations = append(operations, op+" "+q.Get("rrid")+" "+q.Get("rrvalue"))
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	records := []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.9", TTL: time.Hour},
+	}
+
+	result, err := provider.SetRecords(context.Background(), "example.com.", records)
+	if err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if len(result) != 2 {
+		t.Errorf("Expected 2 records, got %d", len(result))
+	}
+
+	// 192.0.2.2 is kept as is, r1 is reused for the new value and r3 is removed;
+	// the TXT record of the same name is not touched.
+	want := []string{
+		"dnsUpdateRecord r1 192.0.2.9",
+		"dnsDeleteRecord r3 ",
+	}
+	if strings.Join(operations, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected operations:\n got: %v\nwant: %v", operations, want)
+	}
+}
+
+func TestSetRecordsListsZoneOnce(t *testing.T) {
+	calls := make(map[string]int)
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		op := strings.TrimPrefix(r.URL.Path, "/api/")
+		calls[op]++
+		if op == "dnsListRecords" {
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>r1</record_id><type>A</type><host>www.dev.example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r2</record_id><type>A</type><host>www.dev.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r3</record_id><type>TXT</type><host>dev.example.com</host><value>old</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r4</record_id><type>MX</type><host>dev.example.com</host><value>mx1.example.com</value><ttl>3600</ttl><distance>10</distance></resource_record>
+</reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><record_id>new</record_id></reply></namesilo>`))
+	})
+	provider.Subzones = map[string]string{"dev.example.com": "example.com"}
+
+	// Three RRsets are replaced, in a zone below a registered domain
+	_, err := provider.SetRecords(context.Background(), "dev.example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.9", TTL: time.Hour},
+		libdns.TXT{Name: "@", Text: "new", TTL: time.Hour},
+		libdns.MX{Name: "@", Preference: 20, Target: "mx2.example.com", TTL: time.Hour},
+		libdns.MX{Name: "@", Preference: 30, Target: "mx3.example.com", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+
+	// One listing, then one request per changed record: r1, r3 and r4 are
+	// updated in place, r2 is deleted and the second MX is added
+	want := map[string]int{"dnsListRecords": 1, "dnsUpdateRecord": 3, "dnsDeleteRecord": 1, "dnsAddRecord": 1}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("Unexpected requests %v, want %v", calls, want)
+	}
+}
+
+func TestProviderWithFakeServer(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "test-token"
+	srv.AddZone("example.com", namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600})
+
+	provider := &Provider{APIToken: "test-token", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token", TTL: 300 * time.Second},
+		libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	_, err = provider.SetRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+
+	records := srv.Records("example.com")
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %+v", records)
+	}
+	if txt := records[0]; txt.Type != "TXT" || txt.Host != "_acme-challenge.example.com" || txt.Value != "token" || txt.TTL != 300 {
+		t.Errorf("Unexpected TXT record: %+v", txt)
+	}
+	if mx := records[1]; mx.Type != "MX" || mx.Host != "example.com" || mx.Value != "mail.example.com" || mx.Distance != 10 {
+		t.Errorf("Unexpected MX record: %+v", mx)
+	}
+	if a := records[2]; a.Type != "A" || a.Host != "www.example.com" || a.Value != "192.0.2.2" {
+		t.Errorf("Unexpected A record: %+v", a)
+	}
+
+	provider.APIToken = "wrong"
+	if _, err := provider.GetRecords(ctx, "example.com."); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey, got %v", err)
+	}
+}
+
+func TestRecordID(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	added, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	id, ok := RecordID(added[0])
+	if !ok || id != srv.Records("example.com")[0].ID {
+		t.Errorf("RecordID = %q, %v; want ID of created record", id, ok)
+	}
+	if added[0].RR().Data != "token" {
+		t.Errorf("Unexpected appended record: %+v", added[0].RR())
+	}
+
+	records, err := provider.GetRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if got, ok := RecordID(records[0]); !ok || got != id {
+		t.Errorf("RecordID of listed record = %q, %v; want %q", got, ok, id)
+	}
+
+	if _, ok := RecordID(libdns.TXT{Name: "x", Text: "y"}); ok {
+		t.Error("RecordID should report false for records not from NameSilo")
+	}
+}
+
+func TestUsePOST(t *testing.T) {
+	var methods []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.URL.RawQuery != "" && r.Method == http.MethodPost {
+			t.Errorf("POST request has a query string: %s", r.URL.RawQuery)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Get("key") != "test-token" {
+			t.Errorf("Missing API key in %s request", r.Method)
+		}
+
+		if r.Method == http.MethodPost && r.URL.Path == "/api/listDomains" {
+			w.Write([]byte(`<namesilo><reply><code>120</code><detail>Invalid request method</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.UsePOST = true
+	provider.MaxRetries = 1
+	ctx := context.Background()
+
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if strings.Join(methods, ",") != "POST" {
+		t.Errorf("Expected a single POST request, got %v", methods)
+	}
+
+	// listDomains rejects POST: fall back to GET and remember it
+	methods = nil
+	for i := 0; i < 2; i++ {
+		if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+			t.Fatalf("ListDomains failed: %v", err)
+		}
+	}
+	if strings.Join(methods, ",") != "POST,GET,GET" {
+		t.Errorf("Unexpected request methods %v", methods)
+	}
+}
+
+func TestRecordParamsDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   libdns.Record
+		value    string
+		distance string // "" means no rrdistance parameter
+	}{
+		{"MX preference 0", libdns.MX{Name: "@", Preference: 0, Target: "mail.example.com"}, "mail.example.com", "0"},
+		{"MX preference 10", libdns.MX{Name: "@", Preference: 10, Target: "mail.example.com"}, "mail.example.com", "10"},
+		{"SRV priority 0", libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Priority: 0, Weight: 5, Port: 5060, Target: "sip.example.com"}, "5 5060 sip.example.com", "0"},
+		{"generic MX", libdns.RR{Name: "@", Type: "MX", Data: "0 mail.example.com"}, "mail.example.com", "0"},
+		{"wrapped MX", namesileoRecord{Record: libdns.MX{Name: "@", Preference: 20, Target: "mx2.example.com"}, ID: "1"}, "mx2.example.com", "20"},
+		{"A record", libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1"}, "192.0.2.1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := (&Provider{}).recordParams("example.com", tt.record)
+			if params["rrvalue"] != tt.value {
+				t.Errorf("Expected rrvalue %q, got %q", tt.value, params["rrvalue"])
+			}
+			distance, ok := params["rrdistance"]
+			if tt.distance == "" && ok {
+				t.Errorf("Expected no rrdistance, got %q", distance)
+			}
+			if tt.distance != "" && distance != tt.distance {
+				t.Errorf("Expected rrdistance %q, got %q (present: %v)", tt.distance, distance, ok)
+			}
+		})
+	}
+}
+
+func TestCancellationBetweenRecords(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var operations []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		op := strings.TrimPrefix(r.URL.Path, "/api/")
+		operations = append(operations, op)
+		switch op {
+		case "dnsListRecords":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>r1</record_id><type>TXT</type><host>a.example.com</host><value>one</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>r2</record_id><type>TXT</type><host>b.example.com</host><value>two</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><record_id>new</record_id></reply></namesilo>`))
+		}
+	})
+
+	// Cancel once the first change has completed
+	provider.Metrics = cancelAfterChange{&cancel}
+
+	records := []libdns.Record{
+		libdns.TXT{Name: "a", Text: "one"},
+		libdns.TXT{Name: "b", Text: "two"},
+	}
+
+	added, err := provider.AppendRecords(ctx, "example.com", records)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled from AppendRecords, got %v", err)
+	}
+	if len(added) != 1 || len(operations) != 1 {
+		t.Errorf("Expected one record added before cancellation, got %d records and operations %v", len(added), operations)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	operations = nil
+
+	deleted, err := provider.DeleteRecords(ctx, "example.com", records)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled from DeleteRecords, got %v", err)
+	}
+	if len(deleted) != 1 || len(operations) != 2 {
+		t.Errorf("Expected one record deleted before cancellation, got %d records and operations %v", len(deleted), operations)
+	}
+}
+
+// cancelAfterChange cancels a context when an API call that changes the
+// zone completes
+type cancelAfterChange struct {
+	cancel *context.CancelFunc
+}
+
+func (c cancelAfterChange) ObserveCall(info CallInfo) {
+	if mutatingOperations[info.Operation] {
+		(*c.cancel)()
+	}
+}
+
+func (c cancelAfterChange) ObserveThrottle(time.Duration) {}
+
+func TestGetRecordsRelativeNames(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "test-token"
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "", Value: "mail.example.com", Distance: 10, TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "_acme-challenge.sub", Value: "token", TTL: 3600},
+		namesilotest.Record{Type: "SRV", Host: "_sip._tcp", Value: "5 5060 sip.example.com", Distance: 10, TTL: 3600},
+	)
+
+	provider := &Provider{APIToken: "test-token", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	records, err := provider.GetRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+
+	var names []string
+	for _, rec := range records {
+		names = append(names, rec.RR().Name)
+	}
+	want := []string{"www", "@", "_acme-challenge.sub", "_sip._tcp"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("Expected relative names %v, got %v", want, names)
+	}
+
+	// Setting the listed records again, without their IDs, must not
+	// duplicate anything
+	var plain []libdns.Record
+	for _, rec := range records {
+		plain = append(plain, rec.RR())
+	}
+	if _, err := provider.SetRecords(ctx, "example.com.", plain); err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if got := srv.Records("example.com"); len(got) != len(records) {
+		t.Errorf("Expected %d records after round trip, got %+v", len(records), got)
+	}
+}
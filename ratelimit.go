@@ -0,0 +1,94 @@
+package namesilo
+
+import (
+	"context"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by all requests of a Provider
+type rateLimiter struct {
+	rate   float64 // tokens added per second
+	burst  float64 // bucket capacity
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// matches reports whether the limiter was built for rate and burst
+func (l *rateLimiter) matches(rate float64, burst int) bool {
+	if burst < 1 {
+		burst = 1
+	}
+	return l.rate == rate && l.burst == float64(burst)
+}
+
+// reserve takes a token if one is available, otherwise it returns how long
+// to wait before the next token is added. The caller must hold the
+// Provider's mutex.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// waitForRateLimit blocks until the rate limiter allows another request or
+// the context is done. It is a no-op when RequestsPerSecond is not set.
+// Time spent waiting is reported to the Metrics hook.
+func (p *Provider) waitForRateLimit(ctx context.Context) error {
+	var waited time.Duration
+	defer func() {
+		if waited > 0 && p.Metrics != nil {
+			p.Metrics.ObserveThrottle(waited)
+		}
+	}()
+
+	for {
+		p.mu.Lock()
+		if p.RequestsPerSecond <= 0 {
+			p.mu.Unlock()
+			return nil
+		}
+		if p.limiter == nil || !p.limiter.matches(p.RequestsPerSecond, p.Burst) {
+			// Changed settings, e.g. after a config reload, take effect
+			// with a new bucket
+			p.limiter = newRateLimiter(p.RequestsPerSecond, p.Burst)
+		}
+		wait := p.limiter.reserve(time.Now())
+		p.mu.Unlock()
+
+		if wait == 0 {
+			return nil
+		}
+
+		waitStart := time.Now()
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			waited += time.Since(waitStart)
+			return ctx.Err()
+		case <-timer.C:
+			waited += time.Since(waitStart)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package namesilo
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	start := time.Now()
+	limiter := newRateLimiter(2, 2)
+	limiter.last = start
+
+	// The initial burst is available immediately
+	for i := 0; i < 2; i++ {
+		if wait := limiter.reserve(start); wait != 0 {
+			t.Fatalf("Request %d: expected no wait, got %v", i+1, wait)
+		}
+	}
+
+	// The bucket is empty; one token is added every 500ms
+	if wait := limiter.reserve(start); wait != 500*time.Millisecond {
+		t.Errorf("Expected 500ms wait, got %v", wait)
+	}
+	if wait := limiter.reserve(start.Add(500 * time.Millisecond)); wait != 0 {
+		t.Errorf("Expected no wait after refill, got %v", wait)
+	}
+}
+
+func TestRateLimiterFollowsSettings(t *testing.T) {
+	provider := &Provider{RequestsPerSecond: 0.1, Burst: 1}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := provider.waitForRateLimit(ctx); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+
+	// The bucket is empty at the old rate; the new settings start afresh
+	provider.RequestsPerSecond = 100
+	provider.Burst = 2
+	for i := 0; i < 2; i++ {
+		if err := provider.waitForRateLimit(ctx); err != nil {
+			t.Fatalf("Request %d: expected the new settings to apply, got %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// Record is a DNS record in NameSilo's own terms, for programs that do not
+// work with libdns types. Host is relative to the zone, with "@" for the
+// apex. Value is the data as NameSilo stores it: the priority of MX and SRV
+// records is kept in Distance, and SRV values read "weight port target".
+type Record struct {
+	ID       string `json:"id,omitempty"`
+	Host     string `json:"host"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	TTL      int    `json:"ttl"` // seconds
+	Distance int    `json:"distance,omitempty"`
+}
+
+// RecordFromLibdns converts a libdns record of zone to a Record. The
+// NameSilo record ID is kept for records returned by this package.
+func RecordFromLibdns(zone string, record libdns.Record) Record {
+	rr := record.RR()
+	value, distance := extractRecordData(record)
+	id, _ := RecordID(record)
+
+	return Record{
+		ID:       id,
+		Host:     normalizeRecordName(rr.Name, zone),
+		Type:     apiRecordType(record),
+		Value:    value,
+		TTL:      int(rr.TTL / time.Second),
+		Distance: distance,
+	}
+}
+
+// RecordsFromLibdns converts libdns records of zone to Records.
+func RecordsFromLibdns(zone string, records []libdns.Record) []Record {
+	converted := make([]Record, 0, len(records))
+	for _, record := range records {
+		converted = append(converted, RecordFromLibdns(zone, record))
+	}
+	return converted
+}
+
+// ToLibdns converts the record to the matching libdns type, named relative
+// to zone, such as libdns.MX for MX records. Records with an ID can be
+// passed to DeleteRecords and DeleteRecordByID like records returned by
+// GetRecords. Malformed data is reported as an error along with a generic
+// libdns.RR.
+func (r Record) ToLibdns(zone string) (libdns.Record, error) {
+	if r.Type == "" {
+		return nil, fmt.Errorf("record %s: type is required", r.Host)
+	}
+	host := r.Host
+	if host == "" {
+		host = "@"
+	}
+
+	record, err := createLibDNSRecord(dnsRecord{
+		ID:       r.ID,
+		Type:     strings.ToUpper(r.Type),
+		Host:     host,
+		Value:    r.Value,
+		TTL:      r.TTL,
+		Distance: r.Distance,
+	})
+	record = relativeRecord(zone, record)
+	if r.ID == "" {
+		record = record.(namesileoRecord).Record
+	}
+	return record, err
+}
+
+// ListRecords lists the records of the zone as Records.
+func (p *Provider) ListRecords(ctx context.Context, zone string) ([]Record, error) {
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	return RecordsFromLibdns(zone, records), nil
+}
@@ -0,0 +1,124 @@
+package namesilo
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestRecordConversion(t *testing.T) {
+	longText := strings.Repeat("k", 300)
+
+	tests := []struct {
+		name   string
+		record libdns.Record
+		want   Record
+	}{
+		{
+			"A",
+			libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+			Record{Host: "www", Type: "A", Value: "192.0.2.1", TTL: 3600},
+		},
+		{
+			"MX at apex",
+			libdns.MX{Name: "@", TTL: time.Hour, Preference: 10, Target: "mail.example.com"},
+			Record{Host: "@", Type: "MX", Value: "mail.example.com", TTL: 3600, Distance: 10},
+		},
+		{
+			"long TXT",
+			libdns.TXT{Name: "s1._domainkey", TTL: time.Hour, Text: longText},
+			Record{Host: "s1._domainkey", Type: "TXT", Value: txtValue(longText), TTL: 3600},
+		},
+		{
+			"SRV",
+			libdns.SRV{Service: "sip", Transport: "tcp", Name: "voice", TTL: time.Hour, Priority: 5, Weight: 10, Port: 5060, Target: "sip.example.com"},
+			Record{Host: "_sip._tcp.voice", Type: "SRV", Value: "10 5060 sip.example.com", TTL: 3600, Distance: 5},
+		},
+		{
+			"ALIAS",
+			NewAlias("@", "lb.example.net", time.Hour),
+			Record{Host: "@", Type: "ALIAS", Value: "lb.example.net", TTL: 3600},
+		},
+		{
+			"with ID",
+			withRecordID(libdns.CNAME{Name: "blog", TTL: time.Hour, Target: "host.example.net"}, "abc"),
+			Record{ID: "abc", Host: "blog", Type: "CNAME", Value: "host.example.net", TTL: 3600},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RecordFromLibdns("example.com.", tt.record)
+			if got != tt.want {
+				t.Fatalf("RecordFromLibdns() = %+v, want %+v", got, tt.want)
+			}
+
+			back, err := got.ToLibdns("example.com.")
+			if err != nil {
+				t.Fatalf("ToLibdns failed: %v", err)
+			}
+			if back.RR() != tt.record.RR() {
+				t.Errorf("Round trip changed the record: %+v, want %+v", back.RR(), tt.record.RR())
+			}
+			if id, _ := RecordID(back); id != tt.want.ID {
+				t.Errorf("Expected ID %q, got %q", tt.want.ID, id)
+			}
+			if reflect.TypeOf(back) != reflect.TypeOf(tt.record) {
+				t.Errorf("Expected a %T, got %T", tt.record, back)
+			}
+		})
+	}
+}
+
+func TestRecordToLibdnsErrors(t *testing.T) {
+	if _, err := (Record{Host: "www", Value: "192.0.2.1"}).ToLibdns("example.com"); err == nil {
+		t.Error("Expected an error for a record without type")
+	}
+
+	record, err := Record{Host: "_sip._tcp", Type: "SRV", Value: "garbage", TTL: 3600}.ToLibdns("example.com")
+	if err == nil {
+		t.Error("Expected an error for a malformed SRV value")
+	}
+	if rr, ok := record.(libdns.RR); !ok || rr.Data != "garbage" {
+		t.Errorf("Expected a generic RR with the raw value, got %#v", record)
+	}
+}
+
+func TestListRecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "MX", Host: "example.com", Value: "mail.example.com", Distance: 10, TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "www.example.com", Value: "192.0.2.1", TTL: 7200},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+
+	records, err := provider.ListRecords(context.Background(), "example.com.")
+	if err != nil {
+		t.Fatalf("ListRecords failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %+v", records)
+	}
+	for _, record := range records {
+		if record.ID == "" {
+			t.Errorf("Expected a record ID: %+v", record)
+		}
+		record.ID = ""
+		switch record.Type {
+		case "MX":
+			if record != (Record{Host: "@", Type: "MX", Value: "mail.example.com", TTL: 3600, Distance: 10}) {
+				t.Errorf("Unexpected MX record %+v", record)
+			}
+		case "A":
+			if record != (Record{Host: "www", Type: "A", Value: "192.0.2.1", TTL: 7200}) {
+				t.Errorf("Unexpected A record %+v", record)
+			}
+		}
+	}
+}
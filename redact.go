@@ -0,0 +1,82 @@
+package namesilo
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// redactedToken replaces the API key in logs, errors and debug output
+const redactedToken = "REDACTED"
+
+// redact removes the API keys from s
+func (p *Provider) redact(s string) string {
+	for _, token := range p.knownTokens() {
+		s = strings.ReplaceAll(s, token, redactedToken)
+		if escaped := url.QueryEscape(token); escaped != token {
+			s = strings.ReplaceAll(s, escaped, redactedToken)
+		}
+	}
+	return s
+}
+
+// String describes the Provider with the API keys masked, so that printing
+// a Provider (e.g. in a configuration dump) does not leak them. Only
+// *Provider has this method, since a Provider must not be copied: print a
+// pointer, as formatting a Provider value with %v shows its fields.
+func (p *Provider) String() string {
+	return fmt.Sprintf("namesilo.Provider{APIToken: %q, APITokens: %d, Accounts: %v, Sandbox: %t}",
+		maskToken(p.APIToken), len(p.APITokens), p.Accounts, p.Sandbox)
+}
+
+// GoString is like String, for the %#v verb.
+func (p *Provider) GoString() string {
+	return p.String()
+}
+
+// String describes the account with its API keys masked.
+func (a Account) String() string {
+	return fmt.Sprintf("namesilo.Account{Zones: %q, APIToken: %q, APITokens: %d}", a.Zones, maskToken(a.APIToken), len(a.APITokens))
+}
+
+// GoString is like String, for the %#v verb.
+func (a Account) GoString() string {
+	return a.String()
+}
+
+// maskToken returns redactedToken for a configured key
+func maskToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	return redactedToken
+}
+
+// redactedError hides the API key in the message of a wrapped error
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError masks the API key in err. Request URLs in *url.Error values
+// are masked in place; any other error mentioning the key is wrapped so its
+// message is redacted while errors.Is and errors.As keep working.
+func (p *Provider) redactError(err error) error {
+	if err == nil {
+		return err
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = p.redact(urlErr.URL)
+	}
+
+	if msg := err.Error(); p.redact(msg) != msg {
+		return &redactedError{err: err, msg: p.redact(msg)}
+	}
+	return err
+}
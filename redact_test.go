@@ -0,0 +1,78 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// failingTransport fails every request like an unreachable host would
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestErrorsDoNotLeakToken(t *testing.T) {
+	provider := &Provider{
+		APIToken:   "secret-token-123",
+		HTTPClient: &http.Client{Transport: failingTransport{}},
+	}
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if strings.Contains(err.Error(), "secret-token-123") {
+		t.Errorf("Error leaks the API key: %v", err)
+	}
+	if !strings.Contains(err.Error(), "key="+redactedToken) {
+		t.Errorf("Expected masked key in error: %v", err)
+	}
+
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(urlErr.URL, "secret-token-123") {
+		t.Errorf("Expected a *url.Error with a masked URL, got %v", err)
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	provider := &Provider{APIToken: "secret"}
+	sentinel := errors.New("boom")
+
+	err := provider.redactError(fmt.Errorf("calling ?key=secret: %w", sentinel))
+	if err.Error() != "calling ?key="+redactedToken+": boom" {
+		t.Errorf("Unexpected message %q", err)
+	}
+	if !errors.Is(err, sentinel) {
+		t.Error("Expected redacted error to wrap the original")
+	}
+
+	if err := provider.redactError(sentinel); err != sentinel {
+		t.Errorf("Expected errors without the key to be returned as is, got %v", err)
+	}
+}
+
+func TestProviderString(t *testing.T) {
+	provider := &Provider{
+		APIToken: "secret-token-123",
+		Accounts: []Account{{Zones: []string{"example.org"}, APIToken: "account-secret", APITokens: []string{"account-extra"}}},
+	}
+	for _, format := range []string{"%v", "%+v", "%s", "%#v"} {
+		for _, value := range []any{provider, provider.Accounts, provider.Accounts[0]} {
+			out := fmt.Sprintf(format, value)
+			for _, secret := range []string{"secret-token-123", "account-secret", "account-extra"} {
+				if strings.Contains(out, secret) {
+					t.Errorf("%s of %T leaks an API key: %s", format, value, out)
+				}
+			}
+		}
+	}
+	if out := fmt.Sprint(provider); !strings.Contains(out, "example.org") {
+		t.Errorf("Expected the account zones in %s", out)
+	}
+}
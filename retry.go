@@ -0,0 +1,143 @@
+package namesilo
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = time.Second
+	maxRetryDelay         = 30 * time.Second
+)
+
+// retryableReplyCodes are NameSilo reply codes that indicate a temporary
+// condition on NameSilo's side
+var retryableReplyCodes = map[int]bool{
+	115: true, // Central Registry Not Responding - try again later
+}
+
+// replyCoder is implemented by every response type through apiResponse
+type replyCoder interface {
+	replyCode() int
+	replyDetail() string
+}
+
+// doHTTPRequest performs an HTTP request and unmarshals the XML response,
+// retrying transient failures with exponential backoff. It returns the number
+// of retries made.
+func (p *Provider) doHTTPRequest(client *http.Client, req *http.Request, resp interface{}) (int, error) {
+	ctx := req.Context()
+
+	// Transient failures and throttling are retried under separate limits,
+	// within an overall RetryBudget
+	var retries, throttles int
+	start := time.Now()
+	for attempt := 0; ; attempt++ {
+		// Reset the response so a retry does not append to decoded slices
+		reflect.ValueOf(resp).Elem().Set(reflect.Zero(reflect.TypeOf(resp).Elem()))
+		attachRecordSink(ctx, resp)
+
+		if err := p.waitForBackOff(ctx); err != nil {
+			return attempt, fmt.Errorf("throttled: %w", err)
+		}
+		if err := p.waitForRateLimit(ctx); err != nil {
+			return attempt, fmt.Errorf("rate limiter: %w", err)
+		}
+
+		attemptReq := req.Clone(ctx)
+		if req.GetBody != nil {
+			// Each attempt needs a fresh copy of a POST body
+			body, err := req.GetBody()
+			if err != nil {
+				return attempt, err
+			}
+			attemptReq.Body = body
+		}
+
+		// Admit the request only once nothing can fail before it is sent,
+		// so a trial request of the circuit breaker always records its
+		// outcome
+		trial, err := p.allowRequest()
+		if err != nil {
+			return attempt, err
+		}
+
+		err = p.doHTTPRequestOnce(client, attemptReq, resp)
+		p.recordOutcome(ctx, operationName(req), trial, err)
+		if ctx.Err() != nil {
+			return attempt, err
+		}
+		if sink := recordSinkFrom(ctx); sink != nil && sink.yielded {
+			// A retry would pass the same records on again
+			return attempt, err
+		}
+
+		class := p.classifyRetry(operationName(req), attempt, err, resp)
+		var delay time.Duration
+		throttled := false
+		switch {
+		case class == RetryThrottled && throttles < p.throttleRetries():
+			delay = p.throttleDelay(throttles, err)
+			throttles++
+			throttled = true
+		case class != RetryNever && retries < p.MaxRetries:
+			delay = p.jitter(p.retryDelay(retries))
+			retries++
+		default:
+			return attempt, err
+		}
+		if !p.withinRetryBudget(start, delay) {
+			return attempt, err
+		}
+		p.logRetry(ctx, operationName(req), attempt, delay, err, replyCodeOf(resp))
+
+		if throttled {
+			// Back off without sleeping here; the next attempt, like every
+			// other request of the Provider, waits in waitForBackOff
+			p.backOff(delay)
+			continue
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				return attempt, ctx.Err()
+			}
+			return attempt, err
+		case <-timer.C:
+		}
+	}
+}
+
+// operationName returns the API operation of a request URL
+func operationName(req *http.Request) string {
+	return path.Base(req.URL.Path)
+}
+
+// replyCodeOf returns the reply code of a decoded response, or 0
+func replyCodeOf(resp interface{}) int {
+	if rc, ok := resp.(replyCoder); ok {
+		return rc.replyCode()
+	}
+	return 0
+}
+
+// retryDelay returns the backoff delay before the given retry attempt
+func (p *Provider) retryDelay(attempt int) time.Duration {
+	delay := p.RetryBaseDelay
+	if delay <= 0 {
+		delay = defaultRetryBaseDelay
+	}
+	for i := 0; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay
+}
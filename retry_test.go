@@ -0,0 +1,140 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestRetryTransientFailures(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Write([]byte(`<namesilo><reply><code>115</code><detail>try again later</detail></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>TXT</type><host>example.com</host><value>hello</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+		}
+	})
+	provider.MaxRetries = 3
+	provider.RetryBaseDelay = time.Millisecond
+
+	records, err := provider.GetRecords(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	if len(records) != 1 {
+		t.Errorf("Expected 1 record after retries, got %d", len(records))
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err == nil {
+		t.Error("Expected error for HTTP 503")
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	provider := Provider{RetryBaseDelay: time.Second}
+
+	tests := map[int]time.Duration{
+		0:  time.Second,
+		1:  2 * time.Second,
+		3:  8 * time.Second,
+		10: maxRetryDelay,
+	}
+	for attempt, want := range tests {
+		if got := provider.retryDelay(attempt); got != want {
+			t.Errorf("retryDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestRetryResendsPOSTBody(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("domain") != "example.com" {
+			t.Errorf("Attempt %d: missing form body (%v)", attempts, err)
+		}
+		if attempts == 1 {
+			w.Write([]byte(`<namesilo><reply><code>115</code><detail>try again later</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.UsePOST = true
+	provider.MaxRetries = 1
+	provider.RetryBaseDelay = time.Millisecond
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryDoesNotResendMutations(t *testing.T) {
+	adds := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsAddRecord") {
+			adds++
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	provider.MaxRetries = 3
+	provider.RetryBaseDelay = time.Millisecond
+
+	records := []libdns.Record{libdns.TXT{Name: "www", Text: "hello", TTL: time.Hour}}
+	if _, err := provider.AppendRecords(context.Background(), "example.com", records); err == nil {
+		t.Error("Expected error for HTTP 502")
+	}
+	if adds != 1 {
+		t.Errorf("Expected dnsAddRecord to be sent once, got %d", adds)
+	}
+}
+
+func TestRetryMutationsNotSent(t *testing.T) {
+	dials := 0
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			dials++
+			return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+		},
+	}
+	provider := &Provider{APIToken: "test-token", HTTPClient: &http.Client{Transport: transport}}
+	provider.MaxRetries = 2
+	provider.RetryBaseDelay = time.Millisecond
+
+	records := []libdns.Record{libdns.TXT{Name: "www", Text: "hello", TTL: time.Hour}}
+	if _, err := provider.AppendRecords(context.Background(), "example.com", records); err == nil {
+		t.Error("Expected error for a refused connection")
+	}
+	if dials != 3 {
+		t.Errorf("Expected a request that was never sent to be retried, got %d dials", dials)
+	}
+}
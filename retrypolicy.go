@@ -0,0 +1,177 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryClass is how a RetryPolicy classifies the outcome of a request.
+type RetryClass int
+
+const (
+	// RetryNever makes the request fail with its outcome, as for
+	// authentication failures and rejected parameters.
+	RetryNever RetryClass = iota
+
+	// RetryTransient retries the request up to MaxRetries times, with
+	// exponential backoff starting at RetryBaseDelay.
+	RetryTransient
+
+	// RetryThrottled retries the request up to ThrottleRetries times, with
+	// a backoff that delays the other requests of the Provider as well.
+	// Once ThrottleRetries is exhausted, the request is retried like a
+	// transient failure.
+	RetryThrottled
+)
+
+// RetryAttempt describes the outcome of a request that did not succeed,
+// for a RetryPolicy to classify.
+type RetryAttempt struct {
+	Operation  string // API operation, e.g. "dnsListRecords"
+	Attempt    int    // 0 for the first request, 1 for the first retry, ...
+	StatusCode int    // HTTP error status, or 0 if there was none
+	ReplyCode  int    // NameSilo reply code, or 0 if there was no reply
+	Err        error  // error of the request, or nil if NameSilo replied
+}
+
+// RetryPolicy decides whether a request is retried. It is called for
+// every request that failed or got an unsuccessful reply code, unless the
+// context of the request has ended.
+type RetryPolicy func(RetryAttempt) RetryClass
+
+// DefaultRetryPolicy is the RetryPolicy used when the Provider has none. It
+// classifies failures by operation:
+//
+//   - Throttling (HTTP 429 or a "request still processing" reply) is
+//     RetryThrottled for every operation, since NameSilo did not process
+//     the request.
+//   - Network errors, timeouts, HTTP 5xx and "try again later" replies are
+//     RetryTransient for reads (get*, list*, check*, dnsListRecords, ...)
+//     and for idempotent operations such as dnsUpdateRecord,
+//     dnsDeleteRecord, changeNameServers and domainLock.
+//   - For all other operations, e.g. dnsAddRecord, registerDomain,
+//     renewDomain, transferDomain, domainPush and contactAdd, a retry could
+//     add a duplicate or charge the account twice, so only errors that
+//     happened before the request was sent, such as a failure to connect
+//     to NameSilo, are RetryTransient.
+//
+// Other HTTP errors, authentication failures, replies rejecting the request
+// and responses that could not be decoded are never retried.
+func DefaultRetryPolicy(a RetryAttempt) RetryClass {
+	switch {
+	case a.StatusCode == http.StatusTooManyRequests || throttleReplyCodes[a.ReplyCode]:
+		return RetryThrottled
+	case a.StatusCode >= http.StatusInternalServerError || retryableReplyCodes[a.ReplyCode]:
+		if idempotentOperation(a.Operation) {
+			return RetryTransient
+		}
+		return RetryNever
+	case a.StatusCode != 0 || a.Err == nil:
+		return RetryNever
+	}
+
+	var urlErr *url.Error
+	if errors.Is(a.Err, context.Canceled) || !errors.As(a.Err, &urlErr) {
+		return RetryNever
+	}
+	if idempotentOperation(a.Operation) || notSent(a.Err) {
+		return RetryTransient
+	}
+	return RetryNever
+}
+
+// idempotentOperations are the API operations, besides those named get*,
+// list* and check*, that have the same effect when repeated
+var idempotentOperations = map[string]bool{
+	"dnsListRecords":                 true,
+	"dnsUpdateRecord":                true,
+	"dnsDeleteRecord":                true,
+	"dnsSecListRecords":              true,
+	"dnsSecDeleteRecord":             true,
+	"contactList":                    true,
+	"contactUpdate":                  true,
+	"contactDelete":                  true,
+	"contactDomainAssociate":         true,
+	"portfolioList":                  true,
+	"portfolioDelete":                true,
+	"portfolioDomainAssociate":       true,
+	"changeNameServers":              true,
+	"domainLock":                     true,
+	"domainUnlock":                   true,
+	"addPrivacy":                     true,
+	"removePrivacy":                  true,
+	"addAutoRenewal":                 true,
+	"removeAutoRenewal":              true,
+	"domainForward":                  true,
+	"domainForwardSubDomainDelete":   true,
+	"orderDetails":                   true,
+	"registrantVerificationStatus":   true,
+	"marketplaceActiveSalesOverview": true,
+}
+
+// idempotentOperation reports whether operation can be repeated after a
+// failure that NameSilo may have processed
+func idempotentOperation(operation string) bool {
+	for _, prefix := range []string{"get", "list", "check"} {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return idempotentOperations[operation]
+}
+
+// notSent reports whether err happened before the request was sent, such
+// as a failure to resolve or connect to the API host
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// retryPolicy returns the Provider's RetryPolicy or the default
+func (p *Provider) retryPolicy() RetryPolicy {
+	if p.RetryPolicy != nil {
+		return p.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+// classifyRetry classifies the outcome of a request, returning RetryNever
+// for successful replies
+func (p *Provider) classifyRetry(operation string, attempt int, err error, resp interface{}) RetryClass {
+	a := RetryAttempt{Operation: operation, Attempt: attempt, Err: err}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		a.StatusCode = statusErr.StatusCode
+	}
+	if err == nil {
+		a.ReplyCode = replyCodeOf(resp)
+		if isSuccessCode(a.ReplyCode) {
+			return RetryNever
+		}
+	}
+	return p.retryPolicy()(a)
+}
+
+// jitter shortens delay by a random fraction of up to RetryJitter
+func (p *Provider) jitter(delay time.Duration) time.Duration {
+	if p.RetryJitter <= 0 {
+		return delay
+	}
+	return delay - time.Duration(float64(delay)*min(p.RetryJitter, 1)*rand.Float64())
+}
+
+// withinRetryBudget reports whether a retry after delay would still start
+// within RetryBudget of the first attempt of a request made at start
+func (p *Provider) withinRetryBudget(start time.Time, delay time.Duration) bool {
+	return p.RetryBudget <= 0 || time.Since(start)+delay <= p.RetryBudget
+}
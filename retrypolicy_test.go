@@ -0,0 +1,212 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestDefaultRetryPolicy(t *testing.T) {
+	netErr := &url.Error{Op: "Get", URL: "https://www.namesilo.com/api", Err: errors.New("connection reset")}
+	tests := []struct {
+		name    string
+		attempt RetryAttempt
+		want    RetryClass
+	}{
+		{"HTTP 429", RetryAttempt{StatusCode: 429, Err: errors.New("x")}, RetryThrottled},
+		{"still processing", RetryAttempt{ReplyCode: 400}, RetryThrottled},
+		{"HTTP 503", RetryAttempt{Operation: "dnsListRecords", StatusCode: 503, Err: errors.New("x")}, RetryTransient},
+		{"try again later", RetryAttempt{Operation: "dnsListRecords", ReplyCode: 115}, RetryTransient},
+		{"network error", RetryAttempt{Operation: "dnsListRecords", Err: netErr}, RetryTransient},
+		{"timeout", RetryAttempt{Operation: "getDomainInfo", Err: &url.Error{Op: "Get", Err: context.DeadlineExceeded}}, RetryTransient},
+		{"mutation HTTP 502", RetryAttempt{Operation: "dnsAddRecord", StatusCode: 502, Err: errors.New("x")}, RetryNever},
+		{"mutation network error", RetryAttempt{Operation: "dnsAddRecord", Err: netErr}, RetryNever},
+		{"mutation dial error", RetryAttempt{Operation: "dnsAddRecord", Err: &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}}, RetryTransient},
+		{"mutation lookup error", RetryAttempt{Operation: "registerDomain", Err: &url.Error{Op: "Get", Err: &net.DNSError{Err: "no such host", Name: "www.namesilo.com"}}}, RetryTransient},
+		{"mutation try again later", RetryAttempt{Operation: "dnsAddRecord", ReplyCode: 115}, RetryNever},
+		{"throttled mutation", RetryAttempt{Operation: "registerDomain", StatusCode: 429, Err: errors.New("x")}, RetryThrottled},
+		{"canceled", RetryAttempt{Err: &url.Error{Op: "Get", Err: context.Canceled}}, RetryNever},
+		{"HTTP 401", RetryAttempt{StatusCode: 401, Err: errors.New("x")}, RetryNever},
+		{"HTTP 403", RetryAttempt{StatusCode: 403, Err: errors.New("x")}, RetryNever},
+		{"invalid API key", RetryAttempt{ReplyCode: 110}, RetryNever},
+		{"IP not allowed", RetryAttempt{ReplyCode: 113}, RetryNever},
+		{"invalid parameters", RetryAttempt{ReplyCode: 280}, RetryNever},
+		{"decoding error", RetryAttempt{Err: errors.New("XML syntax error")}, RetryNever},
+	}
+	for _, tt := range tests {
+		if got := DefaultRetryPolicy(tt.attempt); got != tt.want {
+			t.Errorf("%s: DefaultRetryPolicy = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultRetryPolicyOperations(t *testing.T) {
+	netErr := &url.Error{Op: "Get", URL: "https://www.namesilo.com/api", Err: errors.New("connection reset")}
+	tests := map[string]RetryClass{
+		"dnsListRecords":            RetryTransient,
+		"dnsSecListRecords":         RetryTransient,
+		"getDomainInfo":             RetryTransient,
+		"listDomains":               RetryTransient,
+		"checkRegisterAvailability": RetryTransient,
+		"contactList":               RetryTransient,
+		"dnsUpdateRecord":           RetryTransient,
+		"dnsDeleteRecord":           RetryTransient,
+		"changeNameServers":         RetryTransient,
+		"domainLock":                RetryTransient,
+		"dnsAddRecord":              RetryNever,
+		"dnsSecAddRecord":           RetryNever,
+		"registerDomain":            RetryNever,
+		"renewDomain":               RetryNever,
+		"transferDomain":            RetryNever,
+		"domainPush":                RetryNever,
+		"contactAdd":                RetryNever,
+		"portfolioAdd":              RetryNever,
+		"domainForwardSubDomain":    RetryNever,
+	}
+	for operation, want := range tests {
+		for _, a := range []RetryAttempt{
+			{Operation: operation, StatusCode: 502, Err: errors.New("x")},
+			{Operation: operation, ReplyCode: 115},
+			{Operation: operation, Err: netErr},
+		} {
+			if got := DefaultRetryPolicy(a); got != want {
+				t.Errorf("DefaultRetryPolicy(%+v) = %v, want %v", a, got, want)
+			}
+		}
+	}
+}
+
+func TestRetryNeverRetriesAuthFailures(t *testing.T) {
+	for _, reply := range []func(http.ResponseWriter){
+		func(w http.ResponseWriter) { w.WriteHeader(http.StatusUnauthorized) },
+		func(w http.ResponseWriter) {
+			w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+		},
+	} {
+		attempts := 0
+		provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			reply(w)
+		})
+		provider.MaxRetries = 3
+		provider.RetryBaseDelay = time.Millisecond
+
+		if _, err := provider.GetRecords(context.Background(), "example.com"); err == nil {
+			t.Error("Expected an error")
+		}
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	}
+}
+
+func TestCustomRetryPolicy(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			fmt.Fprint(w, `<namesilo><reply><code>280</code><detail>DNS modification error</detail></reply></namesilo>`)
+			return
+		}
+		fmt.Fprint(w, `<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`)
+	})
+	provider.MaxRetries = 5
+	provider.RetryBaseDelay = time.Millisecond
+	var seen []RetryAttempt
+	provider.RetryPolicy = func(a RetryAttempt) RetryClass {
+		seen = append(seen, a)
+		if a.ReplyCode == 280 {
+			return RetryTransient
+		}
+		return DefaultRetryPolicy(a)
+	}
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if attempts != 3 || len(seen) != 2 {
+		t.Fatalf("Expected 3 attempts and 2 classified failures, got %d and %+v", attempts, seen)
+	}
+	for i, a := range seen {
+		if a.Operation != "dnsListRecords" || a.Attempt != i || a.ReplyCode != 280 || a.Err != nil {
+			t.Errorf("Unexpected attempt %+v", a)
+		}
+	}
+}
+
+func TestRetryPolicyDisablesRetries(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	provider.MaxRetries = 3
+	provider.RetryBaseDelay = time.Millisecond
+	var status int
+	provider.RetryPolicy = func(a RetryAttempt) RetryClass {
+		status = a.StatusCode
+		return RetryNever
+	}
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err == nil {
+		t.Error("Expected error for HTTP 503")
+	}
+	if attempts != 1 || status != http.StatusServiceUnavailable {
+		t.Errorf("Expected 1 attempt classified with its status, got %d and %d", attempts, status)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	provider.MaxRetries = 10
+	provider.RetryBaseDelay = 20 * time.Millisecond
+	provider.RetryBudget = 50 * time.Millisecond
+
+	// The first retry waits 20ms; the second would wait until 60ms
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err == nil {
+		t.Error("Expected error for HTTP 502")
+	}
+	if attempts != 2 {
+		t.Errorf("Expected the budget to allow 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	if got := (&Provider{}).jitter(time.Second); got != time.Second {
+		t.Errorf("Expected no jitter by default, got %v", got)
+	}
+
+	p := &Provider{RetryJitter: 0.5}
+	varied := false
+	for i := 0; i < 100; i++ {
+		got := p.jitter(time.Second)
+		if got < 500*time.Millisecond || got > time.Second {
+			t.Fatalf("Jittered delay %v out of range", got)
+		}
+		varied = varied || got != p.jitter(time.Second)
+	}
+	if !varied {
+		t.Error("Expected jittered delays to vary")
+	}
+
+	var c Provider
+	if err := json.Unmarshal([]byte(`{"retry_jitter": 0.2, "retry_budget": "1m"}`), &c); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if c.RetryJitter != 0.2 || c.RetryBudget != time.Minute {
+		t.Errorf("Unexpected provider %+v", &c)
+	}
+	if err := json.Unmarshal([]byte(`{"retry_jitter": 1.5}`), &c); err == nil {
+		t.Error("Expected an error for a jitter above 1")
+	}
+}
@@ -0,0 +1,79 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/libdns/libdns"
+)
+
+// rollbackAppend deletes records created by a failed AppendRecords call, in
+// reverse order, and returns cause along with any rollback failure. The
+// rollback runs even if ctx was canceled, since the zone would otherwise be
+// left half changed.
+func (p *Provider) rollbackAppend(ctx context.Context, zone string, added []libdns.Record, cause error) error {
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(added) - 1; i >= 0; i-- {
+		id, ok := RecordID(added[i])
+		if !ok {
+			errs = append(errs, fmt.Errorf("record %s has no ID", added[i].RR().Name))
+			continue
+		}
+		if err := p.deleteRecordByID(ctx, zone, id, added[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (rollback of %d added records failed: %w)", cause, len(added), errors.Join(errs...))
+	}
+	return fmt.Errorf("%w (rolled back %d added records)", cause, len(added))
+}
+
+// rollbackChanges undoes applied changes in reverse order and returns cause
+// along with any rollback failure. Like rollbackAppend, it ignores
+// cancellation of ctx.
+func (p *Provider) rollbackChanges(ctx context.Context, zone string, applied []SyncChange, cause error) error {
+	if len(applied) == 0 {
+		return cause
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for i := len(applied) - 1; i >= 0; i-- {
+		if err := p.undoChange(ctx, zone, applied[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w (rollback of %d changes failed: %w)", cause, len(applied), errors.Join(errs...))
+	}
+	return fmt.Errorf("%w (rolled back %d changes)", cause, len(applied))
+}
+
+// undoChange reverts a change returned by applyChange
+func (p *Provider) undoChange(ctx context.Context, zone string, change SyncChange) error {
+	switch change.Action {
+	case SyncCreate:
+		id, ok := RecordID(change.Record)
+		if !ok {
+			return fmt.Errorf("cannot remove created record %s without its ID", change.Record.RR().Name)
+		}
+		return p.deleteRecordByID(ctx, zone, id, change.Record)
+	case SyncUpdate:
+		id, ok := RecordID(change.Record)
+		if !ok {
+			return fmt.Errorf("cannot restore updated record %s without its ID", change.Record.RR().Name)
+		}
+		_, err := p.updateRecord(ctx, zone, id, change.Record, change.Existing)
+		return err
+	case SyncDelete:
+		_, err := p.addRecord(ctx, zone, change.Existing, false)
+		return err
+	}
+	return nil
+}
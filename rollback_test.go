@@ -0,0 +1,76 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestAtomicAppend(t *testing.T) {
+	records := []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1"},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2"},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1"}, // duplicate
+	}
+
+	for _, atomic := range []bool{false, true} {
+		srv := namesilotest.NewServer()
+		provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), AtomicAppend: atomic}
+		srv.AddZone("example.com")
+
+		added, err := provider.AppendRecords(context.Background(), "example.com.", records)
+		if !errors.Is(err, ErrRecordExists) {
+			t.Errorf("atomic=%v: expected ErrRecordExists, got %v", atomic, err)
+		}
+
+		want := 2
+		if atomic {
+			want = 0
+		}
+		if len(added) != want {
+			t.Errorf("atomic=%v: expected %d records returned, got %d", atomic, want, len(added))
+		}
+		if n := len(srv.Records("example.com")); n != want {
+			t.Errorf("atomic=%v: expected %d records in the zone, got %d", atomic, want, n)
+		}
+		srv.Close()
+	}
+}
+
+func TestSetRecordsRollback(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "TXT", Host: "www", Value: "old", TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+
+	_, err := provider.SetRecords(context.Background(), "example.com.", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2"},
+		libdns.TXT{Name: "www", Text: "new"},
+		libdns.RR{Name: "mail", Type: "A", Data: "192.0.2.3"},
+		libdns.RR{Name: "mail", Type: "A", Data: "192.0.2.3"}, // duplicate
+	})
+	if !errors.Is(err, ErrRecordExists) {
+		t.Fatalf("Expected ErrRecordExists, got %v", err)
+	}
+
+	records := srv.Records("example.com")
+	got := map[string]string{}
+	for _, rec := range records {
+		got[rec.Type+" "+rec.Host] = rec.Value
+	}
+	want := map[string]string{"A www.example.com": "192.0.2.1", "TXT www.example.com": "old"}
+	if len(got) != len(want) || len(records) != len(want) {
+		t.Fatalf("Zone not restored: %+v", records)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %q, want %q", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// ZoneSnapshot is a point-in-time copy of the records of a zone, taken by
+// SnapshotZone. It can be stored as JSON and later passed to RestoreZone.
+type ZoneSnapshot struct {
+	Zone    string           `json:"zone"`
+	Taken   time.Time        `json:"taken"`
+	Records []SnapshotRecord `json:"records"`
+}
+
+// SnapshotRecord is a record of a ZoneSnapshot.
+type SnapshotRecord struct {
+	ID   string `json:"id,omitempty"` // NameSilo record ID at the time of the snapshot
+	Name string `json:"name"`         // Name relative to the zone, "@" for the apex
+	Type string `json:"type"`         // NameSilo record type, e.g. "A" or "ALIAS"
+	Data string `json:"data"`         // Record data in zone file syntax
+	TTL  int    `json:"ttl"`          // TTL in seconds
+}
+
+// SnapshotZone returns a snapshot of the records currently in the zone,
+// as a safety net before risky bulk changes.
+func (p *Provider) SnapshotZone(ctx context.Context, zone string) (*ZoneSnapshot, error) {
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ZoneSnapshot{Zone: zone, Taken: time.Now().UTC()}
+	for _, record := range records {
+		snapshot.Records = append(snapshot.Records, snapshotRecord(record))
+	}
+	return snapshot, nil
+}
+
+// snapshotRecord converts a record into its serializable form
+func snapshotRecord(record libdns.Record) SnapshotRecord {
+	rr := record.RR()
+	id, _ := RecordID(record)
+	return SnapshotRecord{
+		ID:   id,
+		Name: rr.Name,
+		Type: apiRecordType(record),
+		Data: rr.Data,
+		TTL:  int(rr.TTL.Seconds()),
+	}
+}
+
+// RestoreZone reconciles the zone back to a snapshot with SyncZone: records
+// added since the snapshot are deleted (unless opts.NoDelete is set), and
+// changed or deleted records are recreated. Records NameSilo manages itself,
+// such as the apex NS records, are left alone. The snapshot may be restored
+// into another zone, since its names are relative.
+func (p *Provider) RestoreZone(ctx context.Context, zone string, snapshot *ZoneSnapshot, opts SyncOptions) (*SyncPlan, error) {
+	records, err := snapshot.records()
+	if err != nil {
+		return nil, err
+	}
+
+	var desired []libdns.Record
+	for _, record := range records {
+		if !isManagedByNameSilo(zone, record.RR()) {
+			desired = append(desired, record)
+		}
+	}
+	return p.SyncZone(ctx, zone, desired, opts)
+}
+
+// records converts the snapshot back into libdns records
+func (s *ZoneSnapshot) records() ([]libdns.Record, error) {
+	records := make([]libdns.Record, 0, len(s.Records))
+	for _, rec := range s.Records {
+		record, err := parseRR(libdns.RR{
+			Name: rec.Name,
+			Type: rec.Type,
+			Data: rec.Data,
+			TTL:  time.Duration(rec.TTL) * time.Second,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("snapshot record %s %s: %w", rec.Name, rec.Type, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
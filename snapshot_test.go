@@ -0,0 +1,92 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestSnapshotAndRestoreZone(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "NS", Host: "@", Value: "ns1.dnsowl.com", TTL: 7207},
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "MX", Host: "@", Value: "mail.example.com", Distance: 10, TTL: 3600},
+		namesilotest.Record{Type: "ALIAS", Host: "@", Value: "lb.example.net", TTL: 3600},
+	)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	snapshot, err := provider.SnapshotZone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("SnapshotZone failed: %v", err)
+	}
+	if len(snapshot.Records) != 4 || snapshot.Records[1].Name != "www" || snapshot.Records[3].Type != "ALIAS" {
+		t.Fatalf("Unexpected snapshot: %+v", snapshot.Records)
+	}
+
+	// The snapshot survives a JSON round trip
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var stored ZoneSnapshot
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	// Risky changes
+	if _, err := provider.SetRecords(ctx, "example.com", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.99", TTL: time.Hour},
+	}); err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if _, err := provider.DeleteRecords(ctx, "example.com", []libdns.Record{libdns.RR{Name: "@", Type: "MX"}}); err != nil {
+		t.Fatalf("DeleteRecords failed: %v", err)
+	}
+	if _, err := provider.AppendRecords(ctx, "example.com", []libdns.Record{libdns.TXT{Name: "new", Text: "x"}}); err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	plan, err := provider.RestoreZone(ctx, "example.com", &stored, SyncOptions{})
+	if err != nil {
+		t.Fatalf("RestoreZone failed: %v", err)
+	}
+	if len(plan.Changes) != 3 {
+		t.Errorf("Expected 3 changes, got %+v", plan.Changes)
+	}
+
+	after, err := provider.SnapshotZone(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("SnapshotZone failed: %v", err)
+	}
+	if !sameSnapshotContent(snapshot, after) {
+		t.Errorf("Expected the zone restored to\n%+v\ngot\n%+v", snapshot.Records, after.Records)
+	}
+}
+
+// sameSnapshotContent compares snapshots ignoring record IDs and order
+func sameSnapshotContent(a, b *ZoneSnapshot) bool {
+	key := func(r SnapshotRecord) SnapshotRecord {
+		r.ID = ""
+		return r
+	}
+	count := make(map[SnapshotRecord]int)
+	for _, r := range a.Records {
+		count[key(r)]++
+	}
+	for _, r := range b.Records {
+		count[key(r)]--
+	}
+	for _, n := range count {
+		if n != 0 {
+			return false
+		}
+	}
+	return len(a.Records) == len(b.Records)
+}
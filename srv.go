@@ -0,0 +1,97 @@
+package namesilo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// srvValue formats the rrvalue NameSilo expects for SRV records; the
+// priority is sent separately as rrdistance
+func srvValue(srv libdns.SRV) string {
+	return fmt.Sprintf("%d %d %s", srv.Weight, srv.Port, srv.Target)
+}
+
+// validateSRV checks that an SRV record can be created at NameSilo
+func validateSRV(srv libdns.SRV) error {
+	if srv.Service == "" && srv.Transport == "" {
+		// The name may already carry the _service._proto labels
+		if _, _, _, ok := splitSRVName(srv.Name); !ok {
+			return fmt.Errorf("SRV record %q: service and transport are required", srv.Name)
+		}
+	} else if srv.Service == "" || srv.Transport == "" {
+		return fmt.Errorf("SRV record %q: both service and transport are required", srv.Name)
+	}
+	if strings.ContainsAny(srv.Service+srv.Transport, ". ") || strings.HasPrefix(srv.Service, "_") || strings.HasPrefix(srv.Transport, "_") {
+		return fmt.Errorf("SRV record: service %q and transport %q must be single labels without underscores", srv.Service, srv.Transport)
+	}
+	if srv.Target == "" {
+		return fmt.Errorf("SRV record _%s._%s.%s: target is required (use \".\" for no service)", srv.Service, srv.Transport, srv.Name)
+	}
+	return nil
+}
+
+// splitSRVName splits "_service._proto.name" into its parts
+func splitSRVName(host string) (service, transport, name string, ok bool) {
+	labels := strings.SplitN(host, ".", 3)
+	if len(labels) < 2 || len(labels[0]) < 2 || len(labels[1]) < 2 || labels[0][0] != '_' || labels[1][0] != '_' {
+		return "", "", "", false
+	}
+
+	name = "@"
+	if len(labels) == 3 && labels[2] != "" {
+		name = labels[2]
+	}
+	return labels[0][1:], labels[1][1:], name, true
+}
+
+// parseSRV converts an SRV record returned by NameSilo. The value is
+// normally "weight port target" with the priority in the distance field;
+// values with a leading priority are accepted as well.
+func parseSRV(rec dnsRecord) (libdns.SRV, error) {
+	service, transport, name, ok := splitSRVName(rec.Host)
+	if !ok {
+		return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): host lacks _service._proto labels", rec.Host, rec.ID)
+	}
+
+	fields := strings.Fields(rec.Value)
+	priority := uint64(rec.Distance)
+	switch len(fields) {
+	case 3:
+	case 4:
+		p, err := strconv.ParseUint(fields[0], 10, 16)
+		if err != nil {
+			return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): invalid priority %q", rec.Host, rec.ID, fields[0])
+		}
+		priority = p
+		fields = fields[1:]
+	default:
+		return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): malformed value %q, expected \"weight port target\"", rec.Host, rec.ID, rec.Value)
+	}
+
+	weight, err := strconv.ParseUint(fields[0], 10, 16)
+	if err != nil {
+		return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): invalid weight %q", rec.Host, rec.ID, fields[0])
+	}
+	port, err := strconv.ParseUint(fields[1], 10, 16)
+	if err != nil {
+		return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): invalid port %q", rec.Host, rec.ID, fields[1])
+	}
+	if priority > 65535 {
+		return libdns.SRV{}, fmt.Errorf("SRV record %s (ID %s): invalid priority %d", rec.Host, rec.ID, priority)
+	}
+
+	return libdns.SRV{
+		Service:   service,
+		Transport: transport,
+		Name:      name,
+		TTL:       time.Duration(rec.TTL) * time.Second,
+		Priority:  uint16(priority),
+		Weight:    uint16(weight),
+		Port:      uint16(port),
+		Target:    fields[2],
+	}, nil
+}
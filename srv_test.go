@@ -0,0 +1,126 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestParseSRV(t *testing.T) {
+	tests := []struct {
+		name    string
+		rec     dnsRecord
+		want    libdns.SRV
+		wantErr string
+	}{
+		{
+			name: "weight port target",
+			rec:  dnsRecord{ID: "1", Type: "SRV", Host: "_sip._tcp.example.com", Value: "5 5060 sip.example.com", TTL: 3600, Distance: 10},
+			want: libdns.SRV{Service: "sip", Transport: "tcp", Name: "example.com", TTL: time.Hour, Priority: 10, Weight: 5, Port: 5060, Target: "sip.example.com"},
+		},
+		{
+			name: "leading priority",
+			rec:  dnsRecord{ID: "2", Type: "SRV", Host: "_xmpp._tcp.chat.example.com", Value: "0 5 5222 xmpp.example.com", TTL: 3600},
+			want: libdns.SRV{Service: "xmpp", Transport: "tcp", Name: "chat.example.com", TTL: time.Hour, Priority: 0, Weight: 5, Port: 5222, Target: "xmpp.example.com"},
+		},
+		{
+			name:    "missing labels",
+			rec:     dnsRecord{ID: "3", Type: "SRV", Host: "sip.example.com", Value: "5 5060 sip.example.com"},
+			wantErr: "lacks _service._proto labels",
+		},
+		{
+			name:    "bad port",
+			rec:     dnsRecord{ID: "4", Type: "SRV", Host: "_sip._tcp.example.com", Value: "5 http sip.example.com"},
+			wantErr: "invalid port",
+		},
+		{
+			name:    "too few fields",
+			rec:     dnsRecord{ID: "5", Type: "SRV", Host: "_sip._tcp.example.com", Value: "sip.example.com"},
+			wantErr: "malformed value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSRV(tt.rec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), tt.rec.ID) {
+					t.Fatalf("Expected error containing %q and the record ID, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseSRV failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unexpected SRV:\n got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSRVRoundTrip(t *testing.T) {
+	srv := libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", TTL: time.Hour, Priority: 0, Weight: 5, Port: 5060, Target: "sip.example.com"}
+	params := (&Provider{}).recordParams("example.com", srv)
+	if params["rrhost"] != "_sip._tcp" || params["rrvalue"] != "5 5060 sip.example.com" || params["rrdistance"] != "0" {
+		t.Fatalf("Unexpected params %v", params)
+	}
+
+	rec, err := createLibDNSRecord(dnsRecord{
+		ID:       "1",
+		Type:     "SRV",
+		Host:     "_sip._tcp.example.com",
+		Value:    params["rrvalue"],
+		TTL:      3600,
+		Distance: 0,
+	})
+	if err != nil {
+		t.Fatalf("createLibDNSRecord failed: %v", err)
+	}
+	if got := (&Provider{}).recordParams("example.com", rec); got["rrhost"] != params["rrhost"] || got["rrvalue"] != params["rrvalue"] || got["rrdistance"] != params["rrdistance"] {
+		t.Errorf("Round trip changed params: %v != %v", got, params)
+	}
+
+	// Malformed data is kept as a generic RR
+	rec, err = createLibDNSRecord(dnsRecord{ID: "2", Type: "SRV", Host: "_sip._tcp.example.com", Value: "garbage"})
+	if err == nil {
+		t.Error("Expected an error for malformed SRV data")
+	}
+	if rr := rec.RR(); rr.Type != "SRV" || rr.Data != "garbage" {
+		t.Errorf("Unexpected fallback record %+v", rr)
+	}
+}
+
+func TestValidateSRV(t *testing.T) {
+	invalid := []libdns.Record{
+		libdns.SRV{Name: "@", Target: "sip.example.com"},
+		libdns.SRV{Service: "sip", Name: "@", Target: "sip.example.com"},
+		libdns.SRV{Service: "_sip", Transport: "tcp", Name: "@", Target: "sip.example.com"},
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "@"},
+		libdns.RR{Name: "_sip._tcp", Type: "SRV", Data: "5060 sip.example.com"},
+	}
+	for _, rec := range invalid {
+		if err := validateRecord(rec); err == nil {
+			t.Errorf("Expected validation error for %+v", rec)
+		}
+	}
+
+	valid := []libdns.Record{
+		libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Target: "sip.example.com"},
+		libdns.SRV{Name: "_sip._udp", Target: "."},
+		libdns.RR{Name: "_sip._tcp", Type: "SRV", Data: "10 5 5060 sip.example.com"},
+	}
+	for _, rec := range valid {
+		if err := validateRecord(rec); err != nil {
+			t.Errorf("Unexpected validation error for %+v: %v", rec, err)
+		}
+	}
+
+	provider := &Provider{APIToken: "test-token"}
+	if _, err := provider.AppendRecords(context.Background(), "example.com", invalid[:1]); err == nil {
+		t.Error("Expected AppendRecords to reject an invalid SRV record")
+	}
+}
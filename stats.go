@@ -0,0 +1,92 @@
+package namesilo
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// ZoneStats summarizes the records of a zone, for audits and dashboards.
+type ZoneStats struct {
+	Zone    string
+	Records int // Number of records
+	Names   int // Number of distinct record names
+
+	// Types counts the records by NameSilo type, e.g. "A" or "ALIAS"
+	Types map[string]int
+
+	// TTLs counts the records by TTL; MinTTL and MaxTTL are the lowest and
+	// highest TTL, or 0 for an empty zone
+	TTLs   map[time.Duration]int
+	MinTTL time.Duration
+	MaxTTL time.Duration
+
+	// Apex counts the records at the zone apex by type
+	Apex map[string]int
+
+	// Wildcards is the number of records with a wildcard name such as "*"
+	// or "*.sub", and WildcardNames are those names, sorted
+	Wildcards     int
+	WildcardNames []string
+}
+
+// ZoneStats lists the zone and returns statistics about its records: the
+// number of records by type and by TTL, the records at the apex and the
+// use of wildcard names. The records are counted as they are read, so
+// zones of any size can be summarized.
+func (p *Provider) ZoneStats(ctx context.Context, zone string) (*ZoneStats, error) {
+	stats := newZoneStats(zone)
+	names := make(map[string]bool)
+	for record, err := range p.RecordsIter(ctx, zone) {
+		if err != nil {
+			return nil, err
+		}
+		stats.add(record, names)
+	}
+	sort.Strings(stats.WildcardNames)
+	return stats, nil
+}
+
+// newZoneStats returns empty statistics for zone
+func newZoneStats(zone string) *ZoneStats {
+	return &ZoneStats{
+		Zone:  strings.TrimSuffix(zone, "."),
+		Types: make(map[string]int),
+		TTLs:  make(map[time.Duration]int),
+		Apex:  make(map[string]int),
+	}
+}
+
+// add counts a record of the zone. names holds the names seen so far.
+func (s *ZoneStats) add(record libdns.Record, names map[string]bool) {
+	rr := record.RR()
+	recordType := apiRecordType(record)
+	name := strings.ToLower(normalizeRecordName(rr.Name, s.Zone))
+
+	s.Records++
+	s.Types[recordType]++
+	s.TTLs[rr.TTL]++
+	if s.Records == 1 || rr.TTL < s.MinTTL {
+		s.MinTTL = rr.TTL
+	}
+	if rr.TTL > s.MaxTTL {
+		s.MaxTTL = rr.TTL
+	}
+
+	if name == "@" {
+		s.Apex[recordType]++
+	}
+	if name == "*" || strings.HasPrefix(name, "*.") {
+		s.Wildcards++
+		if !names[name] {
+			s.WildcardNames = append(s.WildcardNames, name)
+		}
+	}
+	if !names[name] {
+		names[name] = true
+		s.Names++
+	}
+}
@@ -0,0 +1,66 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestZoneStats(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>ALIAS</type><host>example.com</host><value>example.net</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>2</record_id><type>MX</type><host>example.com</host><value>mail.example.com</value><ttl>3600</ttl><distance>10</distance></resource_record>
+<resource_record><record_id>3</record_id><type>TXT</type><host>example.com</host><value>v=spf1 -all</value><ttl>7200</ttl></resource_record>
+<resource_record><record_id>4</record_id><type>A</type><host>www.example.com</host><value>192.0.2.1</value><ttl>300</ttl></resource_record>
+<resource_record><record_id>5</record_id><type>A</type><host>WWW.example.com</host><value>192.0.2.2</value><ttl>300</ttl></resource_record>
+<resource_record><record_id>6</record_id><type>A</type><host>*.example.com</host><value>192.0.2.3</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>7</record_id><type>AAAA</type><host>*.example.com</host><value>2001:db8::1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>8</record_id><type>CNAME</type><host>*.dev.example.com</host><value>dev.example.net</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+	})
+
+	stats, err := provider.ZoneStats(context.Background(), "example.com.")
+	if err != nil {
+		t.Fatalf("ZoneStats failed: %v", err)
+	}
+	want := &ZoneStats{
+		Zone:          "example.com",
+		Records:       8,
+		Names:         4,
+		Types:         map[string]int{"ALIAS": 1, "MX": 1, "TXT": 1, "A": 3, "AAAA": 1, "CNAME": 1},
+		TTLs:          map[time.Duration]int{5 * time.Minute: 2, time.Hour: 5, 2 * time.Hour: 1},
+		MinTTL:        5 * time.Minute,
+		MaxTTL:        2 * time.Hour,
+		Apex:          map[string]int{"ALIAS": 1, "MX": 1, "TXT": 1},
+		Wildcards:     3,
+		WildcardNames: []string{"*", "*.dev"},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("ZoneStats = %+v\nwant %+v", stats, want)
+	}
+}
+
+func TestZoneStatsEmptyAndErrors(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("domain") == "missing.com" {
+			w.Write([]byte(`<namesilo><reply><code>200</code><detail>Domain is not active</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	stats, err := provider.ZoneStats(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("ZoneStats failed: %v", err)
+	}
+	if stats.Records != 0 || stats.MinTTL != 0 || stats.MaxTTL != 0 || len(stats.Types) != 0 {
+		t.Errorf("Unexpected statistics for an empty zone: %+v", stats)
+	}
+
+	if _, err := provider.ZoneStats(context.Background(), "missing.com"); err == nil {
+		t.Error("Expected an error for a zone that is not in the account")
+	}
+}
@@ -0,0 +1,107 @@
+package namesilo
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+
+	"github.com/libdns/libdns"
+)
+
+// streamDecoder is implemented by responses that decode themselves from an
+// XML token stream instead of being unmarshaled in one piece
+type streamDecoder interface {
+	decodeStream(d *xml.Decoder) error
+}
+
+// errStopDecoding ends the decoding of a reply whose recordSink asked to
+// stop
+var errStopDecoding = errors.New("decoding stopped")
+
+// decodeResponse decodes an XML reply from r into resp as it is read,
+// without buffering the whole body
+func decodeResponse(r io.Reader, resp interface{}) error {
+	d := xml.NewDecoder(r)
+	if s, ok := resp.(streamDecoder); ok {
+		return s.decodeStream(d)
+	}
+	return d.Decode(resp)
+}
+
+// decodeStream decodes a dnsListRecords reply, converting each
+// resource_record as soon as it has been read so that the records of large
+// zones are never held in memory as raw XML or as an intermediate slice
+func (r *dnsListResponse) decodeStream(d *xml.Decoder) error {
+	var path []string
+	seenRoot := false
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			if !seenRoot {
+				return io.EOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			seenRoot = true
+			if len(path) == 2 && path[1] == "reply" {
+				handled, err := r.decodeReplyElement(d, &t)
+				if err == errStopDecoding {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if handled {
+					continue
+				}
+			}
+			path = append(path, t.Name.Local)
+		case xml.EndElement:
+			if len(path) > 0 {
+				path = path[:len(path)-1]
+			}
+		}
+	}
+}
+
+// decodeReplyElement decodes a direct child of the reply element, reporting
+// whether it was consumed
+func (r *dnsListResponse) decodeReplyElement(d *xml.Decoder, start *xml.StartElement) (bool, error) {
+	switch start.Name.Local {
+	case "code":
+		return true, d.DecodeElement(&r.Code, start)
+	case "detail":
+		return true, d.DecodeElement(&r.Detail, start)
+	case "resource_record":
+		var element resourceRecord
+		if err := d.DecodeElement(&element, start); err != nil {
+			return true, err
+		}
+		record, err := element.dnsRecord()
+		var rec libdns.Record = namesileoRecord{Record: genericRecord(record), ID: record.ID}
+		if err == nil {
+			rec, err = createLibDNSRecord(record)
+		}
+		if r.sink != nil {
+			r.sink.yielded = true
+			if !r.sink.yield(rec, err) {
+				r.sink.stopped = true
+				return true, errStopDecoding
+			}
+			return true, nil
+		}
+		if err != nil {
+			r.Malformed = append(r.Malformed, err)
+		}
+		r.Records = append(r.Records, rec)
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,86 @@
+package namesilo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeListStream(t *testing.T) {
+	body := `<?xml version="1.0"?>
+<namesilo>
+  <request><operation>dnsListRecords</operation><ip>192.0.2.9</ip></request>
+  <reply>
+    <code>300</code>
+    <detail>success</detail>
+    <resource_record>
+      <record_id>1</record_id><type>A</type><host>www.example.com</host>
+      <value>192.0.2.1</value><ttl>7207</ttl><distance>0</distance>
+    </resource_record>
+    <resource_record>
+      <record_id>2</record_id><type>SRV</type><host>example.com</host>
+      <value>bogus</value><ttl>3600</ttl><distance>0</distance>
+    </resource_record>
+  </reply>
+</namesilo>`
+
+	var resp dnsListResponse
+	if err := decodeResponse(strings.NewReader(body), &resp); err != nil {
+		t.Fatalf("decodeResponse failed: %v", err)
+	}
+	if resp.Code != 300 || resp.Detail != "success" {
+		t.Errorf("Unexpected reply: %d %q", resp.Code, resp.Detail)
+	}
+	if len(resp.Records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(resp.Records))
+	}
+	if rec := resp.Records[0].(namesileoRecord); rec.ID != "1" || rec.RR().Data != "192.0.2.1" {
+		t.Errorf("Unexpected record: %+v", rec)
+	}
+	if len(resp.Malformed) != 1 {
+		t.Errorf("Expected 1 malformed record, got %v", resp.Malformed)
+	}
+}
+
+func TestDecodeListStreamErrors(t *testing.T) {
+	for name, body := range map[string]string{
+		"empty":     "",
+		"truncated": "<namesilo><reply><code>300</code><resource_record><record_id>1",
+		"bad code":  "<namesilo><reply><code>abc</code></reply></namesilo>",
+	} {
+		var resp dnsListResponse
+		if err := decodeResponse(strings.NewReader(body), &resp); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+// largeZoneReader generates a dnsListRecords reply with n records without
+// holding it in memory
+func largeZoneReader(n int) io.Reader {
+	pr, pw := io.Pipe()
+	go func() {
+		fmt.Fprint(pw, "<namesilo><reply><code>300</code><detail>success</detail>")
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(pw, "<resource_record><record_id>%d</record_id><type>TXT</type><host>r%d.example.com</host><value>v%d</value><ttl>3600</ttl><distance>0</distance></resource_record>", i, i, i)
+		}
+		fmt.Fprint(pw, "</reply></namesilo>")
+		pw.Close()
+	}()
+	return pr
+}
+
+func TestDecodeLargeZone(t *testing.T) {
+	const n = 20000
+	var resp dnsListResponse
+	if err := decodeResponse(largeZoneReader(n), &resp); err != nil {
+		t.Fatalf("decodeResponse failed: %v", err)
+	}
+	if len(resp.Records) != n {
+		t.Fatalf("Expected %d records, got %d", n, len(resp.Records))
+	}
+	if rr := resp.Records[n-1].RR(); rr.Name != fmt.Sprintf("r%d.example.com", n-1) || rr.Type != "TXT" {
+		t.Errorf("Unexpected last record: %+v", rr)
+	}
+}
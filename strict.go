@@ -0,0 +1,91 @@
+package namesilo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnexpectedResponse is returned with StrictDecoding when a reply does
+// not have the structure this package expects, which usually means the
+// NameSilo API has changed.
+var ErrUnexpectedResponse = errors.New("namesilo: unexpected response")
+
+// replyEnvelope holds the elements every NameSilo reply has
+type replyEnvelope struct {
+	XMLName   xml.Name `xml:"namesilo"`
+	Operation *string  `xml:"request>operation"`
+	Code      *string  `xml:"reply>code"`
+}
+
+// requiredFields is implemented by responses with elements that a
+// successful reply must contain
+type requiredFields interface {
+	missingFields() []string
+}
+
+// missingFields implements requiredFields
+func (r dnsAddResponse) missingFields() []string {
+	if r.RecordID == "" {
+		return []string{"record_id"}
+	}
+	return nil
+}
+
+// missingFields implements requiredFields
+func (r dnsUpdateResponse) missingFields() []string {
+	if r.RecordID == "" {
+		return []string{"record_id"}
+	}
+	return nil
+}
+
+// knownReplyCode reports whether code is one of the reply codes documented
+// by NameSilo: request errors (1xx), domain and DNS errors (2xx), success
+// (300-302) and throttling (400)
+func knownReplyCode(code int) bool {
+	switch {
+	case code >= 101 && code <= 120, code >= 200 && code <= 299, code >= 300 && code <= 302:
+		return true
+	}
+	return throttleReplyCodes[code]
+}
+
+// checkStrict verifies a decoded reply of operation against its raw body:
+// the root element, the echoed operation and the reply code must be present
+// and as expected, and successful replies must carry the elements the
+// response type requires
+func (p *Provider) checkStrict(operation string, raw []byte, resp interface{}) error {
+	if p.responseFormat() == FormatJSON {
+		var converted bytes.Buffer
+		if err := jsonToXML(bytes.NewReader(raw), &converted); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+		}
+		raw = converted.Bytes()
+	}
+
+	var envelope replyEnvelope
+	if err := xml.NewDecoder(bytes.NewReader(raw)).Decode(&envelope); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnexpectedResponse, err)
+	}
+	if envelope.Operation != nil && !strings.EqualFold(strings.TrimSpace(*envelope.Operation), operation) {
+		return fmt.Errorf("%w: reply is for operation %q, not %q", ErrUnexpectedResponse, *envelope.Operation, operation)
+	}
+	if envelope.Code == nil {
+		return fmt.Errorf("%w: %s reply has no reply code", ErrUnexpectedResponse, operation)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(*envelope.Code))
+	if err != nil || !knownReplyCode(code) {
+		return fmt.Errorf("%w: %s reply has unknown reply code %q", ErrUnexpectedResponse, operation, *envelope.Code)
+	}
+
+	if r, ok := resp.(requiredFields); ok && isSuccessCode(code) {
+		if missing := r.missingFields(); len(missing) > 0 {
+			return fmt.Errorf("%w: %s reply lacks %s", ErrUnexpectedResponse, operation, strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestStrictDecoding(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{
+			"well-formed",
+			`<namesilo><request><operation>dnsAddRecord</operation></request><reply><code>300</code><detail>success</detail><record_id>abc</record_id></reply></namesilo>`,
+			false,
+		},
+		{
+			"no request element",
+			`<namesilo><reply><code>300</code><detail>success</detail><record_id>abc</record_id></reply></namesilo>`,
+			false,
+		},
+		{
+			"wrong root",
+			`<response><reply><code>300</code><record_id>abc</record_id></reply></response>`,
+			true,
+		},
+		{
+			"missing code",
+			`<namesilo><reply><detail>success</detail><record_id>abc</record_id></reply></namesilo>`,
+			true,
+		},
+		{
+			"unknown code",
+			`<namesilo><reply><code>777</code><detail>new</detail></reply></namesilo>`,
+			true,
+		},
+		{
+			"other operation",
+			`<namesilo><request><operation>dnsListRecords</operation></request><reply><code>300</code><record_id>abc</record_id></reply></namesilo>`,
+			true,
+		},
+		{
+			"missing record ID",
+			`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.body))
+			})
+			provider.StrictDecoding = true
+
+			_, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+				libdns.TXT{Name: "test", Text: "hello"},
+			})
+			if tt.wantErr != errors.Is(err, ErrUnexpectedResponse) {
+				t.Errorf("Expected ErrUnexpectedResponse: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStrictDecodingOff(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<response><reply><code>300</code><record_id>abc</record_id></reply></response>`))
+	})
+
+	if _, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "test", Text: "hello"},
+	}); err != nil {
+		t.Errorf("Expected lenient decoding by default, got %v", err)
+	}
+}
+
+func TestStrictDecodingFakeServer(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600})
+
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), StrictDecoding: true}
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Errorf("GetRecords failed: %v", err)
+	}
+}
+
+func TestStrictDecodingJSON(t *testing.T) {
+	body := `{"request":{"operation":"dnsAddRecord"},"reply":{"code":300,"detail":"success"}}`
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+	provider.ResponseFormat = FormatJSON
+	provider.StrictDecoding = true
+
+	records := []libdns.Record{libdns.TXT{Name: "test", Text: "hello"}}
+	if _, err := provider.AppendRecords(context.Background(), "example.com", records); !errors.Is(err, ErrUnexpectedResponse) {
+		t.Errorf("Expected ErrUnexpectedResponse for a reply without record_id, got %v", err)
+	}
+
+	body = `{"request":{"operation":"dnsAddRecord"},"reply":{"code":300,"detail":"success","record_id":"abc"}}`
+	if _, err := provider.AppendRecords(context.Background(), "example.com", records); err != nil {
+		t.Errorf("AppendRecords failed: %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// subzone maps a zone below a registered domain, such as
+// "app.internal.example.com", onto that domain. Record names relative to the
+// zone become names relative to the domain by appending prefix.
+type subzone struct {
+	zone   string // the zone as given by the caller, without trailing dot
+	domain string // the registered NameSilo domain
+	prefix string // labels of zone below domain, e.g. "app.internal"
+}
+
+// toDomain returns record with its name relative to the registered domain.
+// Absolute names outside the zone are rejected.
+func (s *subzone) toDomain(record libdns.Record) (libdns.Record, error) {
+	var err error
+	mapped := renameRecord(record, func(name string) string {
+		if strings.HasSuffix(name, ".") {
+			fqdn := strings.TrimSuffix(name, ".")
+			if !strings.EqualFold(fqdn, s.zone) && !hasZoneSuffix(fqdn, s.zone) {
+				err = fmt.Errorf("record %s is outside zone %s", name, s.zone)
+				return name
+			}
+		}
+		name = normalizeRecordName(name, s.zone)
+		if name == "@" {
+			return s.prefix
+		}
+		return name + "." + s.prefix
+	})
+	return mapped, err
+}
+
+// fromDomain returns record, named relative to the registered domain, with
+// its name relative to the zone. It reports false if the record lies
+// outside the zone.
+func (s *subzone) fromDomain(record libdns.Record) (libdns.Record, bool) {
+	inside := true
+	mapped := renameRecord(record, func(name string) string {
+		name = normalizeRecordName(name, s.domain)
+		switch {
+		case strings.EqualFold(name, s.prefix):
+			return "@"
+		case hasZoneSuffix(name, s.prefix):
+			return name[:len(name)-len(s.prefix)-1]
+		}
+		inside = false
+		return name
+	})
+	return mapped, inside
+}
+
+// fromDomainAll maps records of the registered domain back into the zone,
+// dropping records outside it
+func (s *subzone) fromDomainAll(records []libdns.Record) []libdns.Record {
+	var mapped []libdns.Record
+	for _, record := range records {
+		if rec, ok := s.fromDomain(record); ok {
+			mapped = append(mapped, rec)
+		}
+	}
+	return mapped
+}
+
+// unregisteredZoneExpiry is how long resolveZone remembers that no account
+// holds a zone or any of its parents, so that a domain added to an account
+// later is picked up
+const unregisteredZoneExpiry = 10 * time.Minute
+
+// subzoneEntry is a result of resolveZone kept by the Provider
+type subzoneEntry struct {
+	sub     *subzone
+	expires time.Time // zero if the entry does not expire
+}
+
+// resolveZone returns the mapping for zone if it lies below a registered
+// domain, or nil if zone can be used as is.
+//
+// With Subzones configured, only the listed zones may be used. Otherwise,
+// zones of more than two labels are looked up with listDomains. A zone
+// found in an account is remembered for the lifetime of the Provider, a
+// zone found in none for unregisteredZoneExpiry; failed lookups are not
+// remembered and return their error.
+func (p *Provider) resolveZone(ctx context.Context, zone string) (*subzone, error) {
+	zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+
+	if len(p.Subzones) > 0 {
+		for name, domain := range p.Subzones {
+			name = strings.ToLower(strings.TrimSuffix(name, "."))
+			domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+			if name != zone {
+				continue
+			}
+			if domain == zone {
+				return nil, nil
+			}
+			if !hasZoneSuffix(zone, domain) {
+				return nil, fmt.Errorf("subzone %s is not below domain %s", zone, domain)
+			}
+			return &subzone{zone: zone, domain: domain, prefix: zone[:len(zone)-len(domain)-1]}, nil
+		}
+		return nil, fmt.Errorf("zone %s is not one of the configured subzones", zone)
+	}
+
+	if strings.Count(zone, ".") < 2 {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	entry, ok := p.subzones[zone]
+	p.mu.Unlock()
+	if ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		return entry.sub, nil
+	}
+
+	domain, err := p.registeredDomain(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the registered domain of %s: %w", zone, err)
+	}
+
+	entry = subzoneEntry{}
+	switch {
+	case domain == "":
+		entry.expires = time.Now().Add(unregisteredZoneExpiry)
+	case domain != zone:
+		entry.sub = &subzone{zone: zone, domain: domain, prefix: zone[:len(zone)-len(domain)-1]}
+	}
+
+	p.mu.Lock()
+	if p.subzones == nil {
+		p.subzones = make(map[string]subzoneEntry)
+	}
+	p.subzones[zone] = entry
+	p.mu.Unlock()
+
+	return entry.sub, nil
+}
+
+// inSubzones reports whether name lies inside one of the configured Subzones
+func (p *Provider) inSubzones(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for zone := range p.Subzones {
+		zone = strings.ToLower(strings.TrimSuffix(zone, "."))
+		if name == zone || hasZoneSuffix(name, zone) {
+			return true
+		}
+	}
+	return false
+}
+
+// registeredDomain returns the domain that zone lies in, or "" if none of
+// the accounts holds it. Each candidate, from zone itself up to its
+// two-label parent, is looked for in the listDomains of the account that
+// requests for it are routed to; every account is listed at most once.
+// Candidates routed to no account are skipped when the Provider has no
+// keys of its own.
+func (p *Provider) registeredDomain(ctx context.Context, zone string) (string, error) {
+	listed := make(map[*Account][]string)
+	for name := zone; strings.Contains(name, "."); name = name[strings.Index(name, ".")+1:] {
+		account := p.account(name)
+		if account == nil && p.TokenSource == nil && len(p.staticTokens()) == 0 {
+			continue
+		}
+
+		domains, ok := listed[account]
+		if !ok {
+			var response listDomainsResponse
+			if err := p.callAPIFor(ctx, name, "listDomains", map[string]string{}, &response); err != nil {
+				return "", err
+			}
+			domains = response.Domains
+			listed[account] = domains
+		}
+
+		for _, domain := range domains {
+			if strings.EqualFold(strings.TrimSuffix(domain, "."), name) {
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// withSubzone runs op on zone, or, if zone lies below a registered domain,
+// on that domain with the record names prefixed accordingly. Returned
+// records are named relative to zone again.
+func (p *Provider) withSubzone(ctx context.Context, zone string, records []libdns.Record, op func(context.Context, string, []libdns.Record) ([]libdns.Record, error)) ([]libdns.Record, error) {
+	sub, err := p.resolveZone(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return op(ctx, zone, records)
+	}
+
+	mapped := make([]libdns.Record, 0, len(records))
+	for _, record := range records {
+		rec, err := sub.toDomain(record)
+		if err != nil {
+			return nil, err
+		}
+		mapped = append(mapped, rec)
+	}
+
+	results, err := op(ctx, sub.domain, mapped)
+	return sub.fromDomainAll(results), err
+}
+
+// checkRecordInZone returns an error unless the record with the given ID
+// lies inside the subzone, so that a subzone cannot reach the rest of its
+// domain by record ID
+func (p *Provider) checkRecordInZone(ctx context.Context, sub *subzone, id string) error {
+	records, err := p.getRecords(ctx, sub.domain)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve existing records: %w", err)
+	}
+	for _, record := range records {
+		if recordID, _ := RecordID(record); recordID == id {
+			if _, ok := sub.fromDomain(record); !ok {
+				return fmt.Errorf("record %s is outside zone %s", id, sub.zone)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("record %s not found in zone %s", id, sub.zone)
+}
+
+// mapPlan returns plan with its zone and records translated by mapRecord
+func mapPlan(plan *SyncPlan, zone string, mapRecord func(libdns.Record) (libdns.Record, error)) (*SyncPlan, error) {
+	mapped := &SyncPlan{Zone: zone}
+	for _, change := range plan.Changes {
+		var err error
+		if change.Record != nil {
+			if change.Record, err = mapRecord(change.Record); err != nil {
+				return nil, err
+			}
+		}
+		if change.Existing != nil {
+			if change.Existing, err = mapRecord(change.Existing); err != nil {
+				return nil, err
+			}
+		}
+		mapped.Changes = append(mapped.Changes, change)
+	}
+	return mapped, nil
+}
@@ -0,0 +1,273 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestRegisteredZoneDetection(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "test-token"
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "app.internal", Value: "192.0.2.2", TTL: 3600},
+	)
+
+	metrics := &testMetrics{}
+	provider := &Provider{APIToken: "test-token", HTTPClient: srv.Client(), Metrics: metrics}
+	ctx := context.Background()
+	const zone = "app.internal.example.com."
+
+	added, err := provider.AppendRecords(ctx, zone, []libdns.Record{
+		libdns.TXT{Name: "_acme-challenge", Text: "token"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if len(added) != 1 || added[0].RR().Name != "_acme-challenge" {
+		t.Errorf("Expected the record named relative to the zone, got %+v", added)
+	}
+
+	var hosts []string
+	for _, rec := range srv.Records("example.com") {
+		hosts = append(hosts, rec.Host)
+	}
+	if !strings.Contains(strings.Join(hosts, ","), "_acme-challenge.app.internal.example.com") {
+		t.Errorf("Expected the host prefixed with the zone labels, got %v", hosts)
+	}
+
+	records, err := provider.GetRecords(ctx, zone)
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	var names []string
+	for _, rec := range records {
+		names = append(names, rec.RR().Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "@,_acme-challenge" {
+		t.Errorf("Expected only records of the zone, got %v", names)
+	}
+
+	deleted, err := provider.DeleteRecords(ctx, zone, []libdns.Record{libdns.TXT{Name: "_acme-challenge"}})
+	if err != nil {
+		t.Fatalf("DeleteRecords failed: %v", err)
+	}
+	if len(deleted) != 1 || len(srv.Records("example.com")) != 2 {
+		t.Errorf("Expected the challenge record deleted, got %+v", deleted)
+	}
+
+	lookups := 0
+	for _, call := range metrics.calls {
+		if call.Operation == "listDomains" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected the registered domain to be looked up once, got %d lookups", lookups)
+	}
+}
+
+func TestRegisteredZoneLookupFailure(t *testing.T) {
+	calls := map[string]int{}
+	failing := true
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		operation := path.Base(r.URL.Path)
+		calls[operation]++
+		switch {
+		case operation == "listDomains" && failing:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case operation == "listDomains":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domains><domain>example.net</domain></domains></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetRecords(ctx, "app.example.com"); err == nil {
+			t.Fatalf("Call %d: expected the failed lookup to be returned", i+1)
+		}
+	}
+	if calls["listDomains"] != 2 || calls["dnsListRecords"] != 0 {
+		t.Errorf("Expected a lookup per call and no listing, got %v", calls)
+	}
+
+	// A zone in no account is remembered for a while
+	failing = false
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetRecords(ctx, "app.example.com"); err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+	}
+	if calls["listDomains"] != 3 {
+		t.Errorf("Expected the unregistered zone to be remembered, got %d lookups", calls["listDomains"])
+	}
+
+	provider.mu.Lock()
+	entry := provider.subzones["app.example.com"]
+	entry.expires = time.Now().Add(-time.Second)
+	provider.subzones["app.example.com"] = entry
+	provider.mu.Unlock()
+	if _, err := provider.GetRecords(ctx, "app.example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if calls["listDomains"] != 4 {
+		t.Errorf("Expected the zone to be looked up again once expired, got %d lookups", calls["listDomains"])
+	}
+}
+
+func TestRegisteredZoneDetectionAccounts(t *testing.T) {
+	var requests []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		operation := strings.TrimPrefix(r.URL.Path, "/api/")
+		requests = append(requests, operation+" "+q.Get("domain")+" "+q.Get("key"))
+		switch {
+		case q.Get("key") != "org-key":
+			w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+		case operation == "listDomains":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><domains><domain>example.org</domain></domains></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>www.dev.example.org</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+		}
+	})
+	// Only the account has keys, so the zone must be looked up with them
+	provider.APIToken = ""
+	provider.Accounts = []Account{{Zones: []string{"example.org"}, APIToken: "org-key"}}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		records, err := provider.GetRecords(ctx, "dev.example.org")
+		if err != nil {
+			t.Fatalf("GetRecords failed: %v", err)
+		}
+		if len(records) != 1 || records[0].RR().Name != "www" {
+			t.Errorf("Expected the record named relative to the subzone, got %+v", records)
+		}
+	}
+
+	want := []string{
+		"listDomains  org-key",
+		"dnsListRecords example.org org-key",
+		"dnsListRecords example.org org-key",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected requests:\n%s\nwant\n%s", strings.Join(requests, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestSubzoneMapping(t *testing.T) {
+	sub := &subzone{zone: "dev.example.com", domain: "example.com", prefix: "dev"}
+
+	tests := []struct {
+		name, toDomain string
+	}{
+		{"@", "dev"},
+		{"www", "www.dev"},
+		{"www.dev.example.com.", "www.dev"},
+		{"dev.example.com.", "dev"},
+	}
+	for _, tt := range tests {
+		rec, err := sub.toDomain(libdns.RR{Name: tt.name, Type: "A", Data: "192.0.2.1"})
+		if err != nil {
+			t.Fatalf("toDomain(%q) failed: %v", tt.name, err)
+		}
+		if got := rec.RR().Name; got != tt.toDomain {
+			t.Errorf("toDomain(%q) = %q, want %q", tt.name, got, tt.toDomain)
+		}
+	}
+
+	if _, err := sub.toDomain(libdns.RR{Name: "www.example.com.", Type: "A"}); err == nil {
+		t.Error("Expected a name outside the zone to be rejected")
+	}
+
+	srv, _ := sub.toDomain(libdns.SRV{Service: "sip", Transport: "tcp", Name: "@", Target: "sip.example.com"})
+	if got := srv.RR().Name; got != "_sip._tcp.dev" {
+		t.Errorf("Expected SRV owner under the prefix, got %q", got)
+	}
+
+	if rec, ok := sub.fromDomain(libdns.RR{Name: "www.dev", Type: "A"}); !ok || rec.RR().Name != "www" {
+		t.Errorf("Expected www.dev to map to www, got %q (%v)", rec.RR().Name, ok)
+	}
+	if _, ok := sub.fromDomain(libdns.RR{Name: "www", Type: "A"}); ok {
+		t.Error("Expected a record outside the zone to be dropped")
+	}
+}
+
+func TestConfiguredSubzones(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "test-token"
+	srv.AddZone("example.com",
+		namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "dev", Value: "192.0.2.2", TTL: 3600},
+		namesilotest.Record{Type: "A", Host: "api.dev", Value: "192.0.2.3", TTL: 3600},
+	)
+
+	metrics := &testMetrics{}
+	provider := &Provider{
+		APIToken:   "test-token",
+		HTTPClient: srv.Client(),
+		Metrics:    metrics,
+		Subzones:   map[string]string{"dev.example.com": "example.com"},
+	}
+	ctx := context.Background()
+
+	if _, err := provider.GetRecords(ctx, "example.com"); err == nil {
+		t.Error("Expected a zone that is not configured to be refused")
+	}
+
+	// Replace the whole subzone; records outside it must survive
+	plan, err := provider.SyncZone(ctx, "dev.example.com.", []libdns.Record{
+		libdns.RR{Name: "@", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+		libdns.TXT{Name: "build", Text: "42", TTL: time.Hour},
+	}, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+	if plan.Zone != "dev.example.com." || len(plan.Changes) != 2 {
+		t.Errorf("Expected 2 changes in the subzone, got %+v", plan)
+	}
+
+	var hosts []string
+	for _, rec := range srv.Records("example.com") {
+		hosts = append(hosts, rec.Type+" "+rec.Host)
+	}
+	sort.Strings(hosts)
+	want := "A dev.example.com,A www.example.com,TXT build.dev.example.com"
+	if strings.Join(hosts, ",") != want {
+		t.Errorf("Expected %s, got %v", want, hosts)
+	}
+
+	var outsideID string
+	for _, rec := range srv.Records("example.com") {
+		if rec.Host == "www.example.com" {
+			outsideID = rec.ID
+		}
+	}
+	if err := provider.DeleteRecordByID(ctx, "dev.example.com", outsideID); err == nil {
+		t.Error("Expected deleting a record outside the subzone to be refused")
+	}
+	if len(srv.Records("example.com")) != 3 {
+		t.Error("Expected the record outside the subzone to remain")
+	}
+
+	for _, call := range metrics.calls {
+		if call.Operation == "listDomains" {
+			t.Error("Expected configured subzones not to list the account's domains")
+		}
+	}
+}
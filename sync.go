@@ -0,0 +1,283 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/libdns/libdns"
+)
+
+// SyncAction is the kind of change SyncZone makes to a record.
+type SyncAction string
+
+// Sync actions.
+const (
+	SyncCreate SyncAction = "create"
+	SyncUpdate SyncAction = "update"
+	SyncDelete SyncAction = "delete"
+)
+
+// SyncChange is a single planned change to a zone.
+type SyncChange struct {
+	Action SyncAction
+
+	// Record is the desired record for creates and updates, and the record
+	// being removed for deletes.
+	Record libdns.Record
+
+	// Existing is the live record being updated or deleted; nil for creates.
+	Existing libdns.Record
+}
+
+// SyncPlan is the list of changes needed to reconcile a zone with a desired state.
+type SyncPlan struct {
+	Zone    string
+	Changes []SyncChange
+}
+
+// Empty reports whether the zone is already in the desired state.
+func (p *SyncPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// SyncOptions controls how SyncZone reconciles a zone.
+type SyncOptions struct {
+	// DryRun computes the plan without applying it.
+	DryRun bool
+
+	// Types restricts the sync to these record types; live records of
+	// other types are left alone. Empty means all types.
+	Types []string
+
+	// NoDelete keeps live records that are not part of the desired state.
+	NoDelete bool
+}
+
+// manages reports whether a record type is in scope of the sync
+func (o SyncOptions) manages(recordType string) bool {
+	if len(o.Types) == 0 {
+		return true
+	}
+	for _, t := range o.Types {
+		if strings.EqualFold(t, recordType) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncZone reconciles the zone with the desired records: records missing
+// from the zone are created, records whose TTL or data differ are updated in
+// place, and live records not in the desired state are deleted (unless
+// NoDelete is set). Apex NS records are managed by NameSilo and are never
+// deleted.
+//
+// It returns the plan that was applied (or, with DryRun, that would be).
+// If applying fails, the returned plan contains only the changes that were
+// applied before the error.
+func (p *Provider) SyncZone(ctx context.Context, zone string, desired []libdns.Record, opts SyncOptions) (*SyncPlan, error) {
+	plan, err := p.PlanZoneSync(ctx, zone, desired, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		return plan, nil
+	}
+
+	return p.ApplySyncPlan(ctx, plan)
+}
+
+// PlanZoneSync computes the changes SyncZone would make without applying them.
+func (p *Provider) PlanZoneSync(ctx context.Context, zone string, desired []libdns.Record, opts SyncOptions) (*SyncPlan, error) {
+	existingRecords, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve existing records: %w", err)
+	}
+
+	// The apex of a subzone is an ordinary host of its domain
+	sub, err := p.resolveZone(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		if desired, err = p.apexAliases(zone, desired); err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []string
+	existingSets := make(map[string][]namesileoRecord)
+	desiredSets := make(map[string][]libdns.Record)
+	addKey := func(key string) {
+		if _, ok := existingSets[key]; ok {
+			return
+		}
+		if _, ok := desiredSets[key]; ok {
+			return
+		}
+		keys = append(keys, key)
+	}
+
+	for _, record := range desired {
+		rr := record.RR()
+		if !opts.manages(rr.Type) {
+			continue
+		}
+		key := rrsetKey(zone, record)
+		addKey(key)
+		desiredSets[key] = append(desiredSets[key], record)
+	}
+
+	for _, rec := range existingRecords {
+		nsRec, ok := rec.(namesileoRecord)
+		rr := rec.RR()
+		if !ok || !opts.manages(rr.Type) || isManagedByNameSilo(zone, rr) {
+			continue
+		}
+		key := rrsetKey(zone, rec)
+		addKey(key)
+		existingSets[key] = append(existingSets[key], nsRec)
+	}
+
+	plan := &SyncPlan{Zone: zone}
+	for _, key := range keys {
+		changes, _ := p.diffRRset(zone, existingSets[key], desiredSets[key], !opts.NoDelete)
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	return plan, nil
+}
+
+// ApplySyncPlan applies the changes of a plan in order. On error, it returns
+// a plan containing the changes that were applied.
+func (p *Provider) ApplySyncPlan(ctx context.Context, plan *SyncPlan) (*SyncPlan, error) {
+	sub, err := p.resolveZone(ctx, plan.Zone)
+	if err != nil {
+		return &SyncPlan{Zone: plan.Zone}, err
+	}
+	if sub == nil {
+		return p.applySyncPlan(ctx, plan)
+	}
+
+	mapped, err := mapPlan(plan, sub.domain, sub.toDomain)
+	if err != nil {
+		return &SyncPlan{Zone: plan.Zone}, err
+	}
+	applied, err := p.applySyncPlan(ctx, mapped)
+	back, _ := mapPlan(applied, plan.Zone, func(record libdns.Record) (libdns.Record, error) {
+		rec, _ := sub.fromDomain(record)
+		return rec, nil
+	})
+	return back, err
+}
+
+// applySyncPlan implements ApplySyncPlan for a registered domain
+func (p *Provider) applySyncPlan(ctx context.Context, plan *SyncPlan) (*SyncPlan, error) {
+	applied := &SyncPlan{Zone: plan.Zone}
+
+	for _, change := range plan.Changes {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		change, err := p.applyChange(ctx, plan.Zone, change)
+		if err != nil {
+			return applied, err
+		}
+		applied.Changes = append(applied.Changes, change)
+	}
+
+	return applied, nil
+}
+
+// applyChange performs a single planned change. It returns the change as
+// applied, with Record carrying the NameSilo record ID for creates and
+// updates.
+func (p *Provider) applyChange(ctx context.Context, zone string, change SyncChange) (SyncChange, error) {
+	switch change.Action {
+	case SyncCreate:
+		if err := p.validateRecords([]libdns.Record{change.Record}); err != nil {
+			return change, err
+		}
+		added, err := p.addRecord(ctx, zone, change.Record, true)
+		if err != nil {
+			return change, fmt.Errorf("failed to add record: %w", err)
+		}
+		change.Record = added
+	case SyncUpdate:
+		existing, ok := change.Existing.(namesileoRecord)
+		if !ok {
+			return change, fmt.Errorf("cannot update record without a NameSilo record ID")
+		}
+		id, err := p.updateRecord(ctx, zone, existing.ID, existing, change.Record)
+		if err != nil {
+			return change, fmt.Errorf("failed to update record: %w", err)
+		}
+		change.Record = withRecordID(change.Record, id)
+	case SyncDelete:
+		existing, ok := change.Existing.(namesileoRecord)
+		if !ok {
+			return change, fmt.Errorf("cannot delete record without a NameSilo record ID")
+		}
+		if err := p.deleteRecordByID(ctx, zone, existing.ID, existing); err != nil {
+			return change, fmt.Errorf("failed to delete stale record: %w", err)
+		}
+	default:
+		return change, fmt.Errorf("unknown sync action %q", change.Action)
+	}
+
+	return change, nil
+}
+
+// diffRRset computes the changes that turn the existing records of an RRset
+// into the desired ones. Existing records whose data already matches are
+// kept (updating the TTL if needed), leftover existing records are reused
+// for new data so the name never disappears from the zone, and the rest are
+// deleted if prune is set. It also returns the desired records that need no
+// change.
+func (p *Provider) diffRRset(zone string, existing []namesileoRecord, desired []libdns.Record, prune bool) ([]SyncChange, []libdns.Record) {
+	var changes []SyncChange
+	var unchanged []libdns.Record
+	var toCreate []libdns.Record
+
+	for _, record := range desired {
+		idx := p.indexOfSameData(zone, existing, record)
+		if idx < 0 {
+			toCreate = append(toCreate, record)
+			continue
+		}
+
+		match := existing[idx]
+		existing = append(existing[:idx:idx], existing[idx+1:]...)
+		if p.recordTTL(match.RR().TTL) != p.recordTTL(record.RR().TTL) {
+			changes = append(changes, SyncChange{Action: SyncUpdate, Record: record, Existing: match})
+		} else {
+			unchanged = append(unchanged, record)
+		}
+	}
+
+	for _, record := range toCreate {
+		if len(existing) > 0 {
+			changes = append(changes, SyncChange{Action: SyncUpdate, Record: record, Existing: existing[0]})
+			existing = existing[1:]
+			continue
+		}
+		changes = append(changes, SyncChange{Action: SyncCreate, Record: record})
+	}
+
+	if prune {
+		for _, stale := range existing {
+			changes = append(changes, SyncChange{Action: SyncDelete, Record: stale, Existing: stale})
+		}
+	}
+
+	return changes, unchanged
+}
+
+// isManagedByNameSilo reports whether a record is maintained by NameSilo
+// itself and must not be removed by a sync
+func isManagedByNameSilo(zone string, rr libdns.RR) bool {
+	recordType := strings.ToUpper(rr.Type)
+	return recordType == "SOA" || (recordType == "NS" && normalizeRecordName(rr.Name, zone) == "@")
+}
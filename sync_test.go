@@ -0,0 +1,88 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const syncTestZone = `<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>ns1</record_id><type>NS</type><host>example.com</host><value>ns1.dnsowl.com</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>a1</record_id><type>A</type><host>example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>a2</record_id><type>A</type><host>www.example.com</host><value>192.0.2.2</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>t1</record_id><type>TXT</type><host>old.example.com</host><value>stale</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`
+
+func newSyncTestProvider(t *testing.T, operations *[]string) *Provider {
+	return newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		op := strings.TrimPrefix(r.URL.Path, "/api/")
+		if op == "dnsListRecords" {
+			w.Write([]byte(syncTestZone))
+			return
+		}
+		q := r.URL.Query()
+		*operations = append(*operations, op+" "+q.Get("rrid")+" "+q.Get("rrhost")+" "+q.Get("rrvalue")+" "+q.Get("rrttl"))
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+}
+
+func TestSyncZone(t *testing.T) {
+	var operations []string
+	provider := newSyncTestProvider(t, &operations)
+
+	desired := []libdns.Record{
+		libdns.RR{Name: "@", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: 2 * time.Hour},
+		libdns.CNAME{Name: "blog", Target: "example.net", TTL: time.Hour},
+	}
+
+	plan, err := provider.SyncZone(context.Background(), "example.com.", desired, SyncOptions{})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+
+	want := []string{
+		"dnsUpdateRecord a2 www 192.0.2.2 7200",
+		"dnsAddRecord  blog example.net 3600",
+		"dnsDeleteRecord t1   ",
+	}
+	if strings.Join(operations, "|") != strings.Join(want, "|") {
+		t.Errorf("Unexpected operations:\n got: %v\nwant: %v", operations, want)
+	}
+	if len(plan.Changes) != 3 {
+		t.Errorf("Expected 3 applied changes, got %d", len(plan.Changes))
+	}
+}
+
+func TestSyncZoneDryRunAndOptions(t *testing.T) {
+	var operations []string
+	provider := newSyncTestProvider(t, &operations)
+
+	desired := []libdns.Record{
+		libdns.RR{Name: "@", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+	}
+
+	plan, err := provider.SyncZone(context.Background(), "example.com.", desired, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+	if len(operations) != 0 {
+		t.Errorf("Dry run made changes: %v", operations)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Action != SyncDelete {
+		t.Errorf("Expected a single planned delete, got %+v", plan.Changes)
+	}
+
+	plan, err = provider.PlanZoneSync(context.Background(), "example.com.", desired, SyncOptions{Types: []string{"A"}})
+	if err != nil {
+		t.Fatalf("PlanZoneSync failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected empty plan when only A records are managed, got %+v", plan.Changes)
+	}
+}
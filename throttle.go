@@ -0,0 +1,94 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const defaultThrottleRetries = 5
+
+// throttleReplyCodes are NameSilo reply codes that indicate the account is
+// sending requests too quickly
+var throttleReplyCodes = map[int]bool{
+	400: true, // Existing API request is still processing
+}
+
+// throttleRetries returns how often a throttled request is retried
+func (p *Provider) throttleRetries() int {
+	if p.ThrottleRetries < 0 {
+		return 0
+	}
+	if p.ThrottleRetries == 0 {
+		return defaultThrottleRetries
+	}
+	return p.ThrottleRetries
+}
+
+// throttleDelay returns how long to back off after the given number of
+// throttled attempts. A Retry-After header sent by NameSilo takes
+// precedence over exponential backoff.
+func (p *Provider) throttleDelay(throttles int, err error) time.Duration {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		if statusErr.RetryAfter > maxRetryDelay {
+			return maxRetryDelay
+		}
+		return statusErr.RetryAfter
+	}
+	return p.jitter(p.retryDelay(throttles))
+}
+
+// backOff makes every request of the Provider wait until delay has passed,
+// so that concurrent operations slow down together once NameSilo starts
+// throttling
+func (p *Provider) backOff(delay time.Duration) {
+	until := time.Now().Add(delay)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if until.After(p.throttledUntil) {
+		p.throttledUntil = until
+	}
+}
+
+// waitForBackOff blocks until a back-off set by backOff has passed or the
+// context is done
+func (p *Provider) waitForBackOff(ctx context.Context) error {
+	p.mu.Lock()
+	wait := time.Until(p.throttledUntil)
+	p.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date. It returns 0 if the header is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
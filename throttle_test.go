@@ -0,0 +1,109 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestThrottledRequestsAreRetried(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Write([]byte(`<namesilo><reply><code>400</code><detail>Existing API request is still processing</detail></reply></namesilo>`))
+		default:
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+	provider.RetryBaseDelay = time.Millisecond
+
+	// MaxRetries is zero, so only the throttling budget applies
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("Expected throttled requests to be retried, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestThrottleRetriesExhausted(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	provider.RetryBaseDelay = time.Millisecond
+	provider.ThrottleRetries = 2
+
+	_, err := provider.GetRecords(context.Background(), "example.com")
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("Expected HTTP 429 error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+
+	attempts = 0
+	provider.ThrottleRetries = -1
+	provider.GetRecords(context.Background(), "example.com")
+	if attempts != 1 {
+		t.Errorf("Expected no retries when disabled, got %d attempts", attempts)
+	}
+}
+
+func TestThrottleHonorsRetryAfter(t *testing.T) {
+	attempts := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.RetryBaseDelay = time.Millisecond
+
+	start := time.Now()
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected to wait for Retry-After, waited %v", elapsed)
+	}
+}
+
+func TestThrottleDelaysOtherRequests(t *testing.T) {
+	provider := &Provider{}
+	provider.backOff(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := provider.waitForBackOff(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected requests to wait for the back-off, got %v", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"7":                             7 * time.Second,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 12:00:30 GMT": 30 * time.Second,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	}
+	for value, want := range tests {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package namesilo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// TLSA is a DANE TLSA record (RFC 6698). libdns has no TLSA type, so
+// GetRecords returns TLSA records in this form and AppendRecords and
+// SetRecords accept it as well as a libdns.RR with Type "TLSA".
+type TLSA struct {
+	// Name is the owner name, normally of the form "_port._proto.host",
+	// e.g. "_443._tcp.www" or "_25._tcp.mail".
+	Name string
+	TTL  time.Duration
+
+	// Usage is the certificate usage (0-3, e.g. 3 for DANE-EE).
+	Usage uint8
+
+	// Selector selects the full certificate (0) or its public key (1).
+	Selector uint8
+
+	// MatchingType is 0 for the full data, 1 for SHA-256 and 2 for SHA-512.
+	MatchingType uint8
+
+	// CertData is the certificate association data in hex.
+	CertData string
+}
+
+// RR implements libdns.Record.
+func (t TLSA) RR() libdns.RR {
+	return libdns.RR{
+		Name: t.Name,
+		TTL:  t.TTL,
+		Type: "TLSA",
+		Data: fmt.Sprintf("%d %d %d %s", t.Usage, t.Selector, t.MatchingType, strings.ToLower(t.CertData)),
+	}
+}
+
+// validateTLSA checks that a TLSA record can be created at NameSilo
+func validateTLSA(t TLSA) error {
+	if t.Usage > 3 {
+		return fmt.Errorf("TLSA record %q: invalid certificate usage %d", t.Name, t.Usage)
+	}
+	if t.Selector > 1 {
+		return fmt.Errorf("TLSA record %q: invalid selector %d", t.Name, t.Selector)
+	}
+	if t.MatchingType > 2 {
+		return fmt.Errorf("TLSA record %q: invalid matching type %d", t.Name, t.MatchingType)
+	}
+
+	data, err := hex.DecodeString(t.CertData)
+	if err != nil || len(data) == 0 {
+		return fmt.Errorf("TLSA record %q: certificate data must be non-empty hex", t.Name)
+	}
+	if want := map[uint8]int{1: 32, 2: 64}[t.MatchingType]; want != 0 && len(data) != want {
+		return fmt.Errorf("TLSA record %q: matching type %d expects %d bytes of certificate data, got %d", t.Name, t.MatchingType, want, len(data))
+	}
+	return nil
+}
+
+// parseTLSA parses TLSA data of the form "usage selector matching-type
+// data"; the hex data may be split across several fields as in master files
+func parseTLSA(name string, ttl time.Duration, data string) (TLSA, error) {
+	fields := strings.Fields(data)
+	if len(fields) < 4 {
+		return TLSA{}, fmt.Errorf("TLSA record %s: malformed value %q, expected \"usage selector matching-type data\"", name, data)
+	}
+
+	var params [3]uint8
+	for i, field := range fields[:3] {
+		n, err := strconv.ParseUint(field, 10, 8)
+		if err != nil {
+			return TLSA{}, fmt.Errorf("TLSA record %s: invalid field %q", name, field)
+		}
+		params[i] = uint8(n)
+	}
+
+	t := TLSA{
+		Name:         name,
+		TTL:          ttl,
+		Usage:        params[0],
+		Selector:     params[1],
+		MatchingType: params[2],
+		CertData:     strings.ToLower(strings.Join(fields[3:], "")),
+	}
+	if err := validateTLSA(t); err != nil {
+		return TLSA{}, err
+	}
+	return t, nil
+}
@@ -0,0 +1,85 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+const testTLSAHash = "0c72ac70b745ac19998811b131d662c9ac69dbdbe7cb23e5b514b56664c5d3d6"
+
+func TestParseTLSA(t *testing.T) {
+	got, err := parseTLSA("_443._tcp.www", time.Hour, "3 1 1 "+strings.ToUpper(testTLSAHash[:32])+" "+testTLSAHash[32:])
+	if err != nil {
+		t.Fatalf("parseTLSA failed: %v", err)
+	}
+	want := TLSA{Name: "_443._tcp.www", TTL: time.Hour, Usage: 3, Selector: 1, MatchingType: 1, CertData: testTLSAHash}
+	if got != want {
+		t.Errorf("parseTLSA = %+v, want %+v", got, want)
+	}
+	if rr := got.RR(); rr.Type != "TLSA" || rr.Data != "3 1 1 "+testTLSAHash {
+		t.Errorf("Unexpected RR: %+v", rr)
+	}
+}
+
+func TestParseTLSAErrors(t *testing.T) {
+	for _, data := range []string{
+		"3 1 1",
+		"4 1 1 " + testTLSAHash,
+		"3 2 1 " + testTLSAHash,
+		"3 1 3 " + testTLSAHash,
+		"3 1 1 zz",
+		"3 1 2 " + testTLSAHash,
+		"x 1 1 " + testTLSAHash,
+	} {
+		if _, err := parseTLSA("_443._tcp", 0, data); err == nil {
+			t.Errorf("Expected error for %q", data)
+		}
+	}
+}
+
+func TestTLSARecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		TLSA{Name: "_443._tcp.www", TTL: time.Hour, Usage: 3, Selector: 1, MatchingType: 1, CertData: testTLSAHash},
+		libdns.RR{Name: "_25._tcp.mail", Type: "TLSA", Data: "2 0 1 " + strings.ToUpper(testTLSAHash)},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	records, err := provider.GetRecords(ctx, "example.com.")
+	if err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	var found int
+	for _, rec := range records {
+		tlsa, ok := rec.(namesileoRecord).Record.(TLSA)
+		if !ok {
+			continue
+		}
+		found++
+		if tlsa.CertData != testTLSAHash {
+			t.Errorf("Unexpected certificate data: %q", tlsa.CertData)
+		}
+	}
+	if found != 2 {
+		t.Errorf("Expected 2 TLSA records, got %d", found)
+	}
+
+	_, err = provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		TLSA{Name: "_443._tcp", Usage: 3, Selector: 1, MatchingType: 1, CertData: "abcd"},
+	})
+	if err == nil {
+		t.Error("Expected error for truncated SHA-256 data")
+	}
+}
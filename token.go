@@ -0,0 +1,37 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TokenSource supplies the NameSilo API key. Token is called before every
+// API call, so implementations that fetch keys remotely should cache them.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// FileTokenSource reads the API key from a file on every call, e.g. a
+// mounted Kubernetes secret that is updated in place on rotation.
+// Surrounding whitespace is ignored.
+type FileTokenSource string
+
+// Token reads the key from the file.
+func (f FileTokenSource) Token(ctx context.Context) (string, error) {
+	data, err := os.ReadFile(string(f))
+	if err != nil {
+		return "", fmt.Errorf("reading API token file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
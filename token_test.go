@@ -0,0 +1,59 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTokenSource(t *testing.T) {
+	var used []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		used = append(used, r.URL.Query().Get("key"))
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.APIToken = ""
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("first\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider.TokenSource = FileTokenSource(path)
+	ctx := context.Background()
+
+	if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+		t.Fatalf("ListDomains failed: %v", err)
+	}
+
+	// Rotate the key without touching the Provider
+	if err := os.WriteFile(path, []byte("second"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.ListDomains(ctx, ListDomainsOptions{}); err != nil {
+		t.Fatalf("ListDomains failed: %v", err)
+	}
+
+	if got := strings.Join(used, ","); got != "first,second" {
+		t.Errorf("Unexpected keys %s", got)
+	}
+	if got := provider.redact("key=first key=second"); got != "key="+redactedToken+" key="+redactedToken {
+		t.Errorf("Token source keys not redacted: %s", got)
+	}
+}
+
+func TestTokenSourceError(t *testing.T) {
+	sourceErr := errors.New("vault unavailable")
+	provider := &Provider{
+		TokenSource: TokenSourceFunc(func(ctx context.Context) (string, error) {
+			return "", sourceErr
+		}),
+	}
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); !errors.Is(err, sourceErr) {
+		t.Errorf("Expected token source error, got %v", err)
+	}
+}
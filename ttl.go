@@ -0,0 +1,159 @@
+package namesilo
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// TTLPolicy decides what happens to a record TTL below the minimum.
+type TTLPolicy string
+
+// TTL policies.
+const (
+	// TTLUseDefault replaces a TTL below the minimum with the default TTL.
+	TTLUseDefault TTLPolicy = "default"
+
+	// TTLClamp raises a TTL below the minimum to the minimum.
+	TTLClamp TTLPolicy = "clamp"
+
+	// TTLError rejects records with a TTL below the minimum before any
+	// change is made.
+	TTLError TTLPolicy = "error"
+)
+
+// minRecordTTL returns the configured minimum TTL in seconds
+func (p *Provider) minRecordTTL() int {
+	if p.MinTTL > 0 {
+		return int(p.MinTTL.Seconds())
+	}
+	return minTTL
+}
+
+// defaultRecordTTL returns the configured default TTL in seconds
+func (p *Provider) defaultRecordTTL() int {
+	if p.DefaultTTL > 0 {
+		return int(p.DefaultTTL.Seconds())
+	}
+	return defaultTTL
+}
+
+// recordTTL returns the TTL in seconds to send for a record, applying
+// TTLPolicy and TTLSnap. Records without a TTL always get the default TTL.
+func (p *Provider) recordTTL(ttl time.Duration) int {
+	return p.snapTTL(p.policyTTL(ttl))
+}
+
+// policyTTL returns the TTL in seconds after applying TTLPolicy
+func (p *Provider) policyTTL(ttl time.Duration) int {
+	seconds := int(ttl.Seconds())
+	if seconds <= 0 {
+		return p.defaultRecordTTL()
+	}
+	if seconds >= p.minRecordTTL() {
+		return seconds
+	}
+
+	switch p.TTLPolicy {
+	case TTLClamp, TTLError:
+		// TTLError records were rejected by checkTTL already
+		return p.minRecordTTL()
+	default:
+		return p.defaultRecordTTL()
+	}
+}
+
+// checkTTL rejects a record whose TTL is below the minimum when TTLPolicy
+// is TTLError, or not one of the accepted TTLs when TTLSnap is TTLSnapError
+func (p *Provider) checkTTL(record libdns.Record) error {
+	rr := record.RR()
+	seconds := int(rr.TTL.Seconds())
+	if seconds <= 0 {
+		return nil
+	}
+
+	if p.TTLPolicy == TTLError && seconds < p.minRecordTTL() {
+		return fmt.Errorf("%s record %q: TTL %s is below the minimum of %ds", rr.Type, rr.Name, rr.TTL, p.minRecordTTL())
+	}
+	if p.TTLSnap == TTLSnapError {
+		if seconds = p.policyTTL(rr.TTL); p.snapTTL(seconds) != seconds {
+			return fmt.Errorf("%s record %q: TTL %s is not accepted by NameSilo, the nearest accepted TTL is %ds", rr.Type, rr.Name, rr.TTL, p.snapTTL(seconds))
+		}
+	}
+	return nil
+}
+
+// TTLSnap decides what happens to a record TTL that is not one of the
+// TTLs NameSilo accepts.
+type TTLSnap string
+
+// TTL snapping modes.
+const (
+	// TTLSnapOff sends TTLs unchanged and leaves it to NameSilo to accept,
+	// reject or change them.
+	TTLSnapOff TTLSnap = ""
+
+	// TTLSnapNearest replaces a TTL with the nearest accepted TTL, or the
+	// longer one of two equally near.
+	TTLSnapNearest TTLSnap = "nearest"
+
+	// TTLSnapError rejects records with a TTL that is not accepted before
+	// any change is made.
+	TTLSnapError TTLSnap = "error"
+)
+
+// defaultAcceptedTTLs are the TTLs in seconds assumed to be accepted by
+// NameSilo when AcceptedTTLs is empty: 5, 10, 15 and 30 minutes, 1, 2, 4,
+// 8 and 12 hours, 1 and 2 days, and 1 week
+var defaultAcceptedTTLs = []int{300, 600, 900, 1800, 3600, 7200, 14400, 28800, 43200, 86400, 172800, 604800}
+
+// acceptedTTLs returns the accepted TTLs in seconds in increasing order,
+// without those below the minimum TTL
+func (p *Provider) acceptedTTLs() []int {
+	ttls := defaultAcceptedTTLs
+	if len(p.AcceptedTTLs) > 0 {
+		ttls = make([]int, 0, len(p.AcceptedTTLs))
+		for _, ttl := range p.AcceptedTTLs {
+			ttls = append(ttls, int(ttl.Seconds()))
+		}
+		sort.Ints(ttls)
+	}
+
+	accepted := make([]int, 0, len(ttls))
+	for _, ttl := range ttls {
+		if ttl >= p.minRecordTTL() {
+			accepted = append(accepted, ttl)
+		}
+	}
+	return accepted
+}
+
+// snapTTL returns the accepted TTL nearest to seconds when TTLSnap is set,
+// or seconds unchanged
+func (p *Provider) snapTTL(seconds int) int {
+	if p.TTLSnap == TTLSnapOff {
+		return seconds
+	}
+
+	accepted := p.acceptedTTLs()
+	if len(accepted) == 0 {
+		return seconds
+	}
+	nearest := accepted[0]
+	for _, ttl := range accepted[1:] {
+		if abs(ttl-seconds) <= abs(nearest-seconds) {
+			nearest = ttl
+		}
+	}
+	return nearest
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
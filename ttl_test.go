@@ -0,0 +1,147 @@
+package namesilo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestRecordTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider *Provider
+		ttl      time.Duration
+		want     int
+	}{
+		{"unset", &Provider{}, 0, 3600},
+		{"above minimum", &Provider{}, 10 * time.Minute, 600},
+		{"below minimum uses default", &Provider{}, time.Minute, 3600},
+		{"below minimum clamped", &Provider{TTLPolicy: TTLClamp}, time.Minute, 300},
+		{"unset with clamp", &Provider{TTLPolicy: TTLClamp}, 0, 3600},
+		{"custom minimum", &Provider{TTLPolicy: TTLClamp, MinTTL: 15 * time.Minute}, 10 * time.Minute, 900},
+		{"custom default", &Provider{DefaultTTL: 2 * time.Hour}, time.Minute, 7200},
+		{"custom unset", &Provider{DefaultTTL: 2 * time.Hour}, 0, 7200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provider.recordTTL(tt.ttl); got != tt.want {
+				t.Errorf("recordTTL(%s) = %d, want %d", tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTTLErrorPolicy(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), TTLPolicy: TTLError}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "ok", Type: "A", Data: "192.0.2.1", TTL: time.Hour},
+		libdns.RR{Name: "short", Type: "A", Data: "192.0.2.2", TTL: time.Minute},
+	})
+	if err == nil {
+		t.Fatal("Expected error for TTL below minimum")
+	}
+	if n := len(srv.Records("example.com")); n != 0 {
+		t.Errorf("Expected no records to be created, got %d", n)
+	}
+
+	_, err = provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "unset", Type: "A", Data: "192.0.2.3"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if recs := srv.Records("example.com"); len(recs) != 1 || recs[0].TTL != 3600 {
+		t.Errorf("Unexpected records: %+v", recs)
+	}
+}
+
+func TestSnapTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider *Provider
+		ttl      time.Duration
+		want     int
+	}{
+		{"off", &Provider{}, 1000 * time.Second, 1000},
+		{"accepted", &Provider{TTLSnap: TTLSnapNearest}, time.Hour, 3600},
+		{"down", &Provider{TTLSnap: TTLSnapNearest}, 1000 * time.Second, 900},
+		{"up", &Provider{TTLSnap: TTLSnapNearest}, 50 * time.Minute, 3600},
+		{"tie goes up", &Provider{TTLSnap: TTLSnapNearest}, 2700 * time.Second, 3600},
+		{"above all", &Provider{TTLSnap: TTLSnapNearest}, 30 * 24 * time.Hour, 604800},
+		{"unset", &Provider{TTLSnap: TTLSnapNearest, DefaultTTL: 100 * time.Minute}, 0, 7200},
+		{"after policy", &Provider{TTLSnap: TTLSnapNearest, TTLPolicy: TTLClamp, MinTTL: 8 * time.Minute}, time.Minute, 600},
+		{"above minimum", &Provider{TTLSnap: TTLSnapNearest, MinTTL: 620 * time.Second}, 650 * time.Second, 900},
+		{"custom", &Provider{TTLSnap: TTLSnapNearest, AcceptedTTLs: []time.Duration{24 * time.Hour, 10 * time.Minute}}, 3 * time.Hour, 600},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.provider.recordTTL(tt.ttl); got != tt.want {
+				t.Errorf("recordTTL(%s) = %d, want %d", tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTTLSnapError(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), TTLSnap: TTLSnapError}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "ok", Type: "A", Data: "192.0.2.1", TTL: 2 * time.Hour},
+		libdns.RR{Name: "odd", Type: "A", Data: "192.0.2.2", TTL: 1000 * time.Second},
+	})
+	if err == nil || !strings.Contains(err.Error(), "900s") {
+		t.Fatalf("Expected an error naming the nearest accepted TTL, got %v", err)
+	}
+	if n := len(srv.Records("example.com")); n != 0 {
+		t.Errorf("Expected no records to be created, got %d", n)
+	}
+
+	// Low TTLs replaced by TTLPolicy are not rejected
+	if _, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "short", Type: "A", Data: "192.0.2.3", TTL: time.Minute},
+	}); err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+	if recs := srv.Records("example.com"); len(recs) != 1 || recs[0].TTL != 3600 {
+		t.Errorf("Unexpected records: %+v", recs)
+	}
+}
+
+func TestTTLSnapSyncIsStable(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), TTLSnap: TTLSnapNearest}
+	ctx := context.Background()
+
+	records := []libdns.Record{libdns.RR{Name: "www", Type: "A", Data: "192.0.2.1", TTL: 1000 * time.Second}}
+	if _, err := provider.SetRecords(ctx, "example.com.", records); err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	if recs := srv.Records("example.com"); len(recs) != 1 || recs[0].TTL != 900 {
+		t.Fatalf("Expected the TTL to be snapped to 900, got %+v", recs)
+	}
+
+	plan, err := provider.SyncZone(ctx, "example.com.", records, SyncOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("SyncZone failed: %v", err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected no changes once the TTL is snapped, got %+v", plan.Changes)
+	}
+}
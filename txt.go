@@ -0,0 +1,81 @@
+package namesilo
+
+import "strings"
+
+// txtValue formats the rrvalue for a TXT record. Values longer than a single
+// character-string (255 bytes), such as DKIM keys, are split into several
+// quoted character-strings. Values that would otherwise be altered on the
+// way back (surrounding whitespace, or text that itself looks quoted) are
+// quoted as well.
+func txtValue(text string) string {
+	if len(text) <= maxTXTStringLength && !needsQuoting(text) {
+		return text
+	}
+	return quoteTXT(text)
+}
+
+// needsQuoting reports whether a TXT text must be quoted to survive a round
+// trip through joinTXT unchanged
+func needsQuoting(text string) bool {
+	if text != strings.TrimSpace(text) {
+		return true
+	}
+	_, quoted := splitQuotedStrings(text)
+	return quoted
+}
+
+// joinTXT reverses txtValue: a value made of one or more quoted
+// character-strings, as NameSilo returns records created with quotes, is
+// unquoted and joined into one text. Other values are returned as is, so
+// semicolons and inner quotes of plain values are preserved.
+func joinTXT(value string) string {
+	parts, ok := splitQuotedStrings(value)
+	if !ok {
+		return value
+	}
+	return strings.Join(parts, "")
+}
+
+// splitQuotedStrings parses a sequence of whitespace-separated quoted
+// character-strings, unescaping backslash escapes. It reports false if value
+// is not entirely made of quoted strings.
+func splitQuotedStrings(value string) ([]string, bool) {
+	var parts []string
+	s := strings.TrimSpace(value)
+
+	for s != "" {
+		if s[0] != '"' {
+			return nil, false
+		}
+
+		var b strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(s); i++ {
+			c := s[i]
+			if c == '\\' && i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+				continue
+			}
+			if c == '"' {
+				closed = true
+				break
+			}
+			b.WriteByte(c)
+		}
+		if !closed {
+			return nil, false
+		}
+
+		parts = append(parts, b.String())
+		rest := s[i+1:]
+		s = strings.TrimLeft(rest, " \t")
+		if s != "" && len(s) == len(rest) {
+			// Quoted strings must be separated by whitespace
+			return nil, false
+		}
+	}
+
+	return parts, len(parts) > 0
+}
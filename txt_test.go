@@ -0,0 +1,98 @@
+package namesilo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestLongTXTRoundTrip(t *testing.T) {
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8A", 12)
+	if len(dkim) <= maxTXTStringLength {
+		t.Fatal("test value must exceed a single character-string")
+	}
+
+	params := (&Provider{}).recordParams("example.com", libdns.TXT{Name: "sel._domainkey", Text: dkim})
+	value := params["rrvalue"]
+	parts, ok := splitQuotedStrings(value)
+	if !ok || len(parts) != 2 || len(parts[0]) != maxTXTStringLength {
+		t.Fatalf("Expected two quoted character-strings, got %q", value)
+	}
+
+	rec, err := createLibDNSRecord(dnsRecord{ID: "1", Type: "TXT", Host: "sel._domainkey.example.com", Value: value, TTL: 3600})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txt := rec.(namesileoRecord).Record.(libdns.TXT); txt.Text != dkim {
+		t.Errorf("Round trip changed the text:\n got %q\nwant %q", txt.Text, dkim)
+	}
+
+	// Short values are sent unchanged
+	if got := (&Provider{}).recordParams("example.com", libdns.RR{Name: "@", Type: "TXT", Data: "v=spf1 -all"})["rrvalue"]; got != "v=spf1 -all" {
+		t.Errorf("Unexpected short TXT value %q", got)
+	}
+}
+
+func TestSplitQuotedStrings(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+		ok   bool
+	}{
+		{`"abc" "def"`, []string{"abc", "def"}, true},
+		{`"a\"b" "c\\d"`, []string{`a"b`, `c\d`}, true},
+		{`"single"`, []string{"single"}, true},
+		{`plain text`, nil, false},
+		{`"unterminated`, nil, false},
+		{`"a""b"`, nil, false},
+		{`"a" tail`, nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := splitQuotedStrings(tt.in)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitQuotedStrings(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTXTQuotingRoundTrip(t *testing.T) {
+	texts := []string{
+		"v=spf1 include:_spf.example.com ~all",
+		"v=DMARC1; p=reject; rua=mailto:dmarc@example.com",
+		`say "hello"`,
+		`"already quoted"`,
+		`"a" "b"`,
+		"  padded  ",
+		`back\slash`,
+		"",
+	}
+
+	for _, text := range texts {
+		value := (&Provider{}).recordParams("example.com", libdns.TXT{Name: "@", Text: text})["rrvalue"]
+		rec, err := createLibDNSRecord(dnsRecord{ID: "1", Type: "TXT", Host: "example.com", Value: value})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := rec.RR().Data; got != text {
+			t.Errorf("Round trip of %q via %q returned %q", text, value, got)
+		}
+	}
+}
+
+func TestJoinTXTStripsQuotes(t *testing.T) {
+	// Records created in the NameSilo UI may come back quoted
+	tests := map[string]string{
+		`"v=spf1 -all"`:         "v=spf1 -all",
+		`"part one" "part two"`: "part onepart two",
+		`"with \"escapes\""`:    `with "escapes"`,
+		`v=spf1 -all`:           "v=spf1 -all",
+		`semi;colon "quote"`:    `semi;colon "quote"`,
+	}
+	for in, want := range tests {
+		if got := joinTXT(in); got != want {
+			t.Errorf("joinTXT(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
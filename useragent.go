@@ -0,0 +1,51 @@
+package namesilo
+
+import (
+	"net/http"
+	"runtime/debug"
+	"sync"
+)
+
+const modulePath = "github.com/r6c/namesilo"
+
+var (
+	userAgentOnce    sync.Once
+	defaultUserAgent string
+)
+
+// libraryUserAgent returns "libdns-namesilo/<version>", with the module
+// version taken from the build information of the running binary
+func libraryUserAgent() string {
+	userAgentOnce.Do(func() {
+		version := "devel"
+		if info, ok := debug.ReadBuildInfo(); ok {
+			if info.Main.Path == modulePath && info.Main.Version != "" {
+				version = info.Main.Version
+			}
+			for _, dep := range info.Deps {
+				if dep.Path == modulePath && dep.Version != "" {
+					version = dep.Version
+				}
+			}
+		}
+		defaultUserAgent = "libdns-namesilo/" + version
+	})
+	return defaultUserAgent
+}
+
+// userAgent returns the User-Agent sent with API requests
+func (p *Provider) userAgent() string {
+	if ua := p.expandEnv(p.UserAgent); ua != "" {
+		return ua
+	}
+	return libraryUserAgent()
+}
+
+// setHeaders adds the User-Agent and the configured extra headers to an
+// API request
+func (p *Provider) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", p.userAgent())
+	for name, value := range p.Headers {
+		req.Header.Set(name, p.expandEnv(value))
+	}
+}
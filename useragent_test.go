@@ -0,0 +1,54 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDefaultUserAgent(t *testing.T) {
+	var got string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	if _, err := provider.GetRecords(context.Background(), "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	if !strings.HasPrefix(got, "libdns-namesilo/") {
+		t.Errorf("Expected default User-Agent, got %q", got)
+	}
+}
+
+func TestCustomUserAgentAndHeaders(t *testing.T) {
+	var headers []http.Header
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+	provider.UserAgent = "acme-dns-sync/2.1"
+	provider.Headers = map[string]string{"X-Gateway-Tenant": "dns"}
+
+	ctx := context.Background()
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords failed: %v", err)
+	}
+	provider.UsePOST = true
+	if _, err := provider.GetRecords(ctx, "example.com"); err != nil {
+		t.Fatalf("GetRecords with POST failed: %v", err)
+	}
+
+	for i, h := range headers {
+		if h.Get("User-Agent") != "acme-dns-sync/2.1" {
+			t.Errorf("Request %d: expected custom User-Agent, got %q", i+1, h.Get("User-Agent"))
+		}
+		if h.Get("X-Gateway-Tenant") != "dns" {
+			t.Errorf("Request %d: expected extra header, got %q", i+1, h.Get("X-Gateway-Tenant"))
+		}
+	}
+	if len(headers) != 2 || headers[1].Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("Expected POST to keep its form Content-Type, got %v", headers)
+	}
+}
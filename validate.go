@@ -0,0 +1,66 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// accountBalanceResponse represents the response from getAccountBalance
+type accountBalanceResponse struct {
+	apiResponse
+	Balance string `xml:"reply>balance"`
+}
+
+// Validate checks the configuration with a cheap authenticated call that
+// changes nothing, so that a bad setup is reported at startup rather than
+// on the first DNS change. Each configured API key is checked, including
+// those of Accounts, and the error matches ErrInvalidAPIKey for a missing
+// or refused key, ErrIPNotAllowed when the key cannot be used from this IP
+// address, and ErrUnreachable when NameSilo could not be reached.
+func (p *Provider) Validate(ctx context.Context) error {
+	if !p.hasCredentials() {
+		return fmt.Errorf("%w: no API key configured", ErrInvalidAPIKey)
+	}
+	if err := p.checkAccounts(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	}
+	tokens, err := p.tokens(ctx)
+	if err != nil {
+		return err
+	}
+
+	labels := make([]string, len(tokens))
+	for i := range tokens {
+		labels[i] = fmt.Sprintf("API key %d of %d", i+1, len(tokens))
+	}
+	for _, account := range p.Accounts {
+		accountTokens := p.accountTokens(account)
+		for i, token := range accountTokens {
+			tokens = append(tokens, token)
+			labels = append(labels, fmt.Sprintf("API key %d of %d for %s", i+1, len(accountTokens), strings.Join(account.Zones, ", ")))
+		}
+	}
+
+	// Keys are checked one by one, as callAPI would hide a refused key by
+	// failing over to the next
+	for i, token := range tokens {
+		var response accountBalanceResponse
+		info := CallInfo{Operation: "getAccountBalance"}
+		spanCtx, span := p.startSpan(ctx, info.Operation)
+		start := time.Now()
+		err := p.callWithToken(spanCtx, info.Operation, token, nil, &response, &info)
+		info.Duration = time.Since(start)
+		info.Err = err
+		p.observeCall(spanCtx, span, info)
+
+		if err != nil {
+			if len(tokens) > 1 {
+				return fmt.Errorf("%s: %w", labels[i], err)
+			}
+			return err
+		}
+	}
+	return nil
+}
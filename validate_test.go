@@ -0,0 +1,99 @@
+package namesilo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		reply  func(w http.ResponseWriter)
+		target error // nil if Validate must succeed
+	}{
+		{"valid", func(w http.ResponseWriter) {
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><balance>12.50</balance></reply></namesilo>`))
+		}, nil},
+		{"invalid key", func(w http.ResponseWriter) {
+			w.Write([]byte(`<namesilo><reply><code>110</code><detail>Invalid API Key</detail></reply></namesilo>`))
+		}, ErrInvalidAPIKey},
+		{"IP not allowed", func(w http.ResponseWriter) {
+			w.Write([]byte(`<namesilo><reply><code>113</code><detail>API account cannot be accessed from your IP</detail></reply></namesilo>`))
+		}, ErrIPNotAllowed},
+		{"error page", func(w http.ResponseWriter) {
+			w.Write([]byte(`<!DOCTYPE html><html><head><title>Maintenance</title></head></html>`))
+		}, ErrUnreachable},
+		{"HTTP error", func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusBadGateway)
+		}, ErrUnreachable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operation string
+			provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				operation = r.URL.Path
+				tt.reply(w)
+			})
+
+			err := provider.Validate(context.Background())
+			if !strings.HasSuffix(operation, "/getAccountBalance") {
+				t.Errorf("Expected a getAccountBalance call, got %q", operation)
+			}
+			if tt.target == nil {
+				if err != nil {
+					t.Errorf("Validate failed: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.target) {
+				t.Errorf("Expected %v, got %v", tt.target, err)
+			}
+		})
+	}
+}
+
+func TestValidateUnreachable(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		hj, _ := w.(http.Hijacker)
+		conn, _, _ := hj.Hijack()
+		conn.Close()
+	})
+
+	if err := provider.Validate(context.Background()); !errors.Is(err, ErrUnreachable) {
+		t.Errorf("Expected ErrUnreachable for a dropped connection, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := provider.Validate(ctx); err == nil || errors.Is(err, ErrUnreachable) {
+		t.Errorf("Expected a canceled request not to match ErrUnreachable, got %v", err)
+	}
+}
+
+func TestValidateEachKey(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		code := 300
+		if r.URL.Query().Get("key") == "bad" {
+			code = 110
+		}
+		fmt.Fprintf(w, `<namesilo><reply><code>%d</code><detail>x</detail></reply></namesilo>`, code)
+	})
+	provider.APITokens = []string{"bad"}
+
+	err := provider.Validate(context.Background())
+	if !errors.Is(err, ErrInvalidAPIKey) || !strings.Contains(err.Error(), "API key 2 of 2") {
+		t.Errorf("Expected the second key to be reported, got %v", err)
+	}
+	if strings.Contains(err.Error(), "bad") {
+		t.Errorf("Error leaks the API key: %v", err)
+	}
+
+	if err := (&Provider{}).Validate(context.Background()); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("Expected ErrInvalidAPIKey without credentials, got %v", err)
+	}
+}
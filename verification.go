@@ -0,0 +1,88 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RegistrantVerification is the verification state of a registrant email
+// address and the domains that use it.
+type RegistrantVerification struct {
+	Email    string
+	Domains  []string
+	Verified bool
+}
+
+// verificationRecord represents a registrant email from NameSilo API
+type verificationRecord struct {
+	Email    string `xml:"email_address"`
+	Domains  string `xml:"domains"`
+	Verified string `xml:"verified"`
+}
+
+// verificationStatusResponse represents the response from
+// registrantVerificationStatus
+type verificationStatusResponse struct {
+	apiResponse
+	Emails []verificationRecord `xml:"reply>email"`
+}
+
+// RegistrantVerificationStatus lists the registrant email addresses of the
+// account and whether each has been verified. Domains whose registrant email
+// stays unverified are suspended by the registry.
+func (p *Provider) RegistrantVerificationStatus(ctx context.Context) ([]RegistrantVerification, error) {
+	var response verificationStatusResponse
+	if err := p.callAPI(ctx, "registrantVerificationStatus", map[string]string{}, &response); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]RegistrantVerification, 0, len(response.Emails))
+	for _, rec := range response.Emails {
+		status := RegistrantVerification{
+			Email:    rec.Email,
+			Verified: yesNo(rec.Verified),
+		}
+		for _, domain := range strings.Split(rec.Domains, ",") {
+			if domain = strings.TrimSpace(domain); domain != "" {
+				status.Domains = append(status.Domains, domain)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// UnverifiedDomains returns the domains whose registrant email has not been
+// verified yet.
+func (p *Provider) UnverifiedDomains(ctx context.Context) ([]string, error) {
+	statuses, err := p.RegistrantVerificationStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var domains []string
+	for _, status := range statuses {
+		if !status.Verified {
+			domains = append(domains, status.Domains...)
+		}
+	}
+
+	return domains, nil
+}
+
+// SendVerificationEmail asks NameSilo to send a new verification email to the
+// given registrant email address.
+func (p *Provider) SendVerificationEmail(ctx context.Context, email string) error {
+	if !strings.Contains(email, "@") {
+		return fmt.Errorf("invalid email address %q", email)
+	}
+
+	params := map[string]string{
+		"email": email,
+	}
+
+	var response apiResponse
+	return p.callAPI(ctx, "emailVerification", params, &response)
+}
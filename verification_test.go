@@ -0,0 +1,55 @@
+package namesilo
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestRegistrantVerification(t *testing.T) {
+	var sent string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/registrantVerificationStatus":
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<email><email_address>ops@example.com</email_address><domains>example.com, example.net</domains><verified>Yes</verified></email>
+<email><email_address>new@example.org</email_address><domains>example.org</domains><verified>No</verified></email>
+</reply></namesilo>`))
+		case "/api/emailVerification":
+			sent = r.URL.Query().Get("email")
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+		}
+	})
+	ctx := context.Background()
+
+	statuses, err := provider.RegistrantVerificationStatus(ctx)
+	if err != nil {
+		t.Fatalf("RegistrantVerificationStatus failed: %v", err)
+	}
+	want := []RegistrantVerification{
+		{Email: "ops@example.com", Domains: []string{"example.com", "example.net"}, Verified: true},
+		{Email: "new@example.org", Domains: []string{"example.org"}},
+	}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("Unexpected statuses: %+v", statuses)
+	}
+
+	unverified, err := provider.UnverifiedDomains(ctx)
+	if err != nil {
+		t.Fatalf("UnverifiedDomains failed: %v", err)
+	}
+	if !reflect.DeepEqual(unverified, []string{"example.org"}) {
+		t.Errorf("Unexpected unverified domains: %v", unverified)
+	}
+
+	if err := provider.SendVerificationEmail(ctx, "new@example.org"); err != nil {
+		t.Fatalf("SendVerificationEmail failed: %v", err)
+	}
+	if sent != "new@example.org" {
+		t.Errorf("Unexpected email parameter %q", sent)
+	}
+	if err := provider.SendVerificationEmail(ctx, "invalid"); err == nil {
+		t.Error("Expected error for invalid email")
+	}
+}
@@ -0,0 +1,54 @@
+package namesilo
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Reply codes NameSilo uses for a successful operation
+const (
+	codeSuccess            = 300
+	codeSuccessWithWarning = 301
+	codeSuccessPartial     = 302
+)
+
+// Warning describes an operation that NameSilo performed but replied to
+// with a warning (reply code 301 or 302) instead of plain success.
+type Warning struct {
+	Operation string // API operation, e.g. "dnsAddRecord"
+	Domain    string // Domain the operation targeted, if any
+	Code      int    // NameSilo reply code
+	Detail    string // NameSilo reply detail, describing the warning
+}
+
+func (w Warning) String() string {
+	if w.Domain == "" {
+		return fmt.Sprintf("namesilo: %s succeeded with warning: code %d - %s", w.Operation, w.Code, w.Detail)
+	}
+	return fmt.Sprintf("namesilo: %s succeeded with warning for %q: code %d - %s", w.Operation, w.Domain, w.Code, w.Detail)
+}
+
+// isSuccessCode reports whether a reply code means the operation was
+// performed
+func isSuccessCode(code int) bool {
+	return code == codeSuccess || code == codeSuccessWithWarning || code == codeSuccessPartial
+}
+
+// warn reports a success-with-warning reply to OnWarning and the Logger
+func (p *Provider) warn(ctx context.Context, w Warning) {
+	if p.OnWarning != nil {
+		p.OnWarning(w)
+	}
+	if p.Logger != nil {
+		attrs := []slog.Attr{
+			slog.String("operation", w.Operation),
+			slog.Int("reply_code", w.Code),
+			slog.String("detail", w.Detail),
+		}
+		if w.Domain != "" {
+			attrs = append(attrs, slog.String("zone", w.Domain))
+		}
+		p.Logger.LogAttrs(ctx, slog.LevelWarn, "namesilo API call succeeded with warning", attrs...)
+	}
+}
@@ -0,0 +1,51 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/libdns/libdns"
+)
+
+func TestSuccessWithWarning(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>301</code><detail>success, but the TTL was adjusted</detail><record_id>abc</record_id></reply></namesilo>`))
+	})
+	var warnings []Warning
+	provider.OnWarning = func(w Warning) { warnings = append(warnings, w) }
+	var logs bytes.Buffer
+	provider.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	added, err := provider.AppendRecords(context.Background(), "example.com", []libdns.Record{
+		libdns.TXT{Name: "www", Text: "hello"},
+	})
+	if err != nil {
+		t.Fatalf("Expected a 301 reply to succeed, got %v", err)
+	}
+	if id, _ := RecordID(added[0]); id != "abc" {
+		t.Errorf("Expected record ID abc, got %q", id)
+	}
+
+	want := Warning{Operation: "dnsAddRecord", Domain: "example.com", Code: 301, Detail: "success, but the TTL was adjusted"}
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Fatalf("Expected warning %+v, got %+v", want, warnings)
+	}
+	if !strings.Contains(warnings[0].String(), "TTL was adjusted") {
+		t.Errorf("Unexpected warning text %q", warnings[0].String())
+	}
+	if !strings.Contains(logs.String(), "level=WARN") {
+		t.Errorf("Expected the warning to be logged, got %q", logs.String())
+	}
+}
+
+func TestSuccessCodes(t *testing.T) {
+	for code, want := range map[int]bool{300: true, 301: true, 302: true, 280: false, 303: false} {
+		if got := isSuccessCode(code); got != want {
+			t.Errorf("isSuccessCode(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+const (
+	defaultWebhookTimeout   = 5 * time.Second
+	defaultWebhookQueueSize = 100
+)
+
+// errWebhookQueueFull is reported for notifications dropped because the
+// queue is full
+var errWebhookQueueFull = errors.New("notification queue is full")
+
+// WebhookPayload is the JSON body a WebhookNotifier posts for a change.
+type WebhookPayload struct {
+	Time      time.Time       `json:"time"`
+	Zone      string          `json:"zone"`
+	Operation SyncAction      `json:"operation"`
+	Actor     string          `json:"actor,omitempty"`
+	Record    *SnapshotRecord `json:"record,omitempty"`   // record after the change; nil for deletes
+	Previous  *SnapshotRecord `json:"previous,omitempty"` // record before the change, if known
+}
+
+// WebhookNotifier is an Auditor that POSTs a WebhookPayload as JSON to URL
+// for every successful record change, e.g. for a chat or ops channel.
+// Failed changes are not posted.
+//
+// Notifications are queued and delivered in order by a background
+// goroutine, so that a slow webhook does not delay record changes. Use
+// Flush to wait for the queued notifications, e.g. before a program exits.
+// A WebhookNotifier must not be copied after first use.
+type WebhookNotifier struct {
+	URL string
+
+	// HTTPClient is used for the requests. If nil, a client with a
+	// 5 second timeout is used.
+	HTTPClient *http.Client
+
+	// OnError, if set, is called when a notification cannot be delivered
+	// or is dropped because the queue is full. Delivery failures never
+	// fail the record change itself.
+	OnError func(error)
+
+	// QueueSize is the number of notifications that may wait for delivery;
+	// further notifications are dropped. Defaults to 100.
+	QueueSize int
+
+	mu      sync.Mutex
+	queue   chan webhookJob
+	running bool           // a goroutine is delivering the queue
+	pending sync.WaitGroup // notifications queued and not yet delivered
+	client  *http.Client   // default client, created once
+}
+
+// webhookJob is a queued notification
+type webhookJob struct {
+	ctx   context.Context
+	event AuditEvent
+}
+
+// Audit queues the event for delivery to the webhook URL.
+func (n *WebhookNotifier) Audit(ctx context.Context, event AuditEvent) {
+	if event.Err != nil || n.URL == "" {
+		return
+	}
+
+	// The change has been made, so deliver even if the caller gives up
+	job := webhookJob{ctx: context.WithoutCancel(ctx), event: event}
+
+	n.mu.Lock()
+	if n.queue == nil {
+		size := n.QueueSize
+		if size <= 0 {
+			size = defaultWebhookQueueSize
+		}
+		n.queue = make(chan webhookJob, size)
+	}
+	select {
+	case n.queue <- job:
+		n.pending.Add(1)
+	default:
+		n.mu.Unlock()
+		n.report(event, errWebhookQueueFull)
+		return
+	}
+	if !n.running {
+		n.running = true
+		go n.deliver()
+	}
+	n.mu.Unlock()
+}
+
+// Flush waits until the notifications queued so far have been delivered or
+// have failed.
+func (n *WebhookNotifier) Flush() {
+	n.pending.Wait()
+}
+
+// deliver posts queued notifications until the queue is empty
+func (n *WebhookNotifier) deliver() {
+	for {
+		n.mu.Lock()
+		select {
+		case job := <-n.queue:
+			n.mu.Unlock()
+			n.report(job.event, n.post(job.ctx, job.event))
+			n.pending.Done()
+		default:
+			n.running = false
+			n.mu.Unlock()
+			return
+		}
+	}
+}
+
+// report passes a delivery error to OnError
+func (n *WebhookNotifier) report(event AuditEvent, err error) {
+	if err != nil && n.OnError != nil {
+		n.OnError(fmt.Errorf("webhook notification for %s in %s: %w", event.Action, event.Zone, err))
+	}
+}
+
+// httpClient returns the HTTPClient or the default client
+func (n *WebhookNotifier) httpClient() *http.Client {
+	if n.HTTPClient != nil {
+		return n.HTTPClient
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client == nil {
+		n.client = &http.Client{Timeout: defaultWebhookTimeout}
+	}
+	return n.client
+}
+
+// post delivers a single notification
+func (n *WebhookNotifier) post(ctx context.Context, event AuditEvent) error {
+	payload := WebhookPayload{
+		Time:      event.Time.UTC(),
+		Zone:      event.Zone,
+		Operation: event.Action,
+		Actor:     event.Actor,
+		Record:    webhookRecord(event.After, event.RecordID),
+		Previous:  webhookRecord(event.Before, ""),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", libraryUserAgent())
+
+	resp, err := n.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// webhookRecord converts a record for a WebhookPayload, or returns nil
+func webhookRecord(record libdns.Record, id string) *SnapshotRecord {
+	if record == nil {
+		return nil
+	}
+	rec := snapshotRecord(record)
+	if id != "" {
+		rec.ID = id
+	}
+	return &rec
+}
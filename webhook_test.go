@@ -0,0 +1,147 @@
+package namesilo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestWebhookURL(t *testing.T) {
+	var mu sync.Mutex
+	var payloads []WebhookPayload
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected webhook request %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Invalid payload: %v", err)
+		}
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com", namesilotest.Record{Type: "A", Host: "www", Value: "192.0.2.1", TTL: 3600})
+
+	t.Setenv("NAMESILO_TEST_HOOK", hook.URL)
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client(), WebhookURL: "{env.NAMESILO_TEST_HOOK}"}
+	ctx := WithActor(context.Background(), "ci")
+
+	if _, err := provider.SetRecords(ctx, "example.com", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+	}); err != nil {
+		t.Fatalf("SetRecords failed: %v", err)
+	}
+	// Failed changes are not posted
+	provider.AppendRecords(ctx, "example.com", []libdns.Record{
+		libdns.RR{Name: "www", Type: "A", Data: "192.0.2.2", TTL: time.Hour},
+	})
+	provider.FlushWebhook()
+
+	if len(payloads) != 1 {
+		t.Fatalf("Expected 1 notification, got %+v", payloads)
+	}
+	got := payloads[0]
+	if got.Zone != "example.com" || got.Operation != SyncUpdate || got.Actor != "ci" || got.Time.IsZero() {
+		t.Errorf("Unexpected payload: %+v", got)
+	}
+	if got.Record == nil || got.Record.Data != "192.0.2.2" || got.Record.TTL != 3600 || got.Record.ID == "" {
+		t.Errorf("Unexpected record: %+v", got.Record)
+	}
+	if got.Previous == nil || got.Previous.Data != "192.0.2.1" {
+		t.Errorf("Unexpected previous record: %+v", got.Previous)
+	}
+}
+
+func TestWebhookNotifierErrors(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer hook.Close()
+
+	var errs []error
+	notifier := &WebhookNotifier{URL: hook.URL, OnError: func(err error) { errs = append(errs, err) }}
+	notifier.Audit(context.Background(), AuditEvent{
+		Time:   time.Now(),
+		Zone:   "example.com",
+		Action: SyncDelete,
+		Before: libdns.TXT{Name: "old", Text: "x"},
+	})
+	notifier.Flush()
+
+	if len(errs) != 1 {
+		t.Errorf("Expected a delivery error, got %v", errs)
+	}
+}
+
+func TestWebhookNotifierQueue(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var zones []string
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		<-release
+		mu.Lock()
+		zones = append(zones, payload.Zone)
+		mu.Unlock()
+	}))
+	defer hook.Close()
+
+	var errs []error
+	notifier := &WebhookNotifier{URL: hook.URL, QueueSize: 2, OnError: func(err error) { errs = append(errs, err) }}
+	event := func(zone string) AuditEvent {
+		return AuditEvent{Time: time.Now(), Zone: zone, Action: SyncCreate, After: libdns.TXT{Name: "x", Text: "x"}}
+	}
+
+	// A slow webhook does not delay Audit; the first notification is being
+	// delivered, two are queued and the fourth is dropped
+	start := time.Now()
+	notifier.Audit(context.Background(), event("a.com"))
+	for len(notifier.queue) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for _, zone := range []string{"b.com", "c.com", "d.com"} {
+		notifier.Audit(context.Background(), event(zone))
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Audit blocked for %v", elapsed)
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], errWebhookQueueFull) {
+		t.Errorf("Expected the fourth notification to be dropped, got %v", errs)
+	}
+
+	close(release)
+	notifier.Flush()
+	if got := strings.Join(zones, " "); got != "a.com b.com c.com" {
+		t.Errorf("Expected the queued notifications in order, got %s", got)
+	}
+}
+
+func TestWebhookURLSharesNotifier(t *testing.T) {
+	provider := &Provider{WebhookURL: "https://hooks.example.com/dns"}
+	first := provider.webhookNotifier()
+	if provider.webhookNotifier() != first {
+		t.Error("Expected the notifier, and its HTTP client, to be reused")
+	}
+	if first.httpClient() != first.httpClient() {
+		t.Error("Expected the default HTTP client to be created once")
+	}
+	provider.WebhookURL = "https://hooks.example.com/other"
+	if provider.webhookNotifier() == first {
+		t.Error("Expected a new notifier for a changed WebhookURL")
+	}
+}
@@ -0,0 +1,71 @@
+package namesilo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libdns/libdns"
+	"github.com/r6c/namesilo/namesilotest"
+)
+
+func TestNormalizeWildcardNames(t *testing.T) {
+	tests := map[string]string{
+		"*":                  "*",
+		"*.sub":              "*.sub",
+		"*.example.com":      "*",
+		"*.example.com.":     "*",
+		"*.sub.example.com.": "*.sub",
+		"www.Example.COM.":   "www",
+		"example.com.":       "@",
+		"other.org.":         "other.org",
+	}
+	for name, want := range tests {
+		if got := normalizeRecordName(name, "example.com."); got != want {
+			t.Errorf("normalizeRecordName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestValidateWildcardNames(t *testing.T) {
+	for _, name := range []string{"*", "*.sub", "*.example.com.", "www", "@"} {
+		if err := validateRecordName(name); err != nil {
+			t.Errorf("Unexpected error for %q: %v", name, err)
+		}
+	}
+	for _, name := range []string{"*www", "w*", "sub.*", "a.*.b", "**"} {
+		if err := validateRecordName(name); err == nil {
+			t.Errorf("Expected error for %q", name)
+		}
+	}
+}
+
+func TestWildcardRecords(t *testing.T) {
+	srv := namesilotest.NewServer()
+	defer srv.Close()
+	srv.AddZone("example.com")
+	provider := &Provider{APIToken: "test", HTTPClient: srv.Client()}
+	ctx := context.Background()
+
+	_, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{
+		libdns.RR{Name: "*", Type: "A", Data: "192.0.2.1"},
+		libdns.CNAME{Name: "*.apps", Target: "ingress.example.net"},
+		libdns.TXT{Name: "*.example.com.", Text: "wildcard"},
+	})
+	if err != nil {
+		t.Fatalf("AppendRecords failed: %v", err)
+	}
+
+	hosts := map[string]bool{}
+	for _, rec := range srv.Records("example.com") {
+		hosts[rec.Type+" "+rec.Host] = true
+	}
+	for _, want := range []string{"A *.example.com", "CNAME *.apps.example.com", "TXT *.example.com"} {
+		if !hosts[want] {
+			t.Errorf("Missing record %s in %v", want, hosts)
+		}
+	}
+
+	if _, err := provider.AppendRecords(ctx, "example.com.", []libdns.Record{libdns.RR{Name: "sub.*", Type: "A", Data: "192.0.2.1"}}); err == nil {
+		t.Error("Expected an error for an invalid wildcard")
+	}
+}
@@ -0,0 +1,459 @@
+package namesilo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+// maxTXTStringLength is the maximum length of a single TXT character-string
+const maxTXTStringLength = 255
+
+// ExportZone writes the records of the zone to w as an RFC 1035 master file
+// (BIND-style zone file) with $ORIGIN set to the zone.
+func (p *Provider) ExportZone(ctx context.Context, zone string, w io.Writer) error {
+	records, err := p.GetRecords(ctx, zone)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve records: %w", err)
+	}
+
+	return writeZoneFile(w, zone, records)
+}
+
+// writeZoneFile renders records as a master file for zone
+func writeZoneFile(w io.Writer, zone string, records []libdns.Record) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "$ORIGIN %s\n", fqdn(zone))
+	fmt.Fprintf(bw, "$TTL %d\n", defaultTTL)
+
+	for _, rec := range records {
+		rr := rec.RR()
+		recordType := apiRecordType(rec)
+		fmt.Fprintf(bw, "%s\t%d\tIN\t%s\t%s\n",
+			normalizeRecordName(strings.TrimSuffix(rr.Name, "."), zone),
+			int(rr.TTL.Seconds()),
+			recordType,
+			zoneFileData(recordType, rr),
+		)
+	}
+
+	return bw.Flush()
+}
+
+// zoneFileData formats the RDATA of a record in master file syntax, fully
+// qualifying host names and quoting TXT strings
+func zoneFileData(recordType string, rr libdns.RR) string {
+	fields := strings.Fields(rr.Data)
+
+	switch recordType {
+	case "CNAME", "NS", aliasType:
+		return fqdn(rr.Data)
+	case "MX":
+		if len(fields) == 2 {
+			return fields[0] + " " + fqdn(fields[1])
+		}
+	case "SRV":
+		if len(fields) == 4 {
+			return strings.Join(fields[:3], " ") + " " + fqdn(fields[3])
+		}
+	case "TXT":
+		return quoteTXT(rr.Data)
+	}
+
+	return rr.Data
+}
+
+// quoteTXT renders a TXT value as one or more quoted character-strings of
+// at most 255 bytes each, escaping quotes and backslashes
+func quoteTXT(text string) string {
+	var parts []string
+	for len(text) > maxTXTStringLength {
+		parts = append(parts, text[:maxTXTStringLength])
+		text = text[maxTXTStringLength:]
+	}
+	parts = append(parts, text)
+
+	for i, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		part = strings.ReplaceAll(part, `"`, `\"`)
+		parts[i] = `"` + part + `"`
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// fqdn returns name with a trailing dot
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
+
+// ImportResult reports the outcome of importing one record of a zone file.
+type ImportResult struct {
+	Line    int           // Line of the zone file the record starts on
+	Record  libdns.Record // Record parsed from the zone file
+	Skipped bool          // Record is managed by NameSilo (SOA, apex NS) and was not imported
+	Err     error         // Error creating the record, if any
+}
+
+// ImportZone parses an RFC 1035 master file from r and creates its records
+// in the zone. Relative names are interpreted against $ORIGIN, which
+// defaults to the zone. SOA and apex NS records are skipped because
+// NameSilo manages them.
+//
+// A parse error, a record outside the zone, an invalid record or a TTL
+// rejected by TTLPolicy or TTLSnap aborts the import before any record is
+// created. Errors creating individual records are reported in the results
+// and do not stop the import.
+func (p *Provider) ImportZone(ctx context.Context, zone string, r io.Reader) ([]ImportResult, error) {
+	entries, err := parseZoneFile(r, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every record is converted, validated and checked against the TTL
+	// policy before the first one is created, so that a bad record aborts
+	// the import without a partial zone
+	results := make([]ImportResult, 0, len(entries))
+	for _, entry := range entries {
+		rec, err := parseRR(entry.rr)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+		skipped := entry.rr.Type == "SOA" || (entry.rr.Type == "NS" && entry.rr.Name == "@")
+		if !skipped {
+			if err := p.validateRecords([]libdns.Record{rec}); err != nil {
+				return nil, fmt.Errorf("line %d: %w", entry.line, err)
+			}
+		}
+		results = append(results, ImportResult{
+			Line:    entry.line,
+			Record:  rec,
+			Skipped: skipped,
+		})
+	}
+
+	for i := range results {
+		if results[i].Skipped {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results[:i], err
+		}
+		if _, err := p.AppendRecords(ctx, zone, []libdns.Record{results[i].Record}); err != nil {
+			results[i].Err = err
+		}
+	}
+
+	return results, nil
+}
+
+// parseRR converts a generic RR into its typed record, including the
+// NameSilo-specific ALIAS and TLSA types
+func parseRR(rr libdns.RR) (libdns.Record, error) {
+	switch rr.Type {
+	case aliasType:
+		return NewAlias(rr.Name, rr.Data, rr.TTL), nil
+	case "TLSA":
+		return parseTLSA(rr.Name, rr.TTL, rr.Data)
+	}
+	return rr.Parse()
+}
+
+// zoneFileEntry is a resource record parsed from a master file
+type zoneFileEntry struct {
+	line int
+	rr   libdns.RR
+}
+
+// zoneFileToken is a whitespace-delimited field of a master file
+type zoneFileToken struct {
+	text   string
+	quoted bool
+}
+
+// zoneFileLine is a logical master file line, with parentheses resolved
+type zoneFileLine struct {
+	num        int
+	blankOwner bool
+	tokens     []zoneFileToken
+}
+
+// parseZoneFile parses the resource records of a master file for zone
+func parseZoneFile(r io.Reader, zone string) ([]zoneFileEntry, error) {
+	lines, err := tokenizeZoneFile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zoneFQDN := strings.ToLower(fqdn(zone))
+	origin := zoneFQDN
+	defaultRecordTTL := time.Duration(defaultTTL) * time.Second
+	lastOwner := "@"
+
+	var entries []zoneFileEntry
+	for _, line := range lines {
+		tokens := line.tokens
+
+		if !tokens[0].quoted && strings.HasPrefix(tokens[0].text, "$") {
+			switch strings.ToUpper(tokens[0].text) {
+			case "$ORIGIN":
+				if len(tokens) < 2 {
+					return nil, fmt.Errorf("line %d: $ORIGIN requires a domain name", line.num)
+				}
+				origin = strings.ToLower(qualifyName(tokens[1].text, origin))
+			case "$TTL":
+				if len(tokens) < 2 {
+					return nil, fmt.Errorf("line %d: $TTL requires a value", line.num)
+				}
+				ttl, err := parseZoneFileTTL(tokens[1].text)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line.num, err)
+				}
+				defaultRecordTTL = ttl
+			default:
+				return nil, fmt.Errorf("line %d: unsupported directive %s", line.num, tokens[0].text)
+			}
+			continue
+		}
+
+		owner := lastOwner
+		if !line.blankOwner {
+			name := strings.ToLower(qualifyName(tokens[0].text, origin))
+			if name != zoneFQDN && !hasZoneSuffix(name, zoneFQDN) {
+				return nil, fmt.Errorf("line %d: owner %s is outside zone %s", line.num, name, zoneFQDN)
+			}
+			owner = libdns.RelativeName(name, zoneFQDN)
+			tokens = tokens[1:]
+		}
+		lastOwner = owner
+
+		ttl := defaultRecordTTL
+		for len(tokens) > 0 {
+			text := strings.ToUpper(tokens[0].text)
+			if text == "IN" || text == "CH" || text == "HS" || text == "CS" {
+				tokens = tokens[1:]
+				continue
+			}
+			if parsed, err := parseZoneFileTTL(text); err == nil && text[0] >= '0' && text[0] <= '9' {
+				ttl = parsed
+				tokens = tokens[1:]
+				continue
+			}
+			break
+		}
+
+		if len(tokens) == 0 {
+			return nil, fmt.Errorf("line %d: missing record type", line.num)
+		}
+
+		recordType := strings.ToUpper(tokens[0].text)
+		data, err := zoneFileRData(recordType, tokens[1:], origin)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line.num, err)
+		}
+
+		entries = append(entries, zoneFileEntry{
+			line: line.num,
+			rr: libdns.RR{
+				Name: owner,
+				Type: recordType,
+				TTL:  ttl,
+				Data: data,
+			},
+		})
+	}
+
+	return entries, nil
+}
+
+// zoneFileRData converts the RDATA fields of a record to libdns RR data,
+// resolving relative host names against origin
+func zoneFileRData(recordType string, tokens []zoneFileToken, origin string) (string, error) {
+	fields := make([]string, len(tokens))
+	for i, tok := range tokens {
+		fields[i] = tok.text
+	}
+
+	// host returns a host name field without the trailing dot, as NameSilo stores it
+	host := func(name string) string {
+		return strings.TrimSuffix(qualifyName(name, origin), ".")
+	}
+
+	want := map[string]int{"CNAME": 1, "NS": 1, aliasType: 1, "MX": 2, "SRV": 4, "CAA": 3}
+	if n, ok := want[recordType]; ok && len(fields) != n {
+		return "", fmt.Errorf("%s record expects %d fields, got %d", recordType, n, len(fields))
+	}
+
+	switch recordType {
+	case "CNAME", "NS", aliasType:
+		return host(fields[0]), nil
+	case "MX":
+		return fields[0] + " " + host(fields[1]), nil
+	case "SRV":
+		return strings.Join(fields[:3], " ") + " " + host(fields[3]), nil
+	case "CAA":
+		return fmt.Sprintf("%s %s %q", fields[0], fields[1], fields[2]), nil
+	case "TXT":
+		// Multiple character-strings form a single TXT value
+		return strings.Join(fields, ""), nil
+	}
+
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s record has no data", recordType)
+	}
+	return strings.Join(fields, " "), nil
+}
+
+// qualifyName resolves a master file name against origin
+func qualifyName(name, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + origin
+	}
+}
+
+// parseZoneFileTTL parses a TTL in seconds or BIND unit syntax (e.g. "1h30m")
+func parseZoneFileTTL(s string) (time.Duration, error) {
+	if seconds, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	var total time.Duration
+	num := ""
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= '0' && c <= '9' {
+			num += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || num == "" {
+			return 0, fmt.Errorf("invalid TTL %q", s)
+		}
+		n, _ := strconv.Atoi(num)
+		total += time.Duration(n) * unit
+		num = ""
+	}
+	if num != "" || total == 0 {
+		return 0, fmt.Errorf("invalid TTL %q", s)
+	}
+
+	return total, nil
+}
+
+// tokenizeZoneFile splits a master file into logical lines of tokens,
+// handling comments, quoted strings and parenthesized continuation lines
+func tokenizeZoneFile(r io.Reader) ([]zoneFileLine, error) {
+	var lines []zoneFileLine
+	var current zoneFileLine
+	depth := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	num := 0
+
+	for scanner.Scan() {
+		num++
+		text := scanner.Text()
+
+		if depth == 0 {
+			current = zoneFileLine{
+				num:        num,
+				blankOwner: len(text) > 0 && (text[0] == ' ' || text[0] == '\t'),
+			}
+		}
+
+		var tok strings.Builder
+		inToken, inQuotes := false, false
+		flush := func(quoted bool) {
+			if inToken || quoted {
+				current.tokens = append(current.tokens, zoneFileToken{text: tok.String(), quoted: quoted})
+			}
+			tok.Reset()
+			inToken = false
+		}
+
+	scan:
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+
+			if inQuotes {
+				switch c {
+				case '\\':
+					if i+1 < len(text) {
+						i++
+						tok.WriteByte(text[i])
+					}
+				case '"':
+					inQuotes = false
+					flush(true)
+				default:
+					tok.WriteByte(c)
+				}
+				continue
+			}
+
+			switch c {
+			case ';':
+				break scan
+			case '"':
+				flush(false)
+				inQuotes = true
+			case '(':
+				flush(false)
+				depth++
+			case ')':
+				flush(false)
+				if depth == 0 {
+					return nil, fmt.Errorf("line %d: unbalanced parenthesis", num)
+				}
+				depth--
+			case ' ', '\t':
+				flush(false)
+			default:
+				tok.WriteByte(c)
+				inToken = true
+			}
+		}
+
+		if inQuotes {
+			return nil, fmt.Errorf("line %d: unterminated quoted string", num)
+		}
+		flush(false)
+
+		if depth == 0 && len(current.tokens) > 0 {
+			lines = append(lines, current)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read zone file: %w", err)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced parenthesis", current.num)
+	}
+
+	return lines, nil
+}
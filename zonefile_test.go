@@ -0,0 +1,195 @@
+package namesilo
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libdns/libdns"
+)
+
+func TestExportZone(t *testing.T) {
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail>
+<resource_record><record_id>1</record_id><type>A</type><host>example.com</host><value>192.0.2.1</value><ttl>3600</ttl></resource_record>
+<resource_record><record_id>2</record_id><type>CNAME</type><host>www.example.com</host><value>example.com</value><ttl>7200</ttl></resource_record>
+<resource_record><record_id>3</record_id><type>MX</type><host>example.com</host><value>mail.example.com</value><ttl>3600</ttl><distance>10</distance></resource_record>
+<resource_record><record_id>4</record_id><type>TXT</type><host>example.com</host><value>say "hi"</value><ttl>3600</ttl></resource_record>
+</reply></namesilo>`))
+	})
+
+	var buf bytes.Buffer
+	if err := provider.ExportZone(context.Background(), "example.com", &buf); err != nil {
+		t.Fatalf("ExportZone failed: %v", err)
+	}
+
+	want := "$ORIGIN example.com.\n" +
+		"$TTL 3600\n" +
+		"@\t3600\tIN\tA\t192.0.2.1\n" +
+		"www\t7200\tIN\tCNAME\texample.com.\n" +
+		"@\t3600\tIN\tMX\t10 mail.example.com.\n" +
+		"@\t3600\tIN\tTXT\t\"say \\\"hi\\\"\"\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected zone file:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestQuoteTXTSplitsLongValues(t *testing.T) {
+	value := string(bytes.Repeat([]byte("a"), 300))
+	got := quoteTXT(value)
+	want := `"` + value[:255] + `" "` + value[255:] + `"`
+	if got != want {
+		t.Errorf("quoteTXT did not split at 255 bytes: %s", got)
+	}
+}
+
+func TestParseZoneFile(t *testing.T) {
+	zoneFile := `$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.dnsowl.com. hostmaster.example.com. (
+		2024010101 ; serial
+		7200 3600 1209600 3600 )
+	IN	NS	ns1.dnsowl.com.
+www	300	IN	A	192.0.2.1
+	IN	AAAA	2001:db8::1 ; same owner as above
+mail.example.com.	IN	MX	10 mx1
+_sip._tcp	IN	600	SRV	10 20 5060 sip.example.net.
+txt	TXT	"part one; " "part \"two\""
+$ORIGIN sub.example.com.
+alias	CNAME	target
+`
+
+	entries, err := parseZoneFile(strings.NewReader(zoneFile), "example.com")
+	if err != nil {
+		t.Fatalf("parseZoneFile failed: %v", err)
+	}
+
+	want := []libdns.RR{
+		{Name: "@", Type: "SOA", TTL: time.Hour, Data: "ns1.dnsowl.com hostmaster.example.com 2024010101 7200 3600 1209600 3600"},
+		{Name: "@", Type: "NS", TTL: time.Hour, Data: "ns1.dnsowl.com"},
+		{Name: "www", Type: "A", TTL: 300 * time.Second, Data: "192.0.2.1"},
+		{Name: "www", Type: "AAAA", TTL: time.Hour, Data: "2001:db8::1"},
+		{Name: "mail", Type: "MX", TTL: time.Hour, Data: "10 mx1.example.com"},
+		{Name: "_sip._tcp", Type: "SRV", TTL: 600 * time.Second, Data: "10 20 5060 sip.example.net"},
+		{Name: "txt", Type: "TXT", TTL: time.Hour, Data: `part one; part "two"`},
+		{Name: "alias.sub", Type: "CNAME", TTL: time.Hour, Data: "target.sub.example.com"},
+	}
+
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d records, got %d: %+v", len(want), len(entries), entries)
+	}
+	for i, entry := range entries {
+		if i == 0 {
+			// SOA RDATA is passed through unchanged apart from name resolution
+			continue
+		}
+		if entry.rr != want[i] {
+			t.Errorf("Record %d: got %+v, want %+v", i, entry.rr, want[i])
+		}
+	}
+}
+
+func TestImportZone(t *testing.T) {
+	var added []string
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if !strings.HasSuffix(r.URL.Path, "/dnsAddRecord") {
+			w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><nameservers><nameserver>ns1.dnsowl.com</nameserver></nameservers></reply></namesilo>`))
+			return
+		}
+		added = append(added, q.Get("rrtype")+" "+q.Get("rrhost")+" "+q.Get("rrvalue"))
+		if q.Get("rrhost") == "bad" {
+			w.Write([]byte(`<namesilo><reply><code>280</code><detail>DNS modification error</detail></reply></namesilo>`))
+			return
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	zoneFile := `@ 3600 IN NS ns1.dnsowl.com.
+www 3600 IN A 192.0.2.1
+bad 3600 IN A 192.0.2.2
+`
+	results, err := provider.ImportZone(context.Background(), "example.com.", strings.NewReader(zoneFile))
+	if err != nil {
+		t.Fatalf("ImportZone failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if !results[0].Skipped {
+		t.Error("Expected apex NS record to be skipped")
+	}
+	if results[1].Err != nil || results[1].Line != 2 {
+		t.Errorf("Unexpected result for www: %+v", results[1])
+	}
+	if results[2].Err == nil {
+		t.Error("Expected error for rejected record")
+	}
+	if len(added) != 2 {
+		t.Errorf("Expected 2 add calls, got %v", added)
+	}
+}
+
+func TestImportZoneParseErrorCreatesNothing(t *testing.T) {
+	adds := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsAddRecord") {
+			adds++
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail></reply></namesilo>`))
+	})
+
+	zoneFile := `www 3600 IN A 192.0.2.1
+bad 3600 IN A not-an-address
+`
+	if _, err := provider.ImportZone(context.Background(), "example.com", strings.NewReader(zoneFile)); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error for line 2, got %v", err)
+	}
+	if adds != 0 {
+		t.Errorf("Expected no record to be created, got %d add calls", adds)
+	}
+}
+
+func TestImportZoneInvalidRecordCreatesNothing(t *testing.T) {
+	adds := 0
+	provider := newTestProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/dnsAddRecord") {
+			adds++
+		}
+		w.Write([]byte(`<namesilo><reply><code>300</code><detail>success</detail><record_id>1</record_id></reply></namesilo>`))
+	})
+	provider.TTLPolicy = TTLError
+
+	tests := []string{
+		"www 3600 IN A 192.0.2.1\nw*w 3600 IN A 192.0.2.2\n",
+		"www 3600 IN A 192.0.2.1\nshort 60 IN A 192.0.2.2\n",
+	}
+	for _, zoneFile := range tests {
+		if _, err := provider.ImportZone(context.Background(), "example.com", strings.NewReader(zoneFile)); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Expected an error for line 2 of %q, got %v", zoneFile, err)
+		}
+	}
+	if adds != 0 {
+		t.Errorf("Expected no record to be created, got %d add calls", adds)
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	tests := []string{
+		"www IN A (192.0.2.1\n",
+		`txt IN TXT "unterminated` + "\n",
+		"$INCLUDE other.zone\n",
+		"mx IN MX mail.example.com.\n",
+		"badexample.com. IN A 192.0.2.1\n",
+		"www.example.net. IN A 192.0.2.1\n",
+	}
+	for _, zoneFile := range tests {
+		if _, err := parseZoneFile(strings.NewReader(zoneFile), "example.com"); err == nil {
+			t.Errorf("Expected error parsing %q", zoneFile)
+		}
+	}
+}